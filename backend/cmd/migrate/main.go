@@ -0,0 +1,19 @@
+// backend/cmd/migrate/main.go
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/cli"
+)
+
+func main() {
+	if err := cli.Migrate("migrate", os.Args[1:]); err != nil {
+		if cli.IsUsage(err) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
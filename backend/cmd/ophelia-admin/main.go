@@ -0,0 +1,350 @@
+// backend/cmd/ophelia-admin/main.go
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/config"
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const usage = `Usage: ophelia-admin <command> [flags]
+
+Commands:
+  keys create   Issue an API key; the secret is printed once
+  keys list     List API keys
+  keys revoke   Revoke an API key
+  keys usage    Report API key usage per day and route
+  config print  Print the effective configuration, secrets redacted
+
+Run "ophelia-admin keys <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 3 || (os.Args[1] != "keys" && os.Args[1] != "config") {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found: %v", err)
+	}
+
+	if os.Args[1] == "config" {
+		if os.Args[2] != "print" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[2], usage)
+			os.Exit(2)
+		}
+		printConfig()
+		return
+	}
+
+	commands := map[string]func(*repository.RepositoryManager, []string) error{
+		"create": createKey,
+		"list":   listKeys,
+		"revoke": revokeKey,
+		"usage":  reportUsage,
+	}
+	command, ok := commands[os.Args[2]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[2], usage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fatalf("failed to load configuration: %v", err)
+	}
+
+	logger := utils.GetLogger()
+	logger.SetLevel(logrus.WarnLevel)
+
+	// Key management only needs Postgres
+	dbManager, err := database.NewManager(&database.Config{
+		DatabaseURL:  cfg.Database.URL,
+		CacheBackend: database.CacheBackendNone,
+		LogLevel:     cfg.Logging.Level,
+	}, logger)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	defer dbManager.Close()
+
+	if err := command(repository.NewRepositoryManager(dbManager.DB), os.Args[3:]); err != nil {
+		dbManager.Close()
+		fatalf("%v", err)
+	}
+}
+
+// printConfig prints the configuration the server would run with, from the
+// same defaults, config file and environment
+func printConfig() {
+	cfg, err := config.Load()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "ophelia-admin: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func createKey(repos *repository.RepositoryManager, args []string) error {
+	fs := flag.NewFlagSet("keys create", flag.ExitOnError)
+	name := fs.String("name", "", "Unique key name, e.g. the team or tool using it (required)")
+	owner := fs.String("owner", "", "Contact for the key, used for billing")
+	scopes := fs.String("scopes", models.ScopeSearch, "Comma separated scopes: "+strings.Join(models.ValidScopes, ", "))
+	rate := fs.Int("rate", 60, "Requests per minute, 0 for unlimited")
+	daily := fs.Int("daily", 10000, "Quota units per UTC day, 0 for unlimited")
+	fs.Parse(args)
+
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+
+	var keyScopes models.StringArray
+	for _, scope := range strings.Split(*scopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !models.IsValidScope(scope) {
+			return fmt.Errorf("invalid scope %q: want one of %s", scope, strings.Join(models.ValidScopes, ", "))
+		}
+		keyScopes = append(keyScopes, scope)
+	}
+	if len(keyScopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+
+	secret, err := utils.GenerateAPIKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	key := &models.APIKey{
+		Name:          *name,
+		Owner:         *owner,
+		Prefix:        utils.APIKeyDisplayPrefix(secret),
+		KeyHash:       utils.HashAPIKey(secret),
+		Scopes:        keyScopes,
+		RatePerMinute: *rate,
+		DailyQuota:    *daily,
+	}
+	if _, err := repos.APIKey.GetByName(*name); err == nil {
+		return fmt.Errorf("a key named %q already exists", *name)
+	}
+	if err := repos.APIKey.Create(context.Background(), key); err != nil {
+		return fmt.Errorf("failed to create key: %w", err)
+	}
+
+	fmt.Printf("Created API key %q with scopes %s\n", key.Name, strings.Join(key.Scopes, ","))
+	fmt.Printf("Secret (shown only once): %s\n", secret)
+	return nil
+}
+
+func listKeys(repos *repository.RepositoryManager, args []string) error {
+	fs := flag.NewFlagSet("keys list", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print JSON")
+	all := fs.Bool("all", false, "Include revoked keys")
+	fs.Parse(args)
+
+	keys, err := repos.APIKey.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to list keys: %w", err)
+	}
+	if !*all {
+		active := keys[:0]
+		for _, key := range keys {
+			if key.RevokedAt == nil {
+				active = append(active, key)
+			}
+		}
+		keys = active
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(keys)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPREFIX\tSCOPES\tRATE/MIN\tDAILY\tOWNER\tLAST USED\tSTATUS")
+	for _, key := range keys {
+		status := "active"
+		if key.RevokedAt != nil {
+			status = "revoked " + key.RevokedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.Name, key.Prefix, strings.Join(key.Scopes, ","),
+			limitString(key.RatePerMinute), limitString(key.DailyQuota),
+			key.Owner, timeString(key.LastUsedAt), status)
+	}
+	return w.Flush()
+}
+
+func revokeKey(repos *repository.RepositoryManager, args []string) error {
+	fs := flag.NewFlagSet("keys revoke", flag.ExitOnError)
+	name := fs.String("name", "", "Name of the key to revoke (required)")
+	fs.Parse(args)
+
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+
+	key, err := repos.APIKey.GetByName(*name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("no key named %q", *name)
+	}
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		fmt.Printf("API key %q was already revoked on %s\n", key.Name, key.RevokedAt.Format(time.RFC3339))
+		return nil
+	}
+	if err := repos.APIKey.Revoke(context.Background(), key.ID); err != nil {
+		return fmt.Errorf("failed to revoke key: %w", err)
+	}
+
+	fmt.Printf("Revoked API key %q; servers stop accepting it once their key cache expires\n", key.Name)
+	return nil
+}
+
+func reportUsage(repos *repository.RepositoryManager, args []string) error {
+	fs := flag.NewFlagSet("keys usage", flag.ExitOnError)
+	name := fs.String("name", "", "Only this key")
+	from := fs.String("from", "", "First day, YYYY-MM-DD (default 30 days ago)")
+	to := fs.String("to", "", "Last day, YYYY-MM-DD (default today)")
+	format := fs.String("format", "text", "Output format: text or csv")
+	fs.Parse(args)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	start, err := parseDay(*from, today.AddDate(0, 0, -30))
+	if err != nil {
+		return err
+	}
+	end, err := parseDay(*to, today)
+	if err != nil {
+		return err
+	}
+
+	keys, err := repos.APIKey.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to list keys: %w", err)
+	}
+	names := make(map[uint]string, len(keys))
+	for _, key := range keys {
+		names[key.ID] = key.Name
+	}
+
+	var rows []models.APIKeyUsage
+	if *name != "" {
+		key, err := repos.APIKey.GetByName(*name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("no key named %q", *name)
+		}
+		if err != nil {
+			return err
+		}
+		rows, err = repos.APIKeyUsage.GetByKey(key.ID, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to read usage: %w", err)
+		}
+	} else {
+		rows, err = repos.APIKeyUsage.GetAll(start, end)
+		if err != nil {
+			return fmt.Errorf("failed to read usage: %w", err)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if names[rows[i].APIKeyID] != names[rows[j].APIKeyID] {
+			return names[rows[i].APIKeyID] < names[rows[j].APIKeyID]
+		}
+		return rows[i].Day.Before(rows[j].Day)
+	})
+
+	switch *format {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"key", "day", "route", "requests", "units", "errors"})
+		for _, row := range rows {
+			w.Write([]string{
+				names[row.APIKeyID], row.Day.Format("2006-01-02"), row.Route,
+				strconv.Itoa(row.Requests), strconv.Itoa(row.Units), strconv.Itoa(row.Errors),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tDAY\tROUTE\tREQUESTS\tUNITS\tERRORS")
+		totals := make(map[string]int)
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\n", names[row.APIKeyID], row.Day.Format("2006-01-02"),
+				row.Route, row.Requests, row.Units, row.Errors)
+			totals[names[row.APIKeyID]] += row.Units
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("\nUnits %s to %s:\n", start.Format("2006-01-02"), end.Format("2006-01-02"))
+		for _, key := range keys {
+			if units, ok := totals[key.Name]; ok {
+				fmt.Printf("  %s: %d\n", key.Name, units)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q: want text or csv", *format)
+	}
+}
+
+func parseDay(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid day %q: want YYYY-MM-DD", value)
+	}
+	return day, nil
+}
+
+func limitString(limit int) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(limit)
+}
+
+func timeString(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format("2006-01-02 15:04")
+}
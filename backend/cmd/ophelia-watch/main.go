@@ -0,0 +1,128 @@
+// backend/cmd/ophelia-watch/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/client"
+	"github.com/Ayash-Bera/ophelia/backend/internal/logwatch"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// Command line flags
+	serverURL      = flag.String("server", envOr("OPHELIA_SERVER", "http://localhost:8080"), "Ophelia server URL")
+	apiKey         = flag.String("api-key", os.Getenv("OPHELIA_API_KEY"), "API key sent with requests")
+	format         = flag.String("format", "auto", "Log format: auto, plain or export (journalctl -o export)")
+	statePath      = flag.String("state", "", "State file (default $XDG_STATE_HOME/ophelia/watch-state.json)")
+	reportPath     = flag.String("report", "", "Report file, Markdown or .json (default next to the state file)")
+	fromStart      = flag.Bool("from-start", false, "Read files seen for the first time from the beginning")
+	once           = flag.Bool("once", false, "Process what the files hold now, look it up and exit")
+	pollInterval   = flag.Duration("poll", 2*time.Second, "How often files are checked for new lines")
+	lookupInterval = flag.Duration("lookup-interval", 10*time.Second, "Minimum time between searches")
+	relookup       = flag.Duration("relookup", 7*24*time.Hour, "Search a known error again after this long")
+	limit          = flag.Int("limit", 3, "Results kept per error (1-10)")
+	verbose        = flag.Bool("verbose", false, "Enable verbose logging")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: ophelia-watch [flags] file...\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Watches log files for new errors and looks them up in the Arch Wiki.\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "For the journal, follow an export file, e.g.\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  journalctl -f -o export > journal.export & ophelia-watch journal.export\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger := utils.GetLogger()
+	if *verbose {
+		logger.SetLevel(logrus.DebugLevel)
+	}
+
+	logFormat, err := logwatch.ParseFormat(*format)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid format")
+	}
+	if *limit < 1 || *limit > 10 {
+		logger.Fatal("limit must be between 1 and 10")
+	}
+
+	state := *statePath
+	if state == "" {
+		state = filepath.Join(stateDir(), "watch-state.json")
+	}
+	report := *reportPath
+	if report == "" {
+		report = filepath.Join(filepath.Dir(state), "report.md")
+	}
+
+	paths := make([]string, 0, flag.NArg())
+	for _, path := range flag.Args() {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			logger.WithError(err).WithField("file", path).Fatal("Invalid path")
+		}
+		paths = append(paths, abs)
+	}
+
+	watcher, err := logwatch.New(logwatch.Config{
+		Paths:          paths,
+		Format:         logFormat,
+		FromStart:      *fromStart,
+		StatePath:      state,
+		ReportPath:     report,
+		PollInterval:   *pollInterval,
+		LookupInterval: *lookupInterval,
+		Relookup:       *relookup,
+		Limit:          *limit,
+	}, client.New(*serverURL, *apiKey, "ophelia-watch/1.0", 30*time.Second), logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to start log watcher")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		err = watcher.Once(ctx)
+	} else {
+		err = watcher.Run(ctx)
+	}
+	if err != nil {
+		logger.WithError(err).Fatal("Log watcher failed")
+	}
+
+	logger.WithField("report", report).Info("Report written")
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// stateDir follows the XDG base directory spec for state files
+func stateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "ophelia")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "state", "ophelia")
+	}
+	return "."
+}
@@ -0,0 +1,312 @@
+// backend/cmd/ophelia/main.go
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/client"
+	"github.com/Ayash-Bera/ophelia/backend/internal/logwatch"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/seeder"
+	"github.com/spf13/viper"
+)
+
+// maxQueryLength matches the server's limit on query length
+const maxQueryLength = 2000
+
+// maxInput bounds how much of stdin is read
+const maxInput = 1 << 20
+
+var (
+	// Command line flags; each may also come from the config file or an
+	// OPHELIA_* environment variable
+	configPath = flag.String("config", "", "Config file (default $XDG_CONFIG_HOME/ophelia/config.yaml)")
+	serverURL  = flag.String("server", "http://localhost:8080", "Ophelia server URL")
+	apiKey     = flag.String("api-key", "", "API key sent with requests")
+	limit      = flag.Int("limit", 5, "Maximum number of results (1-10)")
+	output     = flag.String("output", "text", "Output format: text or json")
+	summary    = flag.Bool("summary", false, "Include a solution summary")
+	timeout    = flag.Duration("timeout", 30*time.Second, "Request timeout")
+)
+
+var whitespaceRun = regexp.MustCompile(`\s+`)
+
+type settings struct {
+	Server  string
+	APIKey  string
+	Limit   int
+	Output  string
+	Summary bool
+	Timeout time.Duration
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: ophelia [flags] [query...]\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Searches the Arch Wiki for an error. Without a query argument the\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "query is read from stdin, e.g. journalctl -b -p err | ophelia\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg, err := loadSettings()
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	query, err := readQuery(flag.Args(), os.Stdin)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if query == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	api := client.New(cfg.Server, cfg.APIKey, "ophelia-cli/1.0", cfg.Timeout)
+	response, err := api.Search(ctx, models.SearchRequest{
+		Query:     query,
+		Limit:     cfg.Limit,
+		Summarize: cfg.Summary,
+	})
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	switch cfg.Output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(response); err != nil {
+			fatalf("failed to write output: %v", err)
+		}
+	default:
+		printText(os.Stdout, response)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "ophelia: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+// loadSettings merges flags, environment and config file, in that order of
+// precedence, over the flag defaults
+func loadSettings() (settings, error) {
+	v := viper.New()
+	v.SetEnvPrefix("ophelia")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	flag.VisitAll(func(f *flag.Flag) {
+		v.SetDefault(f.Name, f.DefValue)
+	})
+
+	path := *configPath
+	if path == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "ophelia", "config.yaml")
+		}
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			// Only a config file asked for by name has to exist
+			var notFound *os.PathError
+			if *configPath != "" || !errors.As(err, &notFound) {
+				return settings{}, fmt.Errorf("failed to read config %s: %w", path, err)
+			}
+		}
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		v.Set(f.Name, f.Value.String())
+	})
+
+	cfg := settings{
+		Server:  strings.TrimRight(v.GetString("server"), "/"),
+		APIKey:  v.GetString("api-key"),
+		Limit:   v.GetInt("limit"),
+		Output:  v.GetString("output"),
+		Summary: v.GetBool("summary"),
+		Timeout: v.GetDuration("timeout"),
+	}
+
+	if cfg.Output != "text" && cfg.Output != "json" {
+		return settings{}, fmt.Errorf("unknown output format %q: want text or json", cfg.Output)
+	}
+	if cfg.Limit < 1 || cfg.Limit > 10 {
+		return settings{}, fmt.Errorf("limit must be between 1 and 10")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return cfg, nil
+}
+
+// readQuery joins the arguments, or reads stdin when there are none and it
+// is not a terminal
+func readQuery(args []string, stdin *os.File) (string, error) {
+	if len(args) > 0 {
+		return truncateQuery(strings.TrimSpace(strings.Join(args, " "))), nil
+	}
+
+	info, err := stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(stdin, maxInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return cleanLog(string(data)), nil
+}
+
+// cleanLog strips timestamps, hosts and PIDs from log lines and drops
+// repeats, so the distinct messages fit in one query
+func cleanLog(text string) string {
+	var lines []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxInput)
+	for scanner.Scan() {
+		if logwatch.IsMarker(scanner.Text()) {
+			continue
+		}
+		line := logwatch.CleanLine(scanner.Text())
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		lines = append(lines, line)
+	}
+	return truncateQuery(strings.Join(lines, "\n"))
+}
+
+// truncateQuery keeps whole lines up to the server's length limit
+func truncateQuery(query string) string {
+	if len(query) <= maxQueryLength {
+		return query
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(query, "\n") {
+		if b.Len()+len(line)+1 > maxQueryLength {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	if b.Len() == 0 {
+		// A single overlong line is cut at the limit, on a rune boundary:
+		// a split rune would be sent as U+FFFD and could push the query
+		// back over the limit
+		end := maxQueryLength
+		for end > 0 && !utf8.RuneStart(query[end]) {
+			end--
+		}
+		return query[:end]
+	}
+	return b.String()
+}
+
+// printText writes results for reading in a terminal
+func printText(w io.Writer, response *models.SearchResponse) {
+	if len(response.Results) == 0 {
+		fmt.Fprintln(w, "No results found.")
+		return
+	}
+
+	if s := response.Summary; s != nil && len(s.Steps) > 0 {
+		fmt.Fprintln(w, "Suggested steps:")
+		for i, step := range s.Steps {
+			if step.Text != "" {
+				fmt.Fprintf(w, "  %d. %s\n", i+1, step.Text)
+			} else {
+				fmt.Fprintf(w, "  %d.\n", i+1)
+			}
+			for _, command := range step.Commands {
+				fmt.Fprintf(w, "       $ %s\n", command)
+			}
+			for _, warning := range step.Warnings {
+				printWarning(w, "       ", warning)
+			}
+			if step.Source < len(s.Sources) {
+				fmt.Fprintf(w, "       (%s)\n", s.Sources[step.Source].URL)
+			}
+		}
+		if len(s.Files) > 0 {
+			paths := make([]string, 0, len(s.Files))
+			for _, file := range s.Files {
+				paths = append(paths, file.Path)
+			}
+			fmt.Fprintf(w, "  Files: %s\n", strings.Join(paths, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+
+	processor := seeder.NewContentProcessor()
+	for i, result := range response.Results {
+		fmt.Fprintf(w, "%d. %s [%s, %.0f%%]\n", i+1, strings.ReplaceAll(result.Title, "_", " "), result.Relevance, result.Score*100)
+		fmt.Fprintf(w, "   %s\n", result.URL)
+		if snippet := snippet(result.Content, 240); snippet != "" {
+			fmt.Fprintf(w, "   %s\n", snippet)
+		}
+
+		commands := processor.ExtractCommandExamples(result.Content)
+		if len(commands) > 3 {
+			commands = commands[:3]
+		}
+		for _, command := range commands {
+			fmt.Fprintf(w, "   $ %s\n", command)
+		}
+		for _, warning := range result.Warnings {
+			printWarning(w, "   ", warning)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d results in %dms (%s)\n", response.Total, response.ResponseTime, response.Freshness)
+}
+
+func printWarning(w io.Writer, indent string, warning models.CommandWarning) {
+	line := fmt.Sprintf("%s! %s: %s", indent, strings.ReplaceAll(warning.Risk, "_", " "), warning.Command)
+	if len(warning.Reasons) > 0 {
+		line += " (" + strings.Join(warning.Reasons, ", ") + ")"
+	}
+	fmt.Fprintln(w, line)
+	if warning.WikiWarning != "" {
+		fmt.Fprintf(w, "%s  Wiki warning: %s\n", indent, warning.WikiWarning)
+	}
+}
+
+// snippet collapses whitespace and cuts text at a word boundary
+func snippet(text string, max int) string {
+	text = strings.TrimSpace(whitespaceRun.ReplaceAllString(text, " "))
+	if len(text) <= max {
+		return text
+	}
+	cut := strings.LastIndex(text[:max], " ")
+	if cut <= 0 {
+		cut = max
+	}
+	return text[:cut] + "..."
+}
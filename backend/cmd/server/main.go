@@ -3,6 +3,8 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -13,20 +15,67 @@ import (
 	"github.com/Ayash-Bera/ophelia/backend/internal/alchemyst"
 	// "github.com/Ayash-Bera/ophelia/backend/internal/api/handlers"
 	"github.com/Ayash-Bera/ophelia/backend/internal/api/handlers"
+	"github.com/Ayash-Bera/ophelia/backend/internal/cli"
 	"github.com/Ayash-Bera/ophelia/backend/internal/config"
 	"github.com/Ayash-Bera/ophelia/backend/internal/database"
 	"github.com/Ayash-Bera/ophelia/backend/internal/health"
 	"github.com/Ayash-Bera/ophelia/backend/internal/middleware"
 	"github.com/Ayash-Bera/ophelia/backend/internal/migration"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/privacy"
 	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
 
+	"github.com/Ayash-Bera/ophelia/backend/internal/seeder"
 	"github.com/Ayash-Bera/ophelia/backend/internal/services"
+	"github.com/Ayash-Bera/ophelia/backend/internal/suggest"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"github.com/Ayash-Bera/ophelia/backend/migrations"
 	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 )
 
+const usage = `Usage: %s [command] [flags]
+
+Commands:
+  serve    Run the API server (the default)
+  migrate  Show, apply or roll back schema migrations, or check drift
+  seed     Crawl the Arch Wiki pages into the index
+
+Run "%s <command> -h" for the flags of migrate and seed.
+`
+
 func main() {
+	command, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+
+	var err error
+	switch command {
+	case "serve":
+		serve()
+	case "migrate":
+		err = cli.Migrate(os.Args[0]+" migrate", args)
+	case "seed":
+		err = cli.Seed(os.Args[0]+" seed", args)
+	default:
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+
+	if cli.IsUsage(err) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", command, err)
+		os.Exit(1)
+	}
+}
+
+// serve runs the API server until it receives SIGINT or SIGTERM
+func serve() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
 		log.Printf("No .env file found: %v", err)
@@ -42,11 +91,43 @@ func main() {
 		logger.WithError(err).Fatal("Failed to load configuration")
 	}
 
+	// Configure log level and redaction before anything logs request data
+	applyLogging(logger, cfg)
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		FilePath:    cfg.Tracing.FilePath,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	}, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize tracing")
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.WithError(err).Error("Failed to flush traces")
+		}
+	}()
+
 	// Initialize database
 	dbConfig := &database.Config{
-		DatabaseURL: cfg.Database.URL,
-		RedisURL:    cfg.Redis.URL,
-		LogLevel:    os.Getenv("LOG_LEVEL"),
+		DatabaseURL:     cfg.Database.URL,
+		RedisURL:        cfg.Redis.URL,
+		CacheBackend:    cfg.Cache.Backend,
+		CacheMaxEntries: cfg.Cache.MaxEntries,
+		LogLevel:        cfg.Logging.Level,
+
+		MaxOpenConns:       cfg.Database.MaxOpenConns,
+		MaxIdleConns:       cfg.Database.MaxIdleConns,
+		ConnMaxLifetime:    cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime:    cfg.Database.ConnMaxIdleTime,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+		Redis:              redisOptions(cfg),
 	}
 
 	dbManager, err := database.NewManager(dbConfig, logger)
@@ -60,8 +141,8 @@ func main() {
 	}()
 
 	// Run migrations
-	migrationRunner := migration.NewRunner(dbManager, logger)
-	if err := migrationRunner.RunMigrations("migrations"); err != nil {
+	migrationRunner := migration.NewRunner(dbManager, migrations.Source(cfg.Database.MigrationsDir), logger)
+	if err := migrationRunner.RunMigrations(context.Background()); err != nil {
 		logger.WithError(err).Fatal("Failed to run database migrations")
 	}
 
@@ -69,20 +150,80 @@ func main() {
 	repoManager := repository.NewRepositoryManager(dbManager.DB)
 
 	// Initialize Alchemyst client and service
-	alchemystClient := alchemyst.NewClient(cfg.Alchemyst.BaseURL, cfg.Alchemyst.APIKey, logger)
+	alchemystClient := alchemyst.NewClient(alchemystConfig(cfg), logger)
 	alchemystService := alchemyst.NewService(alchemystClient, logger)
+	alchemystService.SetThresholds(similarityThresholds(cfg))
 
 	// Initialize services
 	searchService := services.NewSearchService(alchemystService, repoManager, logger)
 
 	// Initialize cache
-	cache := database.NewCache(dbManager.Redis, logger)
+	cache := database.NewCache(dbManager.Cache, cfg.Cache.Namespace, logger)
+	searchCache := database.NewSearchCache(cache, searchCacheConfig(cfg), logger)
+
+	// Initialize privacy controls
+	anonymizer := privacy.NewAnonymizer(privacy.Config{
+		IPMode:         cfg.Privacy.IPMode,
+		IPHashSalt:     cfg.Privacy.IPHashSalt,
+		StoreUserAgent: cfg.Privacy.StoreUserAgent,
+		ScrubText:      cfg.Privacy.ScrubText,
+	})
+
+	// Background jobs stop when the server shuts down
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	retentionJob := privacy.NewRetentionJob(repoManager, privacy.RetentionConfig{
+		SearchQueryDays: cfg.Privacy.SearchQueryDays,
+		FeedbackDays:    cfg.Privacy.FeedbackDays,
+		Mode:            cfg.Privacy.RetentionMode,
+		Interval:        cfg.Privacy.RetentionInterval,
+	}, logger)
+	go retentionJob.Start(bgCtx)
+
+	// Warm the search cache with popular queries after content changes. With
+	// no cache backend warming would only spend upstream quota.
+	if cfg.Warmer.Enabled && cache.Backend().Name() != database.CacheBackendNone {
+		warmer := services.NewCacheWarmer(searchService, searchCache, cache, repoManager.PopularQuery, services.WarmerConfig{
+			TopN:         cfg.Warmer.TopN,
+			Rate:         cfg.Warmer.Rate,
+			PollInterval: cfg.Warmer.PollInterval,
+			OnStart:      cfg.Warmer.OnStart,
+		}, logger)
+		go warmer.Start(bgCtx)
+	}
+
+	// Initialize the suggestion index from popular queries and wiki content
+	suggestOpts := suggest.DefaultOptions()
+	suggestOpts.MaxQueries = cfg.Suggestions.MaxEntries
+	suggestionIndex := suggest.NewIndex(suggestOpts)
+	go suggestionIndex.Refresh(bgCtx,
+		suggest.Combine(
+			suggest.PopularQueryLoader(repoManager.PopularQuery, cfg.Suggestions.MaxEntries),
+			suggest.ContentLoader(repoManager.ContentMetadata),
+		),
+		cfg.Suggestions.RefreshInterval, logger)
 
 	// Initialize handlers
-	searchHandler := handlers.NewSearchHandler(searchService, repoManager, cache, logger)
+	searchHandler := handlers.NewSearchHandler(searchService, repoManager, searchCache, anonymizer, suggestionIndex, searchConfig(cfg), logger)
+	privacyHandler := handlers.NewPrivacyHandler(repoManager, logger)
+	adminHandler := handlers.NewAdminHandler(cache, logger)
+	crawler := seeder.NewCrawler(alchemystService, repoManager, cache, seeder.CrawlerConfig{}, logger)
+	contentHandler := handlers.NewContentHandler(repoManager, crawler, cache, searchService, logger)
+
+	// API keys: scopes decide what a key may call, limits how much
+	apiKeys := middleware.NewDBAPIKeys(repoManager.APIKey, cfg.API.KeyCacheTTL)
+	scopes := middleware.NewScopePolicy(cfg.API.AnonymousScopes)
+	usageRecorder := services.NewUsageRecorder(repoManager.APIKey, repoManager.APIKeyUsage, cfg.API.UsageFlushInterval, logger)
+	go usageRecorder.Start(bgCtx)
+	// Rate limits and daily key quotas share one store, Redis when configured
+	bucketStore := newBucketStore(cfg, dbManager, logger)
+	keyLimiter := middleware.NewKeyLimiter(bucketStore, usageRecorder, logger)
+
+	batchHandler := handlers.NewBatchHandler(searchService, searchCache, keyLimiter, batchConfig(cfg), logger)
 
 	// Initialize health checker
-	healthChecker := health.NewHealthChecker(dbManager, repoManager.SystemHealth, logger, cfg.Alchemyst.BaseURL)
+	healthChecker := health.NewHealthChecker(dbManager, cache, repoManager.SystemHealth, logger, cfg.Alchemyst.BaseURL, cfg.Health.UpstreamTimeout)
 
 	// Set up Gin router
 	if gin.Mode() == gin.ReleaseMode {
@@ -91,37 +232,52 @@ func main() {
 
 	router := gin.New()
 
+	// Only believe forwarding headers from our own proxies, or anyone could
+	// pick the IP they are rate limited and logged under
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.WithError(err).Fatal("Invalid trusted proxy configuration")
+	}
+	router.RemoteIPHeaders = cfg.Server.RemoteIPHeaders
+
 	// Global middleware
-	router.Use(gin.Logger())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger(logger))
 	router.Use(gin.Recovery())
 	router.Use(middleware.SecurityHeaders())
-	router.Use(middleware.RequestID())
-
-	// Rate limiting
-	rateLimiter := middleware.NewRateLimiter(100) // 100 requests per minute
-	router.Use(rateLimiter.RateLimit())
-
-	// CORS middleware
-	router.Use(func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-		if origin != "" {
-			c.Header("Access-Control-Allow-Origin", origin)
-			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID")
-			c.Header("Access-Control-Allow-Credentials", "true")
-		}
+	router.Use(middleware.Tracing())
+
+	// CORS runs before limits and authentication so browsers can read their
+	// errors, and preflights are answered without spending rate limit
+	corsMiddleware, err := middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           cfg.CORS.MaxAge,
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid CORS configuration")
+	}
+	router.Use(corsMiddleware)
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Rate limiting: a global per-IP cap before authentication, then
+	// per-route policies for anonymous clients and each key's own rate
+	limits, err := rateLimits(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid rate limit configuration")
+	}
+	rateLimiter := middleware.NewRateLimiter(bucketStore, limits, logger)
+	router.Use(rateLimiter.Global())
 
-		c.Next()
-	})
+	// API key authentication and per-key limits; anonymous requests pass
+	router.Use(middleware.APIKeyAuth(apiKeys))
+	router.Use(rateLimiter.RateLimit())
+	router.Use(keyLimiter.Limit())
 
 	// Health check endpoints
 	router.GET("/health", func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), cfg.Health.Timeout)
 		defer cancel()
 
 		// Try to get cached health first
@@ -132,8 +288,9 @@ func main() {
 			health = &fullHealth
 		}
 
+		// A degraded cache slows searches down but does not stop them
 		status := http.StatusOK
-		if health.Status != "healthy" {
+		if health.Status == "unhealthy" {
 			status = http.StatusServiceUnavailable
 		}
 
@@ -143,7 +300,7 @@ func main() {
 	router.GET("/health/detailed", func(c *gin.Context) {
 		health := healthChecker.CheckAll()
 		status := http.StatusOK
-		if health.Status != "healthy" {
+		if health.Status == "unhealthy" {
 			status = http.StatusServiceUnavailable
 		}
 		c.JSON(status, health)
@@ -153,40 +310,65 @@ func main() {
 	v1 := router.Group("/api/v1")
 	{
 		// Search endpoints
-		v1.POST("/search", searchHandler.HandleSearch)
-		v1.POST("/feedback", searchHandler.HandleFeedback)
-		v1.GET("/suggestions", searchHandler.HandleSearchSuggestions)
+		v1.POST("/search", scopes.Require(models.ScopeSearch), searchHandler.HandleSearch)
+		v1.GET("/search/stream", scopes.Require(models.ScopeSearch), searchHandler.HandleSearchStream)
+		v1.POST("/feedback", scopes.Require(models.ScopeFeedback), searchHandler.HandleFeedback)
+		v1.GET("/suggestions", scopes.Require(models.ScopeSearch), searchHandler.HandleSearchSuggestions)
 
 		// Analytics endpoints (basic)
-		v1.GET("/analytics", func(c *gin.Context) {
-			// Simple analytics endpoint
-			recentQueries, err := repoManager.SearchQuery.GetRecentSearches(10)
-			if err != nil {
-				logger.WithError(err).Error("Failed to get recent searches")
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve analytics"})
-				return
-			}
+		v1.GET("/analytics", scopes.Require(models.ScopeAnalytics), privacyHandler.HandleAnalytics)
 
-			c.JSON(http.StatusOK, gin.H{
-				"recent_queries": recentQueries,
-				"server_time":    time.Now(),
-			})
-		})
+		// Privacy endpoints
+		v1.DELETE("/sessions/:session_id", privacyHandler.HandleDeleteSessionData)
+
+		// Batch search always needs a key, whose quota it draws on
+		v1.POST("/search/batch", scopes.RequireKey(models.ScopeSearch), batchHandler.HandleBatchSearch)
+
+		// Admin endpoints
+		admin := v1.Group("/admin", scopes.RequireKey(models.ScopeAdmin))
+		{
+			admin.GET("/cache/stats", adminHandler.HandleCacheStats)
+			admin.POST("/cache/invalidate", adminHandler.HandleCacheInvalidate)
+
+			// Runtime counters (cache outcomes, warming runs, memory stats);
+			// they include the command line, so only admins see them
+			admin.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+
+			admin.GET("/pages", contentHandler.HandleListPages)
+			admin.GET("/pages/:id/sections", contentHandler.HandleListSections)
+			admin.POST("/pages/:id/recrawl", contentHandler.HandleRecrawl)
+			admin.POST("/pages/:id/deactivate", contentHandler.HandleDeactivate)
+			admin.DELETE("/pages/:id", contentHandler.HandleDelete)
+		}
 	}
 
+	// Rate limits, TTLs, thresholds and logging follow the config file;
+	// everything else needs a restart
+	reloader := config.NewReloader(cfg, logger)
+	reloader.OnReload(func(cfg *config.Config) {
+		applyLogging(logger, cfg)
+		alchemystService.SetThresholds(similarityThresholds(cfg))
+		searchCache.SetConfig(searchCacheConfig(cfg))
+		searchHandler.SetConfig(searchConfig(cfg))
+		batchHandler.SetConfig(batchConfig(cfg))
+		if limits, err := rateLimits(cfg); err != nil {
+			logger.WithError(err).Error("Invalid rate limits, keeping the current ones")
+		} else {
+			rateLimiter.SetLimits(limits)
+		}
+	})
+	go reloader.Start(bgCtx)
+
 	// Start server
 	port := cfg.Server.Port
-	if port == "" {
-		port = "8080"
-	}
 
 	server := &http.Server{
 		Addr:           ":" + port,
 		Handler:        router,
-		ReadTimeout:    30 * time.Second,
-		WriteTimeout:   30 * time.Second,
-		IdleTimeout:    120 * time.Second,
-		MaxHeaderBytes: 1 << 20, // 1 MB
+		ReadTimeout:    cfg.Server.ReadTimeout,
+		WriteTimeout:   cfg.Server.WriteTimeout,
+		IdleTimeout:    cfg.Server.IdleTimeout,
+		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
 	}
 
 	// Start server in a goroutine
@@ -203,14 +385,160 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopBackground()
 
-	// Give outstanding requests 30 seconds to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Give outstanding requests time to complete
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		logger.WithError(err).Fatal("Server forced to shutdown")
 	}
 
+	// Requests are done; write the API key usage they left behind
+	usageRecorder.Flush(ctx)
+
 	logger.Info("Server exited gracefully")
 }
+
+// ratePolicy is a rate limit policy as configured
+type ratePolicy struct {
+	name     string
+	identity string
+	spec     string
+}
+
+// ratePolicies parses configured policies, leaving out those turned off
+func ratePolicies(specs ...ratePolicy) ([]middleware.Policy, error) {
+	var policies []middleware.Policy
+	for _, spec := range specs {
+		rate, ok, err := config.ParseRate(spec.spec)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit %s: %w", spec.name, err)
+		}
+		if ok {
+			policies = append(policies, middleware.Policy{
+				Name:     spec.name,
+				Identity: spec.identity,
+				Limit:    rate.Limit,
+				Period:   rate.Period,
+				Burst:    rate.Burst,
+			})
+		}
+	}
+	return policies, nil
+}
+
+// rateLimits builds the rate limit policies of every route
+func rateLimits(cfg *config.Config) (middleware.Limits, error) {
+	limits := middleware.Limits{Routes: make(map[string][]middleware.Policy)}
+	var err error
+
+	if limits.Global, err = ratePolicies(ratePolicy{"global", middleware.IdentityIP, cfg.RateLimit.Global}); err != nil {
+		return limits, err
+	}
+	if limits.Default, err = ratePolicies(ratePolicy{"default", middleware.IdentityIP, cfg.RateLimit.Default}); err != nil {
+		return limits, err
+	}
+
+	routes := map[string][]ratePolicy{
+		"/api/v1/search": {
+			{"search_ip", middleware.IdentityIP, cfg.RateLimit.SearchIP},
+			{"search_session", middleware.IdentitySession, cfg.RateLimit.SearchSession},
+		},
+		"/api/v1/search/stream": {
+			{"search_ip", middleware.IdentityIP, cfg.RateLimit.SearchIP},
+			{"search_session", middleware.IdentitySession, cfg.RateLimit.SearchSession},
+		},
+		"/api/v1/suggestions": {{"suggestions_ip", middleware.IdentityIP, cfg.RateLimit.SuggestionsIP}},
+		"/api/v1/feedback":    {{"feedback_ip", middleware.IdentityIP, cfg.RateLimit.FeedbackIP}},
+	}
+	for path, specs := range routes {
+		if limits.Routes[path], err = ratePolicies(specs...); err != nil {
+			return limits, err
+		}
+	}
+	return limits, nil
+}
+
+// applyLogging sets the log level and redaction
+func applyLogging(logger *logrus.Logger, cfg *config.Config) {
+	if level, err := logrus.ParseLevel(cfg.Logging.Level); err == nil {
+		logger.SetLevel(level)
+	}
+	utils.SetRedaction(cfg.Logging.RedactQueries, cfg.Logging.RedactIPs)
+}
+
+func redisOptions(cfg *config.Config) database.RedisOptions {
+	return database.RedisOptions{
+		PoolSize:     cfg.Redis.PoolSize,
+		MinIdleConns: cfg.Redis.MinIdleConns,
+		MaxConnAge:   cfg.Redis.MaxConnAge,
+		IdleTimeout:  cfg.Redis.IdleTimeout,
+	}
+}
+
+func alchemystConfig(cfg *config.Config) alchemyst.ClientConfig {
+	return alchemyst.ClientConfig{
+		BaseURL: cfg.Alchemyst.BaseURL,
+		APIKey:  cfg.Alchemyst.APIKey,
+		Timeout: cfg.Alchemyst.Timeout,
+		Retry: alchemyst.RetryConfig{
+			MaxRetries: cfg.Alchemyst.MaxRetries,
+			BaseDelay:  cfg.Alchemyst.RetryBaseDelay,
+			MaxDelay:   cfg.Alchemyst.RetryMaxDelay,
+		},
+	}
+}
+
+func similarityThresholds(cfg *config.Config) alchemyst.Thresholds {
+	return alchemyst.Thresholds{
+		Similarity: cfg.Alchemyst.SimilarityThreshold,
+		Minimum:    cfg.Alchemyst.MinSimilarityThreshold,
+	}
+}
+
+func searchCacheConfig(cfg *config.Config) database.SearchCacheConfig {
+	return database.SearchCacheConfig{
+		SoftTTL:        cfg.Cache.SearchSoftTTL,
+		HardTTL:        cfg.Cache.SearchHardTTL,
+		NegativeTTL:    cfg.Cache.NegativeTTL,
+		RefreshTimeout: cfg.Cache.RefreshTimeout,
+	}
+}
+
+func searchConfig(cfg *config.Config) handlers.SearchConfig {
+	return handlers.SearchConfig{
+		Timeout:       cfg.Search.Timeout,
+		StreamTimeout: cfg.Search.StreamTimeout,
+	}
+}
+
+func batchConfig(cfg *config.Config) handlers.BatchConfig {
+	return handlers.BatchConfig{
+		MaxQueries:  cfg.Batch.MaxQueries,
+		Concurrency: cfg.Batch.Concurrency,
+		Timeout:     cfg.Batch.Timeout,
+	}
+}
+
+// newBucketStore returns the configured rate limit store. Redis reuses the
+// cache's connection when the cache is on Redis too.
+func newBucketStore(cfg *config.Config, dbManager *database.Manager, logger *logrus.Logger) middleware.BucketStore {
+	switch cfg.RateLimit.Backend {
+	case "", "memory":
+		return middleware.NewMemoryBuckets()
+	case "redis":
+		backend, ok := dbManager.Cache.(*database.RedisBackend)
+		if !ok {
+			var err error
+			if backend, err = database.NewRedisBackend(cfg.Redis.URL, redisOptions(cfg)); err != nil {
+				logger.WithError(err).Fatal("Failed to connect rate limit store")
+			}
+		}
+		return middleware.NewRedisBuckets(backend.Client(), cfg.Cache.Namespace+":ratelimit:", logger)
+	default:
+		logger.WithField("backend", cfg.RateLimit.Backend).Fatal("Unknown rate limit backend")
+		return nil
+	}
+}
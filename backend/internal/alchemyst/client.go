@@ -2,55 +2,78 @@ package alchemyst
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	retry      RetryConfig
 	logger     *logrus.Logger
 }
 
-func NewClient(baseURL, apiKey string, logger *logrus.Logger) *Client {
+// ClientConfig configures a Client. A zero Timeout or Retry keeps the
+// default.
+type ClientConfig struct {
+	BaseURL string
+	APIKey  string
+	Timeout time.Duration // per HTTP request
+	Retry   RetryConfig
+}
+
+func NewClient(cfg ClientConfig, logger *logrus.Logger) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Minute
+	}
+	if cfg.Retry.BaseDelay <= 0 {
+		cfg.Retry = DefaultRetryConfig()
+	}
+
 	return &Client{
-		baseURL: baseURL,
-		apiKey:  apiKey,
+		baseURL: cfg.BaseURL,
+		apiKey:  cfg.APIKey,
 		httpClient: &http.Client{
-			Timeout: 600 * time.Second, // Increased from 30s to 120s
+			Timeout: cfg.Timeout,
 		},
+		retry:  cfg.Retry,
 		logger: logger,
 	}
 }
 
-func (c *Client) AddContext(req AddContextRequest) error {
-	return c.makeRequest("POST", "/add", req, nil)
+func (c *Client) AddContext(ctx context.Context, req AddContextRequest) error {
+	return c.makeRequest(ctx, "POST", "/add", req, nil)
 }
 
-func (c *Client) SearchContext(req SearchRequest) (*SearchResponse, error) {
+func (c *Client) SearchContext(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
 	var response SearchResponse
-	err := c.makeRequest("POST", "/search?mode=standard", req, &response)
+	err := c.makeRequest(ctx, "POST", "/search?mode=standard", req, &response)
 	return &response, err
 }
 
-func (c *Client) DeleteContext(req DeleteContextRequest) error {
-	return c.makeRequest("POST", "/delete", req, nil)
+func (c *Client) DeleteContext(ctx context.Context, req DeleteContextRequest) error {
+	return c.makeRequest(ctx, "POST", "/delete", req, nil)
 }
 
-func (c *Client) ViewContext() (*ViewContextResponse, error) {
+func (c *Client) ViewContext(ctx context.Context) (*ViewContextResponse, error) {
 	var response ViewContextResponse
-	err := c.makeRequest("GET", "/view", nil, &response)
+	err := c.makeRequest(ctx, "GET", "/view", nil, &response)
 	return &response, err
 }
 
-func (c *Client) makeRequest(method, endpoint string, payload interface{}, result interface{}) error {
+func (c *Client) makeRequest(ctx context.Context, method, endpoint string, payload interface{}, result interface{}) error {
 	url := c.baseURL + endpoint
+	log := utils.LoggerFromContext(ctx, c.logger)
 
 	var body io.Reader
 	var contentLength int
@@ -64,15 +87,16 @@ func (c *Client) makeRequest(method, endpoint string, payload interface{}, resul
 		contentLength = len(jsonData)
 
 		// Log payload size for debugging
-		c.logger.WithFields(logrus.Fields{
+		log.WithFields(logrus.Fields{
 			"method":       method,
 			"url":          url,
 			"payload_size": contentLength,
 		}).Debug("Request payload info")
 
-		// Only log full payload for small requests to avoid spam
-		if contentLength < 1000 {
-			c.logger.WithFields(logrus.Fields{
+		// Only log full payload for small requests to avoid spam; payloads
+		// carry query text, so skip them when queries are redacted
+		if contentLength < 1000 && !utils.RedactsQueries() {
+			log.WithFields(logrus.Fields{
 				"method":       method,
 				"url":          url,
 				"payload_json": string(jsonData),
@@ -80,7 +104,7 @@ func (c *Client) makeRequest(method, endpoint string, payload interface{}, resul
 		}
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -88,7 +112,7 @@ func (c *Client) makeRequest(method, endpoint string, payload interface{}, resul
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	c.logger.WithFields(logrus.Fields{
+	log.WithFields(logrus.Fields{
 		"method":   method,
 		"url":      url,
 		"has_body": payload != nil,
@@ -104,13 +128,18 @@ func (c *Client) makeRequest(method, endpoint string, payload interface{}, resul
 	responseBody, err := io.ReadAll(resp.Body)
 
 	// if endpoint == "/search?mode=standard" {
-	// 	c.logger.WithField("raw_response", string(responseBody)).Info("Raw search response")
+	// 	log.WithField("raw_response", string(responseBody)).Info("Raw search response")
 	// }
 	if err != nil {
 		return fmt.Errorf("failed to read response: %w", err)
 	}
 
-	c.logger.WithFields(logrus.Fields{
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("http.response.status_code", resp.StatusCode),
+		attribute.Int("alchemyst.response_size", len(responseBody)),
+	)
+
+	log.WithFields(logrus.Fields{
 		"status_code":   resp.StatusCode,
 		"method":        method,
 		"url":           url,
@@ -119,7 +148,7 @@ func (c *Client) makeRequest(method, endpoint string, payload interface{}, resul
 
 	// Only log response body for small responses or errors
 	if len(responseBody) < 500 || resp.StatusCode >= 400 {
-		c.logger.WithFields(logrus.Fields{
+		log.WithFields(logrus.Fields{
 			"status_code":   resp.StatusCode,
 			"method":        method,
 			"url":           url,
@@ -7,12 +7,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type RetryConfig struct {
 	MaxRetries int
-	BaseDelay  time.Duration
+	BaseDelay  time.Duration // first backoff, grown 1.5x on each retry
 	MaxDelay   time.Duration
 }
 
@@ -24,8 +28,14 @@ func DefaultRetryConfig() RetryConfig {
 	}
 }
 
-func (c *Client) AddContextWithRetry(ctx context.Context, req AddContextRequest) error {
-	config := DefaultRetryConfig()
+func (c *Client) AddContextWithRetry(ctx context.Context, req AddContextRequest) (err error) {
+	config := c.retry
+
+	ctx, span := tracing.Start(ctx, "alchemyst.AddContext",
+		attribute.String("alchemyst.source", req.Source),
+		attribute.Int("alchemyst.documents", len(req.Documents)),
+	)
+	defer func() { tracing.End(span, err) }()
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		select {
@@ -34,7 +44,9 @@ func (c *Client) AddContextWithRetry(ctx context.Context, req AddContextRequest)
 		default:
 		}
 
-		err := c.AddContext(req)
+		err := c.attempt(ctx, "add", attempt, func(attemptCtx context.Context) error {
+			return c.AddContext(attemptCtx, req)
+		})
 		if err == nil {
 			return nil
 		}
@@ -43,7 +55,7 @@ func (c *Client) AddContextWithRetry(ctx context.Context, req AddContextRequest)
 		if strings.Contains(err.Error(), "File name already exists") ||
 			strings.Contains(err.Error(), "BAD_REQUEST") {
 
-			c.logger.WithFields(logrus.Fields{
+			utils.LoggerFromContext(ctx, c.logger).WithFields(logrus.Fields{
 				"attempt": attempt + 1,
 				"error":   err.Error(),
 			}).Warn("File name conflict, modifying filename")
@@ -59,7 +71,7 @@ func (c *Client) AddContextWithRetry(ctx context.Context, req AddContextRequest)
 
 				req.Documents[0].FileName = newName
 
-				c.logger.WithFields(logrus.Fields{
+				utils.LoggerFromContext(ctx, c.logger).WithFields(logrus.Fields{
 					"old_name": originalName,
 					"new_name": newName,
 				}).Debug("Updated filename for retry")
@@ -75,12 +87,17 @@ func (c *Client) AddContextWithRetry(ctx context.Context, req AddContextRequest)
 			delay = config.MaxDelay
 		}
 
-		c.logger.WithFields(logrus.Fields{
+		utils.LoggerFromContext(ctx, c.logger).WithFields(logrus.Fields{
 			"attempt": attempt + 1,
 			"delay":   delay,
 			"error":   err.Error(),
 		}).Warn("Retrying Alchemyst operation")
 
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt+1),
+			attribute.String("delay", delay.String()),
+		))
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -93,16 +110,33 @@ func (c *Client) AddContextWithRetry(ctx context.Context, req AddContextRequest)
 
 func (c *Client) SearchContextWithRetry(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
 	var result *SearchResponse
-	err := c.retryOperation(ctx, func() error {
+	err := c.retryOperation(ctx, "search", func(attemptCtx context.Context) error {
 		var err error
-		result, err = c.SearchContext(req)
+		result, err = c.SearchContext(attemptCtx, req)
 		return err
 	})
 	return result, err
 }
 
-func (c *Client) retryOperation(ctx context.Context, operation func() error) error {
-	config := DefaultRetryConfig()
+// attempt runs a single try of an operation inside its own span so retries
+// show up as siblings under the operation span
+func (c *Client) attempt(ctx context.Context, operation string, attempt int, fn func(ctx context.Context) error) error {
+	ctx, span := tracing.Start(ctx, "alchemyst.attempt",
+		attribute.String("alchemyst.operation", operation),
+		attribute.Int("alchemyst.attempt", attempt+1),
+	)
+	err := fn(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *Client) retryOperation(ctx context.Context, operation string, fn func(ctx context.Context) error) (err error) {
+	config := c.retry
+
+	ctx, span := tracing.Start(ctx, "alchemyst."+operation,
+		attribute.Int("alchemyst.max_retries", config.MaxRetries),
+	)
+	defer func() { tracing.End(span, err) }()
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		select {
@@ -111,7 +145,7 @@ func (c *Client) retryOperation(ctx context.Context, operation func() error) err
 		default:
 		}
 
-		err := operation()
+		err := c.attempt(ctx, operation, attempt, fn)
 		if err == nil {
 			return nil
 		}
@@ -125,12 +159,17 @@ func (c *Client) retryOperation(ctx context.Context, operation func() error) err
 			delay = config.MaxDelay
 		}
 
-		c.logger.WithFields(logrus.Fields{
+		utils.LoggerFromContext(ctx, c.logger).WithFields(logrus.Fields{
 			"attempt": attempt + 1,
 			"delay":   delay,
 			"error":   err.Error(),
 		}).Warn("Retrying operation")
 
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt+1),
+			attribute.String("delay", delay.String()),
+		))
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
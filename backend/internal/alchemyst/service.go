@@ -3,23 +3,42 @@ package alchemyst
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
 type Service struct {
 	client *Client
 	logger *logrus.Logger
+
+	mu         sync.RWMutex
+	thresholds Thresholds
+}
+
+// Thresholds bound the similarity of search results
+type Thresholds struct {
+	Similarity float64 // preferred minimum similarity
+	Minimum    float64 // results below this are never returned
 }
 
 func NewService(client *Client, logger *logrus.Logger) *Service {
 	return &Service{
-		client: client,
-		logger: logger,
+		client:     client,
+		logger:     logger,
+		thresholds: Thresholds{Similarity: 0.7, Minimum: 0.3},
 	}
 }
 
+// SetThresholds changes the similarity thresholds of later searches
+func (s *Service) SetThresholds(t Thresholds) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.thresholds = t
+}
+
 func (s *Service) AddWikiContent(ctx context.Context, title, content, url string) error {
 	contentSize := int64(len(content))
 	now := time.Now()
@@ -35,8 +54,8 @@ func (s *Service) AddWikiContent(ctx context.Context, title, content, url string
 		ByDoc:  true,
 	}
 
-	if err := s.client.DeleteContext(deleteReq); err != nil {
-		s.logger.WithError(err).Debug("Delete failed, continuing with unique filename")
+	if err := s.client.DeleteContext(ctx, deleteReq); err != nil {
+		utils.LoggerFromContext(ctx, s.logger).WithError(err).Debug("Delete failed, continuing with unique filename")
 	} else {
 		// Wait briefly for deletion to propagate
 		time.Sleep(3 * time.Second)
@@ -66,15 +85,21 @@ func (s *Service) AddWikiContent(ctx context.Context, title, content, url string
 }
 
 func (s *Service) SearchForSolution(ctx context.Context, errorQuery string) ([]SearchResult, error) {
+	s.mu.RLock()
+	thresholds := s.thresholds
+	s.mu.RUnlock()
+
 	req := SearchRequest{
 		Query:                      errorQuery,
-		SimilarityThreshold:        0.7,
-		MinimumSimilarityThreshold: 0.3,
+		SimilarityThreshold:        thresholds.Similarity,
+		MinimumSimilarityThreshold: thresholds.Minimum,
 		Scope:                      "internal",
 	}
 
-	s.logger.WithFields(logrus.Fields{
-		"query":                errorQuery,
+	log := utils.LoggerFromContext(ctx, s.logger)
+
+	log.WithFields(logrus.Fields{
+		"query":                utils.RedactQuery(errorQuery),
 		"similarity_threshold": req.SimilarityThreshold,
 		"min_threshold":        req.MinimumSimilarityThreshold,
 		"scope":                req.Scope,
@@ -82,11 +107,11 @@ func (s *Service) SearchForSolution(ctx context.Context, errorQuery string) ([]S
 
 	response, err := s.client.SearchContextWithRetry(ctx, req)
 	if err != nil {
-		s.logger.WithError(err).Error("Alchemyst API call failed")
+		log.WithError(err).Error("Alchemyst API call failed")
 		return nil, err
 	}
 
-	s.logger.WithField("results_count", len(response.Contexts.Contexts)).Info("Alchemyst API response")
+	log.WithField("results_count", len(response.Contexts.Contexts)).Info("Alchemyst API response")
 	return response.Contexts.Contexts, nil
 }
 
@@ -97,10 +122,10 @@ func (s *Service) DeleteWikiContent(ctx context.Context, title string) error {
 		ByID:   false,
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	utils.LoggerFromContext(ctx, s.logger).WithFields(logrus.Fields{
 		"title":  title,
 		"source": req.Source,
 	}).Debug("Deleting from Alchemyst context")
 
-	return s.client.DeleteContext(req)
+	return s.client.DeleteContext(ctx, req)
 }
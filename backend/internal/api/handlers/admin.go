@@ -0,0 +1,118 @@
+// backend/internal/api/handlers/admin.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/services"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminHandler serves operator endpoints for inspecting and managing the cache
+type AdminHandler struct {
+	cache  *database.Cache
+	logger *logrus.Logger
+}
+
+func NewAdminHandler(cache *database.Cache, logger *logrus.Logger) *AdminHandler {
+	return &AdminHandler{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// CacheInvalidateRequest selects what to drop from the cache. Exactly one of
+// Query, Page or All must be set.
+type CacheInvalidateRequest struct {
+	Query string `json:"query,omitempty"` // drop cached results for this search
+	Limit int    `json:"limit,omitempty"` // only the entry for this limit; all limits when zero
+	Page  string `json:"page,omitempty"`  // drop cached searches that used this wiki page
+	All   bool   `json:"all,omitempty"`   // move the whole namespace to a new generation
+}
+
+// HandleCacheStats returns per-family cache statistics
+func (h *AdminHandler) HandleCacheStats(c *gin.Context) {
+	log := utils.LoggerFromContext(c.Request.Context(), h.logger)
+
+	stats, err := h.cache.Stats(c.Request.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to collect cache statistics")
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Failed to collect cache statistics", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cache statistics retrieved", stats)
+}
+
+// HandleCacheInvalidate drops cache entries by query, by page or entirely
+func (h *AdminHandler) HandleCacheInvalidate(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := utils.LoggerFromContext(ctx, h.logger)
+
+	var req CacheInvalidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+	req.Page = strings.TrimSpace(req.Page)
+
+	selectors := 0
+	for _, set := range []bool{req.Query != "", req.Page != "", req.All} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Specify exactly one of query, page or all", nil)
+		return
+	}
+
+	result := gin.H{}
+	var err error
+
+	switch {
+	case req.Query != "":
+		// Without a limit, drop the entry for every limit a client could ask for
+		var limits []int
+		if req.Limit != 0 {
+			limits = append(limits, services.ClampLimit(req.Limit))
+		} else {
+			for limit := 1; limit <= services.MaxSearchResults; limit++ {
+				limits = append(limits, limit)
+			}
+		}
+		for _, limit := range limits {
+			if err = h.cache.InvalidateSearchCache(ctx, database.SearchCacheKey(req.Query, limit)); err != nil {
+				break
+			}
+		}
+		result["query"] = utils.RedactQuery(req.Query)
+		result["limits"] = len(limits)
+
+	case req.Page != "":
+		var removed int
+		removed, err = h.cache.InvalidatePage(ctx, req.Page)
+		result["page"] = req.Page
+		result["entries"] = removed
+
+	case req.All:
+		var generation int64
+		generation, err = h.cache.BumpGeneration(ctx)
+		result["generation"] = generation
+	}
+
+	if err != nil {
+		log.WithError(err).Error("Cache invalidation failed")
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Cache invalidation failed", err)
+		return
+	}
+
+	log.WithFields(logrus.Fields(result)).Info("Cache invalidated")
+	utils.SuccessResponse(c, http.StatusOK, "Cache invalidated", result)
+}
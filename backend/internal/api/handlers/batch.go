@@ -0,0 +1,227 @@
+// backend/internal/api/handlers/batch.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/middleware"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/services"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BatchConfig bounds the work one batch request may cause
+type BatchConfig struct {
+	MaxQueries  int           // queries accepted per request
+	Concurrency int           // searches run at once per request
+	Timeout     time.Duration // deadline shared by every query in a request
+}
+
+// BatchHandler serves key-authenticated batch searches for tools such as
+// CI bots. Batch traffic shares the search cache but is left out of
+// analytics and popular queries, which describe interactive users.
+type BatchHandler struct {
+	searchService *services.SearchService
+	searchCache   *database.SearchCache
+	limits        *middleware.KeyLimiter
+	logger        *logrus.Logger
+
+	mu  sync.RWMutex
+	cfg BatchConfig
+}
+
+func NewBatchHandler(
+	searchService *services.SearchService,
+	searchCache *database.SearchCache,
+	limits *middleware.KeyLimiter,
+	cfg BatchConfig,
+	logger *logrus.Logger,
+) *BatchHandler {
+	return &BatchHandler{
+		searchService: searchService,
+		searchCache:   searchCache,
+		limits:        limits,
+		cfg:           cfg.withDefaults(),
+		logger:        logger,
+	}
+}
+
+func (cfg BatchConfig) withDefaults() BatchConfig {
+	if cfg.MaxQueries <= 0 {
+		cfg.MaxQueries = 25
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 4
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 25 * time.Second
+	}
+	return cfg
+}
+
+// SetConfig changes the bounds applied to later requests
+func (h *BatchHandler) SetConfig(cfg BatchConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cfg = cfg.withDefaults()
+}
+
+func (h *BatchHandler) config() BatchConfig {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cfg
+}
+
+// HandleBatchSearch runs every query in the request with bounded
+// concurrency under one deadline and reports each outcome separately.
+// Each valid query costs one unit of the API key's daily quota.
+func (h *BatchHandler) HandleBatchSearch(c *gin.Context) {
+	startTime := time.Now()
+
+	spanCtx, span := tracing.Start(c.Request.Context(), "BatchHandler.HandleBatchSearch")
+	defer span.End()
+
+	log := utils.LoggerFromContext(spanCtx, h.logger)
+
+	key := middleware.APIKeyFromContext(c)
+	if key == nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "API key required", nil)
+		return
+	}
+	log = log.WithField("api_key", key.Name)
+
+	var req models.BatchSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+	if len(req.Queries) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "At least one query is required", nil)
+		return
+	}
+	cfg := h.config()
+	if len(req.Queries) > cfg.MaxQueries {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Too many queries (max %d)", cfg.MaxQueries), nil)
+		return
+	}
+
+	items := make([]models.BatchItem, len(req.Queries))
+	var pending []int
+	for i, q := range req.Queries {
+		q.Query = strings.TrimSpace(q.Query)
+		req.Queries[i] = q
+		items[i] = models.BatchItem{ID: q.ID, Query: q.Query, Results: []models.SearchResult{}}
+
+		switch {
+		case q.Query == "":
+			items[i].Error = "Query cannot be empty"
+		case len(q.Query) > 2000:
+			items[i].Error = "Query too long (max 2000 characters)"
+		default:
+			pending = append(pending, i)
+		}
+	}
+
+	// The request itself was charged one unit on the way in
+	if !h.limits.Charge(c, len(pending)-1) {
+		log.WithField("queries", len(pending)).Warn("API key quota exceeded")
+		utils.ErrorResponse(c, http.StatusTooManyRequests, "API key daily quota exceeded", nil)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("batch.queries", len(req.Queries)),
+		attribute.Int("batch.valid", len(pending)),
+	)
+
+	ctx, cancel := context.WithTimeout(spanCtx, cfg.Timeout)
+	defer cancel()
+
+	sem := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+	for _, i := range pending {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				items[i].Error = "Deadline exceeded before the search started"
+				return
+			}
+
+			h.runQuery(ctx, req.Queries[i], &items[i])
+		}(i)
+	}
+	wg.Wait()
+
+	response := models.BatchSearchResponse{
+		Items:        items,
+		ResponseTime: int(time.Since(startTime).Milliseconds()),
+	}
+	for _, item := range items {
+		if item.Error != "" {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("batch.succeeded", response.Succeeded),
+		attribute.Int("batch.failed", response.Failed),
+	)
+
+	log.WithFields(logrus.Fields{
+		"queries":       len(req.Queries),
+		"succeeded":     response.Succeeded,
+		"failed":        response.Failed,
+		"response_time": response.ResponseTime,
+	}).Info("Batch search completed")
+
+	utils.SuccessResponse(c, http.StatusOK, "Batch search completed", response)
+}
+
+// runQuery searches for one batch query through the shared search cache
+func (h *BatchHandler) runQuery(ctx context.Context, q models.BatchQuery, item *models.BatchItem) {
+	limit := services.ClampLimit(q.Limit)
+	lookup, err := h.searchCache.Get(ctx, database.SearchCacheKey(q.Query, limit), func(ctx context.Context) ([]models.SearchResult, error) {
+		return h.searchService.SearchForSolution(ctx, q.Query, limit)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			item.Error = "Deadline exceeded"
+		case errors.Is(err, database.ErrUpstreamFailure):
+			item.Error = "Search temporarily unavailable"
+		default:
+			item.Error = "Search failed"
+		}
+		utils.LoggerFromContext(ctx, h.logger).WithError(err).
+			WithField("query", utils.RedactQuery(q.Query)).Warn("Batch query failed")
+		return
+	}
+
+	if lookup.Results != nil {
+		item.Results = lookup.Results
+	}
+	item.Total = len(lookup.Results)
+	item.ComputedAt = &lookup.ComputedAt
+	item.Freshness = lookup.Freshness
+	if q.Summarize {
+		item.Summary = h.searchService.Summarize(ctx, q.Query, lookup.Results)
+	}
+}
@@ -0,0 +1,307 @@
+// backend/internal/api/handlers/content.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
+	"github.com/Ayash-Bera/ophelia/backend/internal/seeder"
+	"github.com/Ayash-Bera/ophelia/backend/internal/services"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// recrawlTimeout bounds a background re-crawl, which uploads every section
+// of the page to Alchemyst one by one
+const recrawlTimeout = 15 * time.Minute
+
+// ContentHandler serves operator endpoints for the indexed wiki pages
+type ContentHandler struct {
+	repoManager   *repository.RepositoryManager
+	crawler       *seeder.Crawler
+	cache         *database.Cache
+	searchService *services.SearchService
+	logger        *logrus.Logger
+
+	mu         sync.Mutex
+	recrawling map[uint]bool
+}
+
+func NewContentHandler(
+	repoManager *repository.RepositoryManager,
+	crawler *seeder.Crawler,
+	cache *database.Cache,
+	searchService *services.SearchService,
+	logger *logrus.Logger,
+) *ContentHandler {
+	return &ContentHandler{
+		repoManager:   repoManager,
+		crawler:       crawler,
+		cache:         cache,
+		searchService: searchService,
+		logger:        logger,
+		recrawling:    make(map[uint]bool),
+	}
+}
+
+// HandleListPages lists indexed pages with their crawl status and content
+// hash, optionally filtered by ?status= and ?active=
+func (h *ContentHandler) HandleListPages(c *gin.Context) {
+	log := utils.LoggerFromContext(c.Request.Context(), h.logger)
+
+	status := c.Query("status")
+	var active *bool
+	if value := c.Query("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "active must be true or false", err)
+			return
+		}
+		active = &parsed
+	}
+
+	contents, err := h.repoManager.ContentMetadata.List()
+	if err != nil {
+		log.WithError(err).Error("Failed to list pages")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to list pages", err)
+		return
+	}
+
+	pages := make([]models.IndexedPage, 0, len(contents))
+	for _, content := range contents {
+		if status != "" && content.CrawlStatus != status {
+			continue
+		}
+		if active != nil && content.IsActive != *active {
+			continue
+		}
+		pages = append(pages, indexedPage(&content))
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pages retrieved", gin.H{
+		"pages": pages,
+		"total": len(pages),
+	})
+}
+
+// HandleListSections lists the stored sections of one page
+func (h *ContentHandler) HandleListSections(c *gin.Context) {
+	content, ok := h.loadPage(c)
+	if !ok {
+		return
+	}
+
+	sections, err := h.repoManager.ContentMetadata.GetSections(content.ID)
+	if err != nil {
+		utils.LoggerFromContext(c.Request.Context(), h.logger).WithError(err).Error("Failed to list sections")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to list sections", err)
+		return
+	}
+
+	result := make([]models.IndexedSection, 0, len(sections))
+	for _, section := range sections {
+		patterns := []string(section.ErrorPatterns)
+		if patterns == nil {
+			patterns = []string{}
+		}
+		result = append(result, models.IndexedSection{
+			ID:            section.ID,
+			Title:         section.SectionTitle,
+			Order:         section.SectionOrder,
+			Length:        len(section.SectionContent),
+			ErrorPatterns: patterns,
+		})
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sections retrieved", gin.H{
+		"page":     indexedPage(content),
+		"sections": result,
+	})
+}
+
+// HandleRecrawl starts a re-crawl of one page in the background and
+// answers right away. The page's crawl status shows the progress. A
+// deactivated page is only re-crawled with ?reactivate=true, which puts it
+// back into search results.
+func (h *ContentHandler) HandleRecrawl(c *gin.Context) {
+	content, ok := h.loadPage(c)
+	if !ok {
+		return
+	}
+
+	reactivate, _ := strconv.ParseBool(c.Query("reactivate"))
+	if !content.IsActive && !reactivate {
+		utils.ErrorResponse(c, http.StatusConflict, "Page is deactivated, pass reactivate=true to crawl it again", nil)
+		return
+	}
+
+	h.mu.Lock()
+	if h.recrawling[content.ID] {
+		h.mu.Unlock()
+		utils.ErrorResponse(c, http.StatusConflict, "Page is already being re-crawled", nil)
+		return
+	}
+	h.recrawling[content.ID] = true
+	h.mu.Unlock()
+
+	page := seeder.Page{Title: content.WikiPageTitle, URL: content.PageURL}
+
+	// Keep the request's logger and trace, but not its deadline
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), recrawlTimeout)
+	go func() {
+		defer cancel()
+		defer func() {
+			h.mu.Lock()
+			delete(h.recrawling, content.ID)
+			h.mu.Unlock()
+		}()
+		h.recrawl(ctx, page)
+	}()
+
+	content.CrawlStatus = "crawling"
+	utils.SuccessResponse(c, http.StatusAccepted, "Re-crawl started", indexedPage(content))
+}
+
+func (h *ContentHandler) recrawl(ctx context.Context, page seeder.Page) {
+	log := utils.LoggerFromContext(ctx, h.logger).WithField("page", page.Title)
+
+	change, err := h.crawler.Crawl(ctx, page, true)
+	if err != nil {
+		log.WithError(err).Error("Re-crawl failed")
+		return
+	}
+
+	h.searchService.ReloadPages()
+	if change != seeder.Unchanged {
+		// Let running servers know to re-warm popular queries
+		if err := h.cache.MarkContentChanged(ctx); err != nil {
+			log.WithError(err).Warn("Failed to signal content change")
+		}
+	}
+	log.WithField("changed", change != seeder.Unchanged).Info("Page re-crawled")
+}
+
+// HandleDeactivate removes a page from search results. Its documents are
+// deleted from Alchemyst and cached searches that used it are dropped; the
+// metadata stays so the page can be re-crawled later.
+func (h *ContentHandler) HandleDeactivate(c *gin.Context) {
+	content, ok := h.loadPage(c)
+	if !ok {
+		return
+	}
+
+	removed, err := h.withdraw(c.Request.Context(), content)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to deactivate page", err)
+		return
+	}
+
+	content.IsActive = false
+	utils.SuccessResponse(c, http.StatusOK, "Page deactivated", gin.H{
+		"page":           indexedPage(content),
+		"cached_entries": removed,
+	})
+}
+
+// HandleDelete withdraws a page like HandleDeactivate and then deletes its
+// metadata and sections. A page that fails to leave Alchemyst is left
+// deactivated so the delete can be retried.
+func (h *ContentHandler) HandleDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+	content, ok := h.loadPage(c)
+	if !ok {
+		return
+	}
+
+	removed, err := h.withdraw(ctx, content)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to delete page", err)
+		return
+	}
+
+	if err := h.repoManager.ContentMetadata.Delete(ctx, content.ID); err != nil {
+		utils.LoggerFromContext(ctx, h.logger).WithError(err).WithField("page", content.WikiPageTitle).Error("Failed to delete page metadata")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete page", err)
+		return
+	}
+
+	utils.LoggerFromContext(ctx, h.logger).WithField("page", content.WikiPageTitle).Info("Page deleted")
+	utils.SuccessResponse(c, http.StatusOK, "Page deleted", gin.H{
+		"page":           content.WikiPageTitle,
+		"cached_entries": removed,
+	})
+}
+
+// withdraw deactivates a page, deletes its Alchemyst documents and drops the
+// cached searches that used it. The page is excluded from results before
+// anything is deleted, so a failure part way leaves it hidden.
+func (h *ContentHandler) withdraw(ctx context.Context, content *models.ContentMetadata) (int, error) {
+	log := utils.LoggerFromContext(ctx, h.logger).WithField("page", content.WikiPageTitle)
+
+	if err := h.repoManager.ContentMetadata.SetActive(ctx, content.ID, false); err != nil {
+		log.WithError(err).Error("Failed to deactivate page")
+		return 0, err
+	}
+	h.searchService.ReloadPages()
+
+	if err := h.crawler.Remove(ctx, content); err != nil {
+		log.WithError(err).Error("Failed to remove page from Alchemyst")
+		return 0, fmt.Errorf("failed to remove page from Alchemyst: %w", err)
+	}
+
+	removed, err := h.cache.InvalidatePage(ctx, content.WikiPageTitle)
+	if err != nil {
+		// The page is already filtered out of fresh results
+		log.WithError(err).Warn("Failed to invalidate cached searches")
+	}
+
+	log.WithField("entries", removed).Info("Page withdrawn from search")
+	return removed, nil
+}
+
+// loadPage reads the page named by the :id parameter, answering the request
+// itself when it cannot
+func (h *ContentHandler) loadPage(c *gin.Context) (*models.ContentMetadata, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid page ID", err)
+		return nil, false
+	}
+
+	content, err := h.repoManager.ContentMetadata.GetByID(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		utils.ErrorResponse(c, http.StatusNotFound, "Page not found", nil)
+		return nil, false
+	}
+	if err != nil {
+		utils.LoggerFromContext(c.Request.Context(), h.logger).WithError(err).Error("Failed to load page")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to load page", err)
+		return nil, false
+	}
+	return content, true
+}
+
+func indexedPage(content *models.ContentMetadata) models.IndexedPage {
+	return models.IndexedPage{
+		ID:           content.ID,
+		Title:        content.WikiPageTitle,
+		URL:          content.PageURL,
+		CrawlStatus:  content.CrawlStatus,
+		ContentHash:  content.ContentHash,
+		IsActive:     content.IsActive,
+		WordCount:    content.WordCount,
+		SectionCount: content.SectionCount,
+		LastCrawled:  content.LastCrawled,
+		UpdatedAt:    content.UpdatedAt,
+	}
+}
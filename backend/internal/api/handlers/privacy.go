@@ -0,0 +1,107 @@
+// backend/internal/api/handlers/privacy.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PrivacyHandler struct {
+	repoManager *repository.RepositoryManager
+	logger      *logrus.Logger
+}
+
+func NewPrivacyHandler(repoManager *repository.RepositoryManager, logger *logrus.Logger) *PrivacyHandler {
+	return &PrivacyHandler{
+		repoManager: repoManager,
+		logger:      logger,
+	}
+}
+
+// RecentSearch is the public view of a stored search; it leaves out the
+// session, user agent and IP address
+type RecentSearch struct {
+	QueryText       string    `json:"query_text"`
+	ResultsCount    int       `json:"results_count"`
+	SearchTimestamp time.Time `json:"search_timestamp"`
+	ResponseTimeMs  int       `json:"response_time_ms"`
+}
+
+// HandleAnalytics returns recent searches without identifying data
+func (h *PrivacyHandler) HandleAnalytics(c *gin.Context) {
+	log := utils.LoggerFromContext(c.Request.Context(), h.logger)
+
+	recentQueries, err := h.repoManager.SearchQuery.GetRecentSearches(10)
+	if err != nil {
+		log.WithError(err).Error("Failed to get recent searches")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve analytics"})
+		return
+	}
+
+	recent := make([]RecentSearch, 0, len(recentQueries))
+	for _, q := range recentQueries {
+		recent = append(recent, RecentSearch{
+			QueryText:       q.QueryText,
+			ResultsCount:    q.ResultsCount,
+			SearchTimestamp: q.SearchTimestamp,
+			ResponseTimeMs:  q.ResponseTimeMs,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recent_queries": recent,
+		"server_time":    time.Now(),
+	})
+}
+
+// HandleDeleteSessionData deletes every search and feedback row recorded for
+// a session. The session ID is the credential, so only random IDs the client
+// issued itself through X-Session-ID can be deleted; IDs derived from IP and
+// User-Agent can be recomputed by anyone and are refused.
+func (h *PrivacyHandler) HandleDeleteSessionData(c *gin.Context) {
+	log := utils.LoggerFromContext(c.Request.Context(), h.logger)
+
+	sessionID := c.Param("session_id")
+	if sessionID == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Session ID is required", nil)
+		return
+	}
+
+	if !utils.IsClientSessionID(sessionID) {
+		utils.ErrorResponse(c, http.StatusForbidden, fmt.Sprintf(
+			"Only client-issued session IDs of at least %d characters can be deleted", utils.MinClientSessionIDLength), nil)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	feedbackDeleted, err := h.repoManager.UserFeedback.DeleteBySession(ctx, sessionID)
+	if err != nil {
+		log.WithError(err).Error("Failed to delete session feedback")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete session data", err)
+		return
+	}
+
+	searchesDeleted, err := h.repoManager.SearchQuery.DeleteBySession(ctx, sessionID)
+	if err != nil {
+		log.WithError(err).Error("Failed to delete session searches")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete session data", err)
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		"searches_deleted": searchesDeleted,
+		"feedback_deleted": feedbackDeleted,
+	}).Info("Session data deleted")
+
+	utils.SuccessResponse(c, http.StatusOK, "Session data deleted", gin.H{
+		"searches_deleted": searchesDeleted,
+		"feedback_deleted": feedbackDeleted,
+	})
+}
@@ -3,48 +3,99 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Ayash-Bera/ophelia/backend/internal/database"
 	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/privacy"
 	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
 	"github.com/Ayash-Bera/ophelia/backend/internal/services"
+	"github.com/Ayash-Bera/ophelia/backend/internal/suggest"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
 	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// SearchConfig bounds how long a search request may run
+type SearchConfig struct {
+	Timeout       time.Duration // for a search request
+	StreamTimeout time.Duration // for a streamed search; keep it inside the server's write timeout
+}
+
+func (cfg SearchConfig) withDefaults() SearchConfig {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.StreamTimeout <= 0 {
+		cfg.StreamTimeout = 25 * time.Second
+	}
+	return cfg
+}
+
 type SearchHandler struct {
 	searchService   *services.SearchService
 	repoManager     *repository.RepositoryManager
-	cache           *database.Cache
+	searchCache     *database.SearchCache
+	anonymizer      *privacy.Anonymizer
+	suggestions     *suggest.Index
 	logger          *logrus.Logger
+
+	mu  sync.RWMutex
+	cfg SearchConfig
 }
 
 func NewSearchHandler(
 	searchService *services.SearchService,
 	repoManager *repository.RepositoryManager,
-	cache *database.Cache,
+	searchCache *database.SearchCache,
+	anonymizer *privacy.Anonymizer,
+	suggestions *suggest.Index,
+	cfg SearchConfig,
 	logger *logrus.Logger,
 ) *SearchHandler {
 	return &SearchHandler{
 		searchService: searchService,
 		repoManager:   repoManager,
-		cache:         cache,
+		searchCache:   searchCache,
+		anonymizer:    anonymizer,
+		suggestions:   suggestions,
+		cfg:           cfg.withDefaults(),
 		logger:        logger,
 	}
 }
 
+// SetConfig changes the timeouts of later requests
+func (h *SearchHandler) SetConfig(cfg SearchConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cfg = cfg.withDefaults()
+}
+
+func (h *SearchHandler) config() SearchConfig {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cfg
+}
+
 // HandleSearch processes search requests
 func (h *SearchHandler) HandleSearch(c *gin.Context) {
 	startTime := time.Now()
+
+	spanCtx, span := tracing.Start(c.Request.Context(), "SearchHandler.HandleSearch")
+	defer span.End()
+
+	log := utils.LoggerFromContext(spanCtx, h.logger)
 	
 	var req models.SearchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.WithError(err).Error("Invalid search request")
+		log.WithError(err).Error("Invalid search request")
 		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
 		return
 	}
@@ -64,64 +115,68 @@ func (h *SearchHandler) HandleSearch(c *gin.Context) {
 	// Get user session for analytics
 	userSession := h.getUserSession(c)
 	
-	h.logger.WithFields(logrus.Fields{
-		"query":        query,
-		"user_session": userSession,
-		"user_agent":   c.GetHeader("User-Agent"),
-		"ip_address":   c.ClientIP(),
+	log.WithFields(logrus.Fields{
+		"query":      utils.RedactQuery(query),
+		"user_agent": c.GetHeader("User-Agent"),
+		"ip_address": utils.RedactIP(c.ClientIP()),
 	}).Info("Processing search request")
 
 	// Check cache first
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(spanCtx, h.config().Timeout)
 	defer cancel()
 
-	var results []models.SearchResult
-	// var err error
-	
-	cacheKey := h.generateCacheKey(query)
-	cached := &models.SearchResponse{}
-	
-	if err := h.cache.GetCachedSearchResults(ctx, cacheKey, cached); err == nil {
-		h.logger.Debug("Search results served from cache")
-		results = cached.Results
-	} else {
-		// Cache miss - perform search
-		h.logger.Debug("Cache miss - performing search")
-		results, err = h.searchService.SearchForSolution(ctx, query)
-		if err != nil {
-			h.logger.WithError(err).Error("Search failed")
-			h.trackSearchQuery(userSession, query, 0, time.Since(startTime), c)
-			utils.ErrorResponse(c, http.StatusInternalServerError, "Search failed", err)
-			return
-		}
+	// Analytics writes outlive the request but should still join its trace
+	trackCtx := context.WithoutCancel(spanCtx)
+	userAgent := c.GetHeader("User-Agent")
+	clientIP := c.ClientIP()
 
-		// Cache results for 5 minutes
-		searchResp := &models.SearchResponse{
-			Results:      results,
-			Total:        len(results),
-			ResponseTime: int(time.Since(startTime).Milliseconds()),
-		}
-		
-		if err := h.cache.CacheSearchResults(ctx, cacheKey, searchResp, 5*time.Minute); err != nil {
-			h.logger.WithError(err).Warn("Failed to cache search results")
+	limit := services.ClampLimit(req.Limit)
+	cacheKey := database.SearchCacheKey(query, limit)
+	lookup, err := h.searchCache.Get(ctx, cacheKey, func(ctx context.Context) ([]models.SearchResult, error) {
+		log.Debug("Cache miss - performing search")
+		return h.searchService.SearchForSolution(ctx, query, limit)
+	})
+	if err != nil {
+		log.WithError(err).Error("Search failed")
+		tracing.Fail(span, err)
+		go h.trackSearchQuery(trackCtx, userSession, query, 0, time.Since(startTime), userAgent, clientIP)
+		if errors.Is(err, database.ErrUpstreamFailure) {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, "Search temporarily unavailable", err)
+			return
 		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Search failed", err)
+		return
 	}
 
+	results := lookup.Results
+	span.SetAttributes(
+		attribute.Bool("cache.hit", lookup.Freshness != database.FreshnessLive),
+		attribute.String("cache.freshness", lookup.Freshness),
+	)
+
 	responseTime := time.Since(startTime)
 	
 	// Track analytics
-	go h.trackSearchQuery(userSession, query, len(results), responseTime, c)
-	go h.updatePopularQueries(query, len(results), responseTime)
+	go h.trackSearchQuery(trackCtx, userSession, query, len(results), responseTime, userAgent, clientIP)
+	go h.updatePopularQueries(trackCtx, query, len(results), responseTime)
+
+	span.SetAttributes(attribute.Int("search.results", len(results)))
 
 	response := models.SearchResponse{
 		Results:      results,
 		Total:        len(results),
 		ResponseTime: int(responseTime.Milliseconds()),
+		ComputedAt:   lookup.ComputedAt,
+		Freshness:    lookup.Freshness,
+	}
+	if req.Summarize {
+		response.Summary = h.searchService.Summarize(ctx, query, results)
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	log.WithFields(logrus.Fields{
 		"results_count": len(results),
 		"response_time": responseTime.Milliseconds(),
+		"freshness":     lookup.Freshness,
 	}).Info("Search completed successfully")
 
 	utils.SuccessResponse(c, http.StatusOK, "Search completed", response)
@@ -129,6 +184,8 @@ func (h *SearchHandler) HandleSearch(c *gin.Context) {
 
 // HandleFeedback processes user feedback on search results
 func (h *SearchHandler) HandleFeedback(c *gin.Context) {
+	log := utils.LoggerFromContext(c.Request.Context(), h.logger)
+
 	var req models.FeedbackRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid feedback format", err)
@@ -151,17 +208,17 @@ func (h *SearchHandler) HandleFeedback(c *gin.Context) {
 	feedback := &models.UserFeedback{
 		QueryID:      req.QueryID,
 		FeedbackType: req.FeedbackType,
-		FeedbackText: req.FeedbackText,
+		FeedbackText: h.anonymizer.Text(req.FeedbackText),
 		UserSession:  h.getUserSession(c),
 	}
 
-	if err := h.repoManager.UserFeedback.Create(feedback); err != nil {
-		h.logger.WithError(err).Error("Failed to save feedback")
+	if err := h.repoManager.UserFeedback.Create(c.Request.Context(), feedback); err != nil {
+		log.WithError(err).Error("Failed to save feedback")
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to save feedback", err)
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	log.WithFields(logrus.Fields{
 		"query_id":      req.QueryID,
 		"feedback_type": req.FeedbackType,
 		"user_session":  feedback.UserSession,
@@ -170,7 +227,7 @@ func (h *SearchHandler) HandleFeedback(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusCreated, "Feedback recorded", nil)
 }
 
-// HandleSearchSuggestions returns search suggestions
+// HandleSearchSuggestions returns autocomplete suggestions for a prefix
 func (h *SearchHandler) HandleSearchSuggestions(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
@@ -179,75 +236,66 @@ func (h *SearchHandler) HandleSearchSuggestions(c *gin.Context) {
 	}
 
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit <= 0 {
+		limit = 5
+	}
 	if limit > 10 {
 		limit = 10
 	}
 
-	suggestions, err := h.repoManager.PopularQuery.GetTop(limit)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to get search suggestions")
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get suggestions", err)
-		return
+	suggestions := h.suggestions.Suggest(query, limit)
+	if suggestions == nil {
+		suggestions = []suggest.Suggestion{}
 	}
 
-	// Filter suggestions that contain the query
-	filtered := make([]models.PopularQuery, 0)
-	queryLower := strings.ToLower(query)
-	
-	for _, suggestion := range suggestions {
-		if strings.Contains(strings.ToLower(suggestion.QueryText), queryLower) {
-			filtered = append(filtered, suggestion)
-		}
-	}
-
-	utils.SuccessResponse(c, http.StatusOK, "Suggestions retrieved", filtered)
+	utils.SuccessResponse(c, http.StatusOK, "Suggestions retrieved", suggestions)
 }
 
 // Helper methods
 
 func (h *SearchHandler) getUserSession(c *gin.Context) string {
-	// Try to get session from header first
-	if session := c.GetHeader("X-Session-ID"); session != "" {
+	// The request logger resolves the session once per request
+	if session := c.GetString("user_session"); session != "" {
 		return session
 	}
-	
-	// Generate session based on IP + User-Agent (basic fingerprinting)
-	userAgent := c.GetHeader("User-Agent")
-	clientIP := c.ClientIP()
-	
-	// Create a simple session identifier
-	sessionID := utils.GenerateSessionID(clientIP + userAgent)
-	return sessionID
+	return utils.SessionFromRequest(c)
 }
 
-func (h *SearchHandler) generateCacheKey(query string) string {
-	// Use MD5 hash of query for cache key
-	return utils.MD5Hash(strings.ToLower(strings.TrimSpace(query)))
-}
+func (h *SearchHandler) trackSearchQuery(ctx context.Context, userSession, query string, resultsCount int, responseTime time.Duration, userAgent, clientIP string) {
+	log := utils.LoggerFromContext(ctx, h.logger)
 
-func (h *SearchHandler) trackSearchQuery(userSession, query string, resultsCount int, responseTime time.Duration, c *gin.Context) {
 	searchQuery := &models.SearchQuery{
-		QueryText:       query,
+		QueryText:       h.anonymizer.Text(query),
 		UserSession:     userSession,
 		ResultsCount:    resultsCount,
 		SearchTimestamp: time.Now(),
 		ResponseTimeMs:  int(responseTime.Milliseconds()),
-		UserAgent:       c.GetHeader("User-Agent"),
-		IPAddress:       c.ClientIP(),
+		UserAgent:       h.anonymizer.UserAgent(userAgent),
+		IPAddress:       h.anonymizer.IP(clientIP),
 	}
 
-	if err := h.repoManager.SearchQuery.Create(searchQuery); err != nil {
-		h.logger.WithError(err).Error("Failed to track search query")
+	if err := h.repoManager.SearchQuery.Create(ctx, searchQuery); err != nil {
+		log.WithError(err).Error("Failed to track search query")
 	}
 }
 
-func (h *SearchHandler) updatePopularQueries(query string, resultsCount int, responseTime time.Duration) {
-	if err := h.repoManager.PopularQuery.IncrementCount(query); err != nil {
-		h.logger.WithError(err).Error("Failed to update popular queries")
+func (h *SearchHandler) updatePopularQueries(ctx context.Context, query string, resultsCount int, responseTime time.Duration) {
+	log := utils.LoggerFromContext(ctx, h.logger)
+
+	// Popular queries feed suggestions, so they must not keep usernames or hostnames
+	query = h.anonymizer.Text(query)
+
+	// Keep the in-memory suggestion index current between rebuilds
+	if resultsCount > 0 {
+		h.suggestions.Record(query)
+	}
+
+	if err := h.repoManager.PopularQuery.IncrementCount(ctx, query); err != nil {
+		log.WithError(err).Error("Failed to update popular queries")
 		return
 	}
 
-	if err := h.repoManager.PopularQuery.UpdateStats(query, float64(resultsCount), int(responseTime.Milliseconds())); err != nil {
-		h.logger.WithError(err).Error("Failed to update query stats")
+	if err := h.repoManager.PopularQuery.UpdateStats(ctx, query, float64(resultsCount), int(responseTime.Milliseconds())); err != nil {
+		log.WithError(err).Error("Failed to update query stats")
 	}
 }
\ No newline at end of file
@@ -0,0 +1,195 @@
+// backend/internal/api/handlers/stream.go
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/services"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Events emitted by the streaming search, in order. cached and local are
+// skipped when there is nothing to report; error replaces results when the
+// backend search fails.
+const (
+	eventAnalysis = "analysis"
+	eventCached   = "cached"
+	eventLocal    = "local"
+	eventResults  = "results"
+	eventError    = "error"
+	eventFinal    = "final"
+	eventDone     = "done"
+)
+
+// streamResults is the payload of the cached, local, results and final
+// events; only final carries a summary
+type streamResults struct {
+	Results    []models.SearchResult   `json:"results"`
+	Total      int                     `json:"total"`
+	ComputedAt *time.Time              `json:"computed_at,omitempty"`
+	Freshness  string                  `json:"freshness,omitempty"`
+	Summary    *models.SolutionSummary `json:"summary,omitempty"`
+}
+
+func newStreamResults(results []models.SearchResult) streamResults {
+	if results == nil {
+		results = []models.SearchResult{}
+	}
+	return streamResults{Results: results, Total: len(results)}
+}
+
+// HandleSearchStream runs a search and streams progress as server-sent
+// events: the query analysis, any cached results, local keyword matches,
+// the backend results and finally the merged ranking, with a solution
+// summary when summarize=true. Clients cancel by disconnecting; a backend
+// search already under way still fills the cache.
+func (h *SearchHandler) HandleSearchStream(c *gin.Context) {
+	startTime := time.Now()
+
+	spanCtx, span := tracing.Start(c.Request.Context(), "SearchHandler.HandleSearchStream")
+	defer span.End()
+
+	log := utils.LoggerFromContext(spanCtx, h.logger)
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Query parameter 'q' is required", nil)
+		return
+	}
+	if len(query) > 2000 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Query too long (max 2000 characters)", nil)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	limit = services.ClampLimit(limit)
+	summarize, _ := strconv.ParseBool(c.Query("summarize"))
+
+	userSession := h.getUserSession(c)
+	trackCtx := context.WithoutCancel(spanCtx)
+	userAgent := c.GetHeader("User-Agent")
+	clientIP := c.ClientIP()
+
+	ctx, cancel := context.WithTimeout(spanCtx, h.config().StreamTimeout)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // keep reverse proxies from buffering events
+	c.Status(http.StatusOK)
+
+	send := func(event string, data interface{}) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+		return true
+	}
+
+	analysis := h.searchService.Analyze(ctx, query, limit)
+	if !send(eventAnalysis, analysis) {
+		return
+	}
+
+	cacheKey := database.SearchCacheKey(query, limit)
+	finish := func(results []models.SearchResult, computedAt time.Time, freshness string) {
+		responseTime := time.Since(startTime)
+		final := newStreamResults(results)
+		final.Freshness = freshness
+		if !computedAt.IsZero() {
+			final.ComputedAt = &computedAt
+		}
+		if summarize {
+			final.Summary = h.searchService.Summarize(ctx, query, results)
+		}
+		send(eventFinal, final)
+		send(eventDone, gin.H{"response_time_ms": responseTime.Milliseconds()})
+
+		span.SetAttributes(
+			attribute.Int("search.results", len(results)),
+			attribute.String("cache.freshness", freshness),
+		)
+		go h.trackSearchQuery(trackCtx, userSession, query, len(results), responseTime, userAgent, clientIP)
+		go h.updatePopularQueries(trackCtx, query, len(results), responseTime)
+
+		log.WithFields(logrus.Fields{
+			"results_count": len(results),
+			"response_time": responseTime.Milliseconds(),
+			"freshness":     freshness,
+		}).Info("Streaming search completed")
+	}
+
+	// A fresh cache entry is the final answer; a stale one is shown while
+	// the search below refreshes it, and the refreshed results follow
+	cached, hasCached := h.searchCache.Peek(ctx, cacheKey)
+	if hasCached {
+		payload := newStreamResults(cached.Results)
+		payload.ComputedAt = &cached.ComputedAt
+		payload.Freshness = cached.Freshness
+		if !send(eventCached, payload) {
+			return
+		}
+		if cached.Freshness == database.FreshnessFresh {
+			finish(cached.Results, cached.ComputedAt, cached.Freshness)
+			return
+		}
+	}
+
+	local, err := h.searchService.LocalSearch(ctx, analysis.Keywords, limit)
+	if err != nil {
+		log.WithError(err).Warn("Local keyword search failed")
+	} else if len(local) > 0 {
+		if !send(eventLocal, newStreamResults(local)) {
+			return
+		}
+	}
+
+	search := func(ctx context.Context) ([]models.SearchResult, error) {
+		return h.searchService.SearchForSolution(ctx, query, limit)
+	}
+	var lookup *database.SearchLookup
+	if hasCached {
+		// Get would hand back the same stale entry, so wait for the refresh
+		lookup, err = h.searchCache.Refresh(ctx, cacheKey, search)
+	} else {
+		lookup, err = h.searchCache.Get(ctx, cacheKey, search)
+	}
+	if err != nil {
+		if c.Request.Context().Err() != nil {
+			log.Debug("Client disconnected from streaming search")
+			return
+		}
+
+		log.WithError(err).Error("Streaming search failed")
+		tracing.Fail(span, err)
+		send(eventError, gin.H{"message": "Search failed", "error": err.Error()})
+
+		// Fall back to whatever was already shown
+		var fallback []models.SearchResult
+		if hasCached {
+			fallback = cached.Results
+		}
+		finish(h.searchService.MergeResults(fallback, local, limit), time.Time{}, "")
+		return
+	}
+
+	payload := newStreamResults(lookup.Results)
+	payload.ComputedAt = &lookup.ComputedAt
+	payload.Freshness = lookup.Freshness
+	if !send(eventResults, payload) {
+		return
+	}
+
+	finish(h.searchService.MergeResults(lookup.Results, local, limit), lookup.ComputedAt, lookup.Freshness)
+}
@@ -0,0 +1,17 @@
+// backend/internal/cli/cli.go
+
+// Package cli implements the commands shared by the standalone binaries and
+// the server binary, which runs them as subcommands so one self-contained
+// binary can migrate, seed and serve.
+package cli
+
+import "errors"
+
+// errUsage is returned after a command printed its usage for bad arguments
+var errUsage = errors.New("invalid usage")
+
+// IsUsage reports whether err means the command line was wrong and the
+// usage has already been printed
+func IsUsage(err error) bool {
+	return errors.Is(err, errUsage)
+}
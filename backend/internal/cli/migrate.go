@@ -0,0 +1,207 @@
+// backend/internal/cli/migrate.go
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/config"
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/migration"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/migrations"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = `Usage: %s [flags] <command>
+
+Commands:
+  status          List migrations and when they were applied
+  up              Apply pending migrations
+  down <version>  Roll back migrations newer than version; 0 rolls back all
+  drift           Compare the database schema with the models; exits 1 on differences
+
+Flags:
+`
+
+// Migrate shows, applies and rolls back schema migrations. name is the
+// command as typed, for usage messages.
+func Migrate(name string, args []string) error {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	dir := flags.String("dir", "", "Directory holding NNN_name.sql migrations (default database.migrations_dir, else the embedded ones)")
+	dryRun := flags.Bool("dry-run", false, "Print the migrations that would run without running them")
+	verbose := flags.Bool("verbose", false, "Log every statement executed")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), migrateUsage, name)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	var target int64
+	switch args[0] {
+	case "status", "up", "drift":
+	case "down":
+		if len(args) != 2 {
+			return fmt.Errorf("down needs the version to roll back to, e.g. \"%s down 2\"", name)
+		}
+		var err error
+		if target, err = strconv.ParseInt(args[1], 10, 64); err != nil || target < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+	default:
+		fmt.Fprintf(flags.Output(), "unknown command %q\n\n", args[0])
+		flags.Usage()
+		return errUsage
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if *dir == "" {
+		*dir = cfg.Database.MigrationsDir
+	}
+
+	logger := utils.GetLogger()
+	logger.SetLevel(logrus.InfoLevel)
+	if *verbose {
+		logger.SetLevel(logrus.DebugLevel)
+	}
+
+	// Migrations only need Postgres
+	dbManager, err := database.NewManager(&database.Config{
+		DatabaseURL:  cfg.Database.URL,
+		CacheBackend: database.CacheBackendNone,
+		LogLevel:     "warn",
+	}, logger)
+	if err != nil {
+		return err
+	}
+	defer dbManager.Close()
+
+	runner := migration.NewRunner(dbManager, migrations.Source(*dir), logger)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "status":
+		return migrationStatus(ctx, runner)
+	case "up":
+		return migrateUp(ctx, runner, *dryRun)
+	case "drift":
+		return migrationDrift(ctx, runner)
+	default:
+		return migrateDown(ctx, runner, target, *dryRun)
+	}
+}
+
+func migrationStatus(ctx context.Context, runner *migration.Runner) error {
+	list, err := runner.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range list {
+		state, appliedAt := "pending", ""
+		if s.Applied != nil {
+			state = "applied"
+			appliedAt = s.Applied.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		switch {
+		case s.Migration == nil:
+			state = "applied, missing on disk"
+		case s.Modified():
+			state = "applied, modified since"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+func migrateUp(ctx context.Context, runner *migration.Runner, dryRun bool) error {
+	if dryRun {
+		todo, err := runner.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		return printPlan("apply", todo)
+	}
+
+	applied, err := runner.Up(ctx)
+	fmt.Printf("Applied %d migration(s)\n", len(applied))
+	return err
+}
+
+func migrateDown(ctx context.Context, runner *migration.Runner, target int64, dryRun bool) error {
+	if dryRun {
+		todo, err := runner.Rollbacks(ctx, target)
+		if err != nil {
+			return err
+		}
+		return printPlan("roll back", todo)
+	}
+
+	reverted, err := runner.DownTo(ctx, target)
+	fmt.Printf("Rolled back %d migration(s)\n", len(reverted))
+	return err
+}
+
+// migrationDrift reports where the database differs from the models. Run
+// after "up" on a fresh database, it fails when a model changed without a
+// migration to match.
+func migrationDrift(ctx context.Context, runner *migration.Runner) error {
+	list, err := runner.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range list {
+		if s.Migration != nil && s.Applied == nil {
+			fmt.Printf("Note: %s is not applied yet\n", s.Name)
+		}
+	}
+
+	diffs, err := runner.Drift(ctx, models.Tables)
+	if err != nil {
+		return err
+	}
+	if len(diffs) == 0 {
+		fmt.Println("Database schema matches the models")
+		return nil
+	}
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	return fmt.Errorf("%d difference(s) between the database schema and the models", len(diffs))
+}
+
+// printPlan lists what a dry run would do
+func printPlan(action string, migrations []migration.Migration) error {
+	if len(migrations) == 0 {
+		fmt.Printf("Nothing to %s\n", action)
+		return nil
+	}
+	fmt.Printf("Would %s:\n", action)
+	for _, m := range migrations {
+		fmt.Printf("  %s\n", m.Name)
+	}
+	return nil
+}
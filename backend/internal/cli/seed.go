@@ -0,0 +1,354 @@
+// backend/internal/cli/seed.go
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/alchemyst"
+	"github.com/Ayash-Bera/ophelia/backend/internal/config"
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
+	"github.com/Ayash-Bera/ophelia/backend/internal/seeder"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+// ContentSeeder crawls the configured wiki pages into the index
+type ContentSeeder struct {
+	crawler      *seeder.Crawler
+	cache        *database.Cache
+	logger       *logrus.Logger
+	dryRun       bool
+	pageLimit    int
+	processed    map[string]bool
+	newPages     int
+	changedPages int
+	errors       []error
+}
+
+var (
+	// High-priority Arch Wiki pages with common troubleshooting content
+	ArchWikiPages = []seeder.Page{
+		// Core troubleshooting (Priority 10-9)
+		{Title: "General_troubleshooting", Priority: 10, URL: "https://wiki.archlinux.org/title/General_troubleshooting"},
+		{Title: "Installation_guide", Priority: 10, URL: "https://wiki.archlinux.org/title/Installation_guide"},
+		{Title: "System_maintenance", Priority: 9, URL: "https://wiki.archlinux.org/title/System_maintenance"},
+		{Title: "System_recovery", Priority: 9, URL: "https://wiki.archlinux.org/title/System_recovery"},
+		{Title: "Locale", Priority: 7, URL: "https://wiki.archlinux.org/title/Locale"},
+		{Title: "Time_zone", Priority: 6, URL: "https://wiki.archlinux.org/title/System_time#Time_zone"},
+		{Title: "Users_and_groups", Priority: 6, URL: "https://wiki.archlinux.org/title/Users_and_groups"},
+
+		// Package management (Priority 9-8)
+		{Title: "Pacman", Priority: 9, URL: "https://wiki.archlinux.org/title/Pacman"},
+		// {Title: "Pacman_troubleshooting", Priority: 9, URL: "https://wiki.archlinux.org/title/Pacman/Troubleshooting"},
+		{Title: "AUR", Priority: 8, URL: "https://wiki.archlinux.org/title/Arch_User_Repository"},
+		{Title: "makepkg", Priority: 8, URL: "https://wiki.archlinux.org/title/Makepkg"},
+
+		// Network (Priority 8-7)
+		{Title: "Hardware_video_acceleration", Priority: 7, URL: "https://wiki.archlinux.org/title/Hardware_video_acceleration"},
+		{Title: "Webcam_setup", Priority: 6, URL: "https://wiki.archlinux.org/title/Webcam_setup"},
+		{Title: "NetworkManager", Priority: 8, URL: "https://wiki.archlinux.org/title/NetworkManager"},
+		{Title: "Network_configuration", Priority: 7, URL: "https://wiki.archlinux.org/title/Network_configuration"},
+		{Title: "Wireless_network_configuration", Priority: 7, URL: "https://wiki.archlinux.org/title/Wireless_network_configuration"},
+		{Title: "OpenVPN", Priority: 6, URL: "https://wiki.archlinux.org/title/OpenVPN"},
+
+		// Graphics (Priority 8-6)
+		{Title: "Xorg", Priority: 8, URL: "https://wiki.archlinux.org/title/Xorg"},
+		{Title: "NVIDIA", Priority: 7, URL: "https://wiki.archlinux.org/title/NVIDIA"},
+		{Title: "NVIDIA_troubleshooting", Priority: 7, URL: "https://wiki.archlinux.org/title/NVIDIA/Troubleshooting"},
+		{Title: "AMDGPU", Priority: 7, URL: "https://wiki.archlinux.org/title/AMDGPU"},
+		{Title: "Intel_graphics", Priority: 6, URL: "https://wiki.archlinux.org/title/Intel_graphics"},
+		{Title: "Wayland", Priority: 6, URL: "https://wiki.archlinux.org/title/Wayland"},
+
+		// Audio (Priority 7-6)
+		{Title: "Advanced_Linux_Sound_Architecture", Priority: 7, URL: "https://wiki.archlinux.org/title/Advanced_Linux_Sound_Architecture"},
+		{Title: "PulseAudio", Priority: 6, URL: "https://wiki.archlinux.org/title/PulseAudio"},
+		{Title: "PulseAudio_troubleshooting", Priority: 6, URL: "https://wiki.archlinux.org/title/PulseAudio/Troubleshooting"},
+		{Title: "PipeWire", Priority: 6, URL: "https://wiki.archlinux.org/title/PipeWire"},
+
+		// Boot/System (Priority 7-6)
+		{Title: "GRUB", Priority: 7, URL: "https://wiki.archlinux.org/title/GRUB"},
+		{Title: "Systemd", Priority: 7, URL: "https://wiki.archlinux.org/title/Systemd"},
+		{Title: "Kernel_parameters", Priority: 6, URL: "https://wiki.archlinux.org/title/Kernel_parameters"},
+		{Title: "Fstab", Priority: 6, URL: "https://wiki.archlinux.org/title/Fstab"},
+		{Title: "Arch_boot_process", Priority: 6, URL: "https://wiki.archlinux.org/title/Arch_boot_process"},
+
+		// Hardware (Priority 6-5)
+		{Title: "Bluetooth", Priority: 6, URL: "https://wiki.archlinux.org/title/Bluetooth"},
+		{Title: "Power_management", Priority: 5, URL: "https://wiki.archlinux.org/title/Power_management"},
+		{Title: "Laptop", Priority: 5, URL: "https://wiki.archlinux.org/title/Laptop"},
+		{Title: "Hardware_video_acceleration", Priority: 5, URL: "https://wiki.archlinux.org/title/Hardware_video_acceleration"},
+
+		// Desktop Environments (Priority 6-5)
+		{Title: "GNOME", Priority: 6, URL: "https://wiki.archlinux.org/title/GNOME"},
+		{Title: "GNOME_troubleshooting", Priority: 6, URL: "https://wiki.archlinux.org/title/GNOME/Troubleshooting"},
+		{Title: "KDE", Priority: 5, URL: "https://wiki.archlinux.org/title/KDE"},
+		{Title: "Xfce", Priority: 5, URL: "https://wiki.archlinux.org/title/Xfce"},
+
+		// Gaming (Priority 5-4)
+		{Title: "Git", Priority: 5, URL: "https://wiki.archlinux.org/title/Git"},
+		{Title: "Programming_languages", Priority: 4, URL: "https://wiki.archlinux.org/title/List_of_applications#Programming_languages"},
+		{Title: "Steam", Priority: 5, URL: "https://wiki.archlinux.org/title/Steam"},
+		{Title: "Steam_troubleshooting", Priority: 5, URL: "https://wiki.archlinux.org/title/Steam/Troubleshooting"},
+		{Title: "Gaming", Priority: 4, URL: "https://wiki.archlinux.org/title/Gaming"},
+
+		// Services & Virtualization (Priority 5-4)
+		{Title: "OpenSSH", Priority: 5, URL: "https://wiki.archlinux.org/title/OpenSSH"},
+		{Title: "Docker", Priority: 4, URL: "https://wiki.archlinux.org/title/Docker"},
+		{Title: "VirtualBox", Priority: 4, URL: "https://wiki.archlinux.org/title/VirtualBox"},
+
+		// Printing & Multimedia (Priority 4-3)
+		{Title: "CUPS", Priority: 4, URL: "https://wiki.archlinux.org/title/CUPS"},
+		{Title: "CUPS_troubleshooting", Priority: 4, URL: "https://wiki.archlinux.org/title/CUPS/Troubleshooting"},
+		{Title: "Firefox", Priority: 3, URL: "https://wiki.archlinux.org/title/Firefox"},
+		{Title: "Chromium", Priority: 3, URL: "https://wiki.archlinux.org/title/Chromium"},
+
+		// File Systems & Storage (Priority 4-3)
+		{Title: "File_systems", Priority: 4, URL: "https://wiki.archlinux.org/title/File_systems"},
+		{Title: "USB_storage_devices", Priority: 3, URL: "https://wiki.archlinux.org/title/USB_storage_devices"},
+		{Title: "Solid_state_drive", Priority: 3, URL: "https://wiki.archlinux.org/title/Solid_state_drive"},
+	}
+)
+
+// Seed crawls the configured Arch Wiki pages into Alchemyst and records
+// them in Postgres. name is the command as typed, for usage messages.
+func Seed(name string, args []string) error {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "Don't upload to Alchemyst, just print what would be uploaded")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	pageLimit := flags.Int("limit", 0, "Limit number of pages to process (0 = all)")
+	flags.Int("concurrent", 2, "Number of concurrent requests (unused, pages are crawled one at a time)")
+	delay := flags.Duration("delay", 2*time.Second, "Delay between requests")
+	flags.Parse(args)
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found: %v", err)
+	}
+
+	// Initialize logger
+	logger := utils.GetLogger()
+	if *verbose {
+		logger.SetLevel(logrus.DebugLevel)
+	}
+
+	logger.Info("Starting Arch Wiki content seeder...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var alchemystService *alchemyst.Service
+	var repoManager *repository.RepositoryManager
+	var cache *database.Cache
+
+	if !*dryRun {
+		// Validate Alchemyst configuration
+		if err := cfg.ValidateAlchemyst(); err != nil {
+			return fmt.Errorf("alchemyst configuration validation failed: %w", err)
+		}
+
+		// Initialize database for tracking
+		dbConfig := &database.Config{
+			DatabaseURL:     cfg.Database.URL,
+			RedisURL:        cfg.Redis.URL,
+			CacheBackend:    cfg.Cache.Backend,
+			CacheMaxEntries: cfg.Cache.MaxEntries,
+			LogLevel:        cfg.Logging.Level,
+
+			MaxOpenConns:       cfg.Database.MaxOpenConns,
+			MaxIdleConns:       cfg.Database.MaxIdleConns,
+			ConnMaxLifetime:    cfg.Database.ConnMaxLifetime,
+			ConnMaxIdleTime:    cfg.Database.ConnMaxIdleTime,
+			SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+			Redis: database.RedisOptions{
+				PoolSize:     cfg.Redis.PoolSize,
+				MinIdleConns: cfg.Redis.MinIdleConns,
+				MaxConnAge:   cfg.Redis.MaxConnAge,
+				IdleTimeout:  cfg.Redis.IdleTimeout,
+			},
+		}
+
+		dbManager, err := database.NewManager(dbConfig, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database manager: %w", err)
+		}
+		defer dbManager.Close()
+
+		repoManager = repository.NewRepositoryManager(dbManager.DB)
+
+		// Shares the server's cache so changed pages drop stale search results
+		cache = database.NewCache(dbManager.Cache, cfg.Cache.Namespace, logger)
+
+		// Initialize Alchemyst client and service
+		alchemystClient := alchemyst.NewClient(alchemyst.ClientConfig{
+			BaseURL: cfg.Alchemyst.BaseURL,
+			APIKey:  cfg.Alchemyst.APIKey,
+			Timeout: cfg.Alchemyst.Timeout,
+			Retry: alchemyst.RetryConfig{
+				MaxRetries: cfg.Alchemyst.MaxRetries,
+				BaseDelay:  cfg.Alchemyst.RetryBaseDelay,
+				MaxDelay:   cfg.Alchemyst.RetryMaxDelay,
+			},
+		}, logger)
+		alchemystService = alchemyst.NewService(alchemystClient, logger)
+	}
+
+	// Create content seeder
+	contentSeeder := NewContentSeeder(alchemystService, repoManager, cache, SeedOptions{
+		DryRun: *dryRun,
+		Limit:  *pageLimit,
+		Delay:  *delay,
+	}, logger)
+
+	// Process pages
+	ctx := context.Background()
+	if err := contentSeeder.SeedContent(ctx); err != nil {
+		return fmt.Errorf("content seeding failed: %w", err)
+	}
+
+	logger.Info("Content seeding completed successfully!")
+	return nil
+}
+
+// SeedOptions are the seed command's flags
+type SeedOptions struct {
+	DryRun bool          // fetch pages without storing or uploading them
+	Limit  int           // only the highest priority pages; 0 for all
+	Delay  time.Duration // between requests to the wiki
+}
+
+func NewContentSeeder(alchemystService *alchemyst.Service, repoManager *repository.RepositoryManager, cache *database.Cache, opts SeedOptions, logger *logrus.Logger) *ContentSeeder {
+	crawler := seeder.NewCrawler(alchemystService, repoManager, cache, seeder.CrawlerConfig{
+		Delay:   opts.Delay,
+		Timeout: 30 * time.Second,
+	}, logger)
+
+	return &ContentSeeder{
+		crawler:   crawler,
+		cache:     cache,
+		logger:    logger,
+		dryRun:    opts.DryRun,
+		pageLimit: opts.Limit,
+		processed: make(map[string]bool),
+		errors:    make([]error, 0),
+	}
+}
+
+func (cs *ContentSeeder) SeedContent(ctx context.Context) error {
+	cs.logger.Info("Starting content seeding process...")
+
+	// Sort pages by priority
+	pages := make([]seeder.Page, len(ArchWikiPages))
+	copy(pages, ArchWikiPages)
+
+	// Sort by priority (descending) - using a simple bubble sort for clarity
+	for i := 0; i < len(pages)-1; i++ {
+		for j := i + 1; j < len(pages); j++ {
+			if pages[i].Priority < pages[j].Priority {
+				pages[i], pages[j] = pages[j], pages[i]
+			}
+		}
+	}
+
+	// Apply page limit if specified
+	if cs.pageLimit > 0 && cs.pageLimit < len(pages) {
+		pages = pages[:cs.pageLimit]
+		cs.logger.WithField("limit", cs.pageLimit).Info("Limited pages to process")
+	}
+
+	cs.logger.WithField("total_pages", len(pages)).Info("Processing wiki pages")
+
+	// Process each page
+	for i, page := range pages {
+		cs.logger.WithFields(logrus.Fields{
+			"page":     page.Title,
+			"priority": page.Priority,
+			"progress": fmt.Sprintf("%d/%d", i+1, len(pages)),
+		}).Info("Processing page")
+
+		if err := cs.processPage(ctx, page); err != nil {
+			cs.logger.WithError(err).WithField("page", page.Title).Error("Failed to process page")
+			cs.errors = append(cs.errors, fmt.Errorf("failed to process %s: %w", page.Title, err))
+			continue
+		}
+
+		cs.processed[page.Title] = true
+		cs.logger.WithField("page", page.Title).Info("Page processed successfully")
+
+		// Small delay between pages
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	if cs.cache != nil {
+		// Any cached search could now have a better answer on a new page
+		if cs.newPages > 0 {
+			if _, err := cs.cache.BumpGeneration(ctx); err != nil {
+				cs.logger.WithError(err).Warn("Failed to invalidate search cache for new pages")
+			}
+		}
+		// Let running servers know to re-warm popular queries
+		if cs.newPages > 0 || cs.changedPages > 0 {
+			if err := cs.cache.MarkContentChanged(ctx); err != nil {
+				cs.logger.WithError(err).Warn("Failed to signal content change")
+			}
+		}
+	}
+
+	// Report results
+	cs.logger.WithFields(logrus.Fields{
+		"processed": len(cs.processed),
+		"errors":    len(cs.errors),
+	}).Info("Content seeding completed")
+
+	if len(cs.errors) > 0 {
+		cs.logger.Warn("Some pages failed to process:")
+		for _, err := range cs.errors {
+			cs.logger.WithError(err).Warn("Processing error")
+		}
+	}
+
+	return nil
+}
+
+func (cs *ContentSeeder) processPage(ctx context.Context, page seeder.Page) error {
+	if cs.dryRun {
+		doc, err := cs.crawler.Fetch(ctx, page)
+		if err != nil {
+			return err
+		}
+		cs.logger.WithFields(logrus.Fields{
+			"page":           page.Title,
+			"content_length": len(doc.Content),
+			"sections":       len(doc.Sections),
+			"error_patterns": len(doc.ErrorPatterns),
+			"hash":           doc.Hash[:8],
+		}).Info("DRY RUN: Would upload content")
+		return nil
+	}
+
+	change, err := cs.crawler.Crawl(ctx, page, false)
+	switch {
+	case errors.Is(err, seeder.ErrPageInactive):
+		cs.logger.WithField("page", page.Title).Info("Skipping deactivated page")
+		return nil
+	case err != nil:
+		return err
+	}
+
+	switch change {
+	case seeder.Modified:
+		cs.changedPages++
+	case seeder.Added:
+		cs.newPages++
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// backend/internal/client/client.go
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+)
+
+// Client calls the Ophelia search API for the command-line tools
+type Client struct {
+	baseURL    string
+	apiKey     string
+	userAgent  string
+	httpClient *http.Client
+}
+
+func New(baseURL, apiKey, userAgent string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		userAgent:  userAgent,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// APIError is a request the server answered with success=false
+type APIError struct {
+	StatusCode int
+	Message    string
+	Detail     string
+	RetryAfter string // Retry-After header, when the server sent one
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	return fmt.Sprintf("server returned HTTP %d: %s", e.StatusCode, message)
+}
+
+type apiResponse struct {
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	Data    models.SearchResponse `json:"data"`
+	Error   string                `json:"error"`
+}
+
+// Search runs one query against POST /api/v1/search
+func (c *Client) Search(ctx context.Context, request models.SearchRequest) (*models.SearchResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/search", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("search request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("unexpected response from server (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !decoded.Success {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    decoded.Message,
+			Detail:     decoded.Error,
+			RetryAfter: resp.Header.Get("Retry-After"),
+		}
+	}
+	return &decoded.Data, nil
+}
@@ -0,0 +1,132 @@
+// backend/internal/config/reload.go
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// watchInterval is how often the config file is checked for changes
+const watchInterval = 2 * time.Second
+
+// Reloader applies changes to reloadable settings (rate limits, TTLs,
+// thresholds, log levels) while the server runs. It reloads when the config
+// file changes or the process receives SIGHUP. Changes to other settings are
+// reported and wait for a restart. Environment variables still take
+// precedence over the file, and are only read at startup.
+type Reloader struct {
+	mu       sync.Mutex // serializes reloads
+	current  atomic.Pointer[Config]
+	onReload []func(*Config)
+	logger   *logrus.Logger
+}
+
+func NewReloader(cfg *Config, logger *logrus.Logger) *Reloader {
+	r := &Reloader{logger: logger}
+	r.current.Store(cfg)
+	return r
+}
+
+// Current returns the configuration in effect
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// OnReload registers fn to apply a reloaded configuration. Register
+// callbacks before calling Start.
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.onReload = append(r.onReload, fn)
+}
+
+// Start watches for SIGHUP and config file changes until ctx is done
+func (r *Reloader) Start(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	path := viper.ConfigFileUsed()
+	modified := modTime(path)
+	if path != "" {
+		r.logger.WithField("file", path).Info("Watching config file for changes")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			r.logger.Info("Received SIGHUP, reloading configuration")
+			r.Reload()
+		case <-ticker.C:
+			if path == "" {
+				continue
+			}
+			if latest := modTime(path); !latest.Equal(modified) {
+				modified = latest
+				r.logger.WithField("file", path).Info("Config file changed, reloading configuration")
+				r.Reload()
+			}
+		}
+	}
+}
+
+// Reload re-reads and validates the configuration and applies its
+// reloadable settings. An invalid configuration is rejected as a whole and
+// the current one stays in effect.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := readConfigFile(); err != nil {
+		r.logger.WithError(err).Error("Configuration reload failed, keeping the current configuration")
+		return err
+	}
+	next, err := build()
+	if err != nil {
+		r.logger.WithError(err).Error("Configuration reload rejected, keeping the current configuration")
+		return err
+	}
+
+	current := r.current.Load()
+	merged := current.withReloadable(next)
+	if pending := Changed(merged, next); len(pending) > 0 {
+		r.logger.WithField("settings", pending).Warn("Changed settings take effect after a restart")
+	}
+
+	applied := Changed(current, merged)
+	if len(applied) == 0 {
+		r.logger.Info("Configuration reloaded, no reloadable settings changed")
+		return nil
+	}
+
+	r.current.Store(merged)
+	for _, fn := range r.onReload {
+		fn(merged)
+	}
+	r.logger.WithField("settings", applied).Info("Configuration reloaded")
+	return nil
+}
+
+// modTime returns when path was last modified, or the zero time when it
+// cannot be read
+func modTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
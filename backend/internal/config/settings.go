@@ -0,0 +1,193 @@
+// backend/internal/config/settings.go
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// setting is one field of a Config section
+type setting struct {
+	section string
+	key     string // section.field, as used in the config file
+	value   reflect.Value
+	secret  bool
+	reload  bool
+}
+
+// settings lists the fields of cfg in declaration order
+func settings(cfg *Config) []setting {
+	var list []setting
+	root := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		sectionField := root.Type().Field(i)
+		section, _ := parseTag(sectionField)
+		sectionValue := root.Field(i)
+		for j := 0; j < sectionValue.NumField(); j++ {
+			field := sectionValue.Type().Field(j)
+			name, options := parseTag(field)
+			list = append(list, setting{
+				section: section,
+				key:     section + "." + name,
+				value:   sectionValue.Field(j),
+				secret:  options["secret"],
+				reload:  options["reload"],
+			})
+		}
+	}
+	return list
+}
+
+// parseTag returns a field's setting name and the options of its config tag
+func parseTag(field reflect.StructField) (string, map[string]bool) {
+	name, rest, _ := strings.Cut(field.Tag.Get("config"), ",")
+	if name == "" {
+		name = snakeCase(field.Name)
+	}
+	options := make(map[string]bool)
+	for _, option := range strings.Split(rest, ",") {
+		if option != "" {
+			options[option] = true
+		}
+	}
+	return name, options
+}
+
+// snakeCase turns a field name such as IPHashSalt into ip_hash_salt
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Print writes the configuration as YAML that Load accepts as a config
+// file. Secrets are redacted: passwords inside URLs are masked and other
+// secrets replaced entirely.
+func (c *Config) Print(w io.Writer) error {
+	section := ""
+	for _, s := range settings(c) {
+		if s.section != section {
+			section = s.section
+			if _, err := fmt.Fprintf(w, "%s:\n", section); err != nil {
+				return err
+			}
+		}
+		value := formatValue(s.value)
+		if s.secret {
+			value = redact(s.value.String())
+		}
+		if _, err := fmt.Fprintf(w, "  %s: %s\n", strings.TrimPrefix(s.key, section+"."), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch value := v.Interface().(type) {
+	case time.Duration:
+		return strconv.Quote(formatDuration(value))
+	case string:
+		return strconv.Quote(value)
+	case []string:
+		return strconv.Quote(strings.Join(value, ","))
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// formatDuration drops the zero units time.Duration.String adds: 1h, not 1h0m0s
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// redact hides a secret. URLs keep everything but their password, in the
+// userinfo or in query parameters such as postgres' sslpassword, so the
+// host a secret points at can still be checked.
+func redact(secret string) string {
+	if secret == "" {
+		return `""`
+	}
+	if u, err := url.Parse(secret); err == nil && u.Scheme != "" && u.Host != "" {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		}
+		u.RawQuery = redactQuery(u.RawQuery)
+		return strconv.Quote(u.String())
+	}
+	return `"[redacted]"`
+}
+
+// secretParams are the query parameters connection URLs carry secrets in
+var secretParams = map[string]bool{
+	"password":    true,
+	"sslpassword": true,
+	"sslkey":      true,
+}
+
+// redactQuery replaces the values of secretParams, keeping the order and
+// encoding of everything else
+func redactQuery(query string) string {
+	if query == "" {
+		return ""
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, hasValue := strings.Cut(param, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if hasValue && secretParams[strings.ToLower(name)] {
+			params[i] = key + "=xxxxx"
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// Changed lists the settings whose values differ between two configurations
+func Changed(old, next *Config) []string {
+	var keys []string
+	nextSettings := settings(next)
+	for i, s := range settings(old) {
+		if !reflect.DeepEqual(s.value.Interface(), nextSettings[i].value.Interface()) {
+			keys = append(keys, s.key)
+		}
+	}
+	return keys
+}
+
+// withReloadable returns a copy of c with the reloadable settings of next
+func (c *Config) withReloadable(next *Config) *Config {
+	merged := *c
+	nextSettings := settings(next)
+	for i, s := range settings(&merged) {
+		if s.reload {
+			s.value.Set(nextSettings[i].value)
+		}
+	}
+	return &merged
+}
@@ -0,0 +1,275 @@
+// backend/internal/config/validate.go
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+)
+
+// minIPHashSaltLength is the shortest privacy.ip_hash_salt accepted in hash mode
+const minIPHashSaltLength = 16
+
+// Rate is a rate limit written as limit/period[:burst]
+type Rate struct {
+	Limit  int // requests allowed per Period once the burst is spent
+	Period time.Duration
+	Burst  int // requests allowed at once
+}
+
+// ParseRate reads a rate such as "30/1m:10" or "600/m". Period accepts a
+// duration or a bare unit (s, m, h). The burst defaults to the limit. "off"
+// or an empty spec disables the limit and returns ok false.
+func ParseRate(spec string) (rate Rate, ok bool, err error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "off" {
+		return Rate{}, false, nil
+	}
+
+	limit, burst, hasBurst := strings.Cut(spec, ":")
+	count, period, found := strings.Cut(limit, "/")
+	if !found {
+		return Rate{}, false, fmt.Errorf("%q is not limit/period[:burst]", spec)
+	}
+
+	if rate.Limit, err = strconv.Atoi(strings.TrimSpace(count)); err != nil || rate.Limit <= 0 {
+		return Rate{}, false, fmt.Errorf("invalid limit %q", count)
+	}
+
+	period = strings.TrimSpace(period)
+	duration := period
+	if period != "" && !strings.ContainsAny(period[:1], "0123456789") {
+		duration = "1" + period
+	}
+	if rate.Period, err = time.ParseDuration(duration); err != nil || rate.Period <= 0 {
+		return Rate{}, false, fmt.Errorf("invalid period %q", period)
+	}
+
+	rate.Burst = rate.Limit
+	if hasBurst {
+		if rate.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil || rate.Burst <= 0 {
+			return Rate{}, false, fmt.Errorf("invalid burst %q", burst)
+		}
+	}
+	return rate, true, nil
+}
+
+// ValidationError lists every invalid setting found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// validator collects problems under the setting they belong to, naming the
+// environment variable as well since most deployments set that
+type validator struct {
+	problems []string
+}
+
+func (v *validator) fail(key, format string, args ...interface{}) {
+	env := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	v.problems = append(v.problems, fmt.Sprintf("%s (%s): %s", key, env, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) positive(key string, d time.Duration) {
+	if d <= 0 {
+		v.fail(key, "must be a positive duration, got %s", d)
+	}
+}
+
+func (v *validator) atLeast(key string, value, min int) {
+	if value < min {
+		v.fail(key, "must be at least %d, got %d", min, value)
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) fraction(key string, value float64) {
+	if value < 0 || value > 1 {
+		v.fail(key, "must be between 0 and 1, got %g", value)
+	}
+}
+
+func (v *validator) rate(key, spec string) {
+	if _, _, err := ParseRate(spec); err != nil {
+		v.fail(key, "%v", err)
+	}
+}
+
+func (v *validator) url(key, value string, schemes ...string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.fail(key, "must be a URL with a host")
+		return
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+	v.fail(key, "scheme must be one of %s, got %q", strings.Join(schemes, ", "), u.Scheme)
+}
+
+// Validate checks every setting and reports all problems at once
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		v.fail("server.port", "must be a port number, got %q", c.Server.Port)
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				v.fail("server.trusted_proxies", "%q is not an IP address or CIDR", proxy)
+			}
+		}
+	}
+	v.positive("server.read_timeout", c.Server.ReadTimeout)
+	v.positive("server.write_timeout", c.Server.WriteTimeout)
+	v.positive("server.idle_timeout", c.Server.IdleTimeout)
+	v.positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	v.atLeast("server.max_header_bytes", c.Server.MaxHeaderBytes, 1024)
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" && c.CORS.AllowCredentials {
+			v.fail("cors.allowed_origins", "cannot be * while cors.allow_credentials is true")
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		v.fail("cors.max_age", "must not be negative")
+	}
+
+	v.oneOf("ratelimit.backend", c.RateLimit.Backend, "memory", "redis")
+	v.rate("ratelimit.global", c.RateLimit.Global)
+	v.rate("ratelimit.default", c.RateLimit.Default)
+	v.rate("ratelimit.search_ip", c.RateLimit.SearchIP)
+	v.rate("ratelimit.search_session", c.RateLimit.SearchSession)
+	v.rate("ratelimit.suggestions_ip", c.RateLimit.SuggestionsIP)
+	v.rate("ratelimit.feedback_ip", c.RateLimit.FeedbackIP)
+
+	for _, scope := range c.API.AnonymousScopes {
+		if !models.IsValidScope(scope) {
+			v.fail("api.anonymous_scopes", "unknown scope %q, want one of %s", scope, strings.Join(models.ValidScopes, ", "))
+		}
+	}
+	if c.API.KeyCacheTTL < 0 {
+		v.fail("api.key_cache_ttl", "must not be negative")
+	}
+	v.positive("api.usage_flush_interval", c.API.UsageFlushInterval)
+
+	v.positive("search.timeout", c.Search.Timeout)
+	v.positive("search.stream_timeout", c.Search.StreamTimeout)
+	if c.Search.StreamTimeout >= c.Server.WriteTimeout {
+		v.fail("search.stream_timeout", "must be shorter than server.write_timeout (%s) or streams are cut off", c.Server.WriteTimeout)
+	}
+
+	v.atLeast("batch.max_queries", c.Batch.MaxQueries, 1)
+	v.atLeast("batch.concurrency", c.Batch.Concurrency, 1)
+	v.positive("batch.timeout", c.Batch.Timeout)
+
+	v.url("database.url", c.Database.URL, "postgres", "postgresql")
+	v.atLeast("database.max_open_conns", c.Database.MaxOpenConns, 1)
+	v.atLeast("database.max_idle_conns", c.Database.MaxIdleConns, 0)
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		v.fail("database.max_idle_conns", "must not exceed database.max_open_conns (%d)", c.Database.MaxOpenConns)
+	}
+	v.positive("database.conn_max_lifetime", c.Database.ConnMaxLifetime)
+	v.positive("database.conn_max_idle_time", c.Database.ConnMaxIdleTime)
+	v.positive("database.slow_query_threshold", c.Database.SlowQueryThreshold)
+	if dir := c.Database.MigrationsDir; dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			v.fail("database.migrations_dir", "%q is not a directory", dir)
+		}
+	}
+
+	v.oneOf("cache.backend", c.Cache.Backend, "redis", "memory", "none")
+	if c.Cache.Backend == "redis" || c.RateLimit.Backend == "redis" {
+		v.url("redis.url", c.Redis.URL, "redis", "rediss")
+	}
+	v.atLeast("redis.pool_size", c.Redis.PoolSize, 1)
+	v.atLeast("redis.min_idle_conns", c.Redis.MinIdleConns, 0)
+	v.positive("redis.max_conn_age", c.Redis.MaxConnAge)
+	v.positive("redis.idle_timeout", c.Redis.IdleTimeout)
+
+	v.atLeast("cache.max_entries", c.Cache.MaxEntries, 1)
+	if c.Cache.Namespace == "" {
+		v.fail("cache.namespace", "must not be empty")
+	}
+	v.positive("cache.search_soft_ttl", c.Cache.SearchSoftTTL)
+	if c.Cache.SearchHardTTL < c.Cache.SearchSoftTTL {
+		v.fail("cache.search_hard_ttl", "must be at least cache.search_soft_ttl (%s)", c.Cache.SearchSoftTTL)
+	}
+	v.positive("cache.negative_ttl", c.Cache.NegativeTTL)
+	v.positive("cache.refresh_timeout", c.Cache.RefreshTimeout)
+
+	// The API key and base URL are checked by ValidateAlchemyst, since
+	// commands that never call Alchemyst run without them
+	if c.Alchemyst.BaseURL != "" {
+		v.url("alchemyst.base_url", c.Alchemyst.BaseURL, "http", "https")
+	}
+	v.positive("alchemyst.timeout", c.Alchemyst.Timeout)
+	v.atLeast("alchemyst.max_retries", c.Alchemyst.MaxRetries, 0)
+	v.positive("alchemyst.retry_base_delay", c.Alchemyst.RetryBaseDelay)
+	if c.Alchemyst.RetryMaxDelay < c.Alchemyst.RetryBaseDelay {
+		v.fail("alchemyst.retry_max_delay", "must be at least alchemyst.retry_base_delay (%s)", c.Alchemyst.RetryBaseDelay)
+	}
+	v.fraction("alchemyst.similarity_threshold", c.Alchemyst.SimilarityThreshold)
+	v.fraction("alchemyst.min_similarity_threshold", c.Alchemyst.MinSimilarityThreshold)
+	if c.Alchemyst.MinSimilarityThreshold > c.Alchemyst.SimilarityThreshold {
+		v.fail("alchemyst.min_similarity_threshold", "must not exceed alchemyst.similarity_threshold (%g)", c.Alchemyst.SimilarityThreshold)
+	}
+
+	v.positive("health.timeout", c.Health.Timeout)
+	v.positive("health.upstream_timeout", c.Health.UpstreamTimeout)
+
+	v.oneOf("logging.level", c.Logging.Level, "debug", "info", "warn", "error")
+	v.oneOf("logging.redact_queries", c.Logging.RedactQueries, "none", "partial", "full")
+	v.oneOf("logging.redact_ips", c.Logging.RedactIPs, "none", "partial", "full")
+
+	v.oneOf("privacy.ip_mode", c.Privacy.IPMode, "none", "truncate", "hash", "drop")
+	// Without a long salt every IPv4 hash can be reversed by trying all 2^32 addresses
+	if c.Privacy.IPMode == "hash" && len(c.Privacy.IPHashSalt) < minIPHashSaltLength {
+		v.fail("privacy.ip_hash_salt", "must be at least %d characters when privacy.ip_mode is hash", minIPHashSaltLength)
+	}
+	v.oneOf("privacy.retention_mode", c.Privacy.RetentionMode, "aggregate", "purge")
+	v.atLeast("privacy.search_query_days", c.Privacy.SearchQueryDays, 0)
+	v.atLeast("privacy.feedback_days", c.Privacy.FeedbackDays, 0)
+	v.positive("privacy.retention_interval", c.Privacy.RetentionInterval)
+
+	v.atLeast("warmer.top_n", c.Warmer.TopN, 1)
+	if c.Warmer.Rate <= 0 {
+		v.fail("warmer.rate", "must be positive, got %g", c.Warmer.Rate)
+	}
+	v.positive("warmer.poll_interval", c.Warmer.PollInterval)
+
+	v.atLeast("suggestions.max_entries", c.Suggestions.MaxEntries, 1)
+	v.positive("suggestions.refresh_interval", c.Suggestions.RefreshInterval)
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "stdout", "file", "otlp")
+	if c.Tracing.Exporter == "file" && c.Tracing.FilePath == "" {
+		v.fail("tracing.file_path", "is required by the file exporter")
+	}
+	v.fraction("tracing.sample_ratio", c.Tracing.SampleRatio)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
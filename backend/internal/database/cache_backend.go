@@ -0,0 +1,273 @@
+// backend/internal/database/cache_backend.go
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// Supported cache backends
+const (
+	CacheBackendRedis  = "redis"
+	CacheBackendMemory = "memory"
+	CacheBackendNone   = "none"
+)
+
+// ErrCacheMiss is returned by a Backend when a key is absent or expired
+var ErrCacheMiss = errors.New("cache miss")
+
+// Backend stores raw cache entries. Implementations must be safe for
+// concurrent use; Cache layers namespacing, key families, encoding and
+// tracing on top. There is deliberately no flush: a backend may be shared,
+// so the cache invalidates by moving to a new key generation instead.
+type Backend interface {
+	Name() string
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Incr atomically increments a counter, starting from zero
+	Incr(ctx context.Context, key string) (int64, error)
+	// SetAdd adds member to the set at key and resets the set's expiry
+	SetAdd(ctx context.Context, key, member string, expiration time.Duration) error
+	// SetPop removes the set at key and returns its members
+	SetPop(ctx context.Context, key string) ([]string, error)
+	// KeyStats counts keys starting with prefix and estimates their size
+	KeyStats(ctx context.Context, prefix string) (KeyStats, error)
+	Ping(ctx context.Context) error
+	Stats(ctx context.Context) (map[string]interface{}, error)
+	Close() error
+}
+
+// KeyStats describes the keys under a prefix
+type KeyStats struct {
+	Keys      int64 `json:"keys"`
+	Bytes     int64 `json:"bytes"`
+	Estimated bool  `json:"estimated"` // Bytes extrapolated from a sample
+}
+
+// evictionNotifier is implemented by backends that evict entries themselves
+// and can report which keys they dropped
+type evictionNotifier interface {
+	OnEvict(fn func(key string))
+}
+
+// CacheConfig selects and sizes the cache backend
+type CacheConfig struct {
+	Backend    string // redis, memory or none
+	RedisURL   string
+	Redis      RedisOptions
+	MaxEntries int // capacity of the memory backend
+}
+
+// NewCacheBackend builds the configured backend. An unreachable Redis is not
+// an error: the backend is returned anyway, reconnects on its own, and the
+// cache reports as degraded until it does.
+func NewCacheBackend(cfg CacheConfig, logger *logrus.Logger) (Backend, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "", CacheBackendRedis:
+		backend, err := NewRedisBackend(cfg.RedisURL, cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := backend.Ping(ctx); err != nil {
+			logger.WithError(err).Warn("Redis is unreachable, cache is degraded until it recovers")
+		}
+		return backend, nil
+
+	case CacheBackendMemory:
+		return NewMemoryBackend(cfg.MaxEntries), nil
+
+	case CacheBackendNone:
+		return NoopBackend{}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q (expected redis, memory or none)", cfg.Backend)
+	}
+}
+
+// RedisOptions sizes the Redis connection pool. A zero pool size or duration
+// keeps the default.
+type RedisOptions struct {
+	PoolSize     int
+	MinIdleConns int
+	MaxConnAge   time.Duration
+	IdleTimeout  time.Duration
+}
+
+// RedisBackend stores entries in Redis
+type RedisBackend struct {
+	client *redis.Client
+}
+
+func NewRedisBackend(url string, opts RedisOptions) (*RedisBackend, error) {
+	redisOpts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 20
+	}
+	if opts.MaxConnAge <= 0 {
+		opts.MaxConnAge = time.Hour
+	}
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = 30 * time.Minute
+	}
+
+	// Configure Redis connection pool
+	redisOpts.PoolSize = opts.PoolSize
+	redisOpts.MinIdleConns = opts.MinIdleConns
+	redisOpts.MaxConnAge = opts.MaxConnAge
+	redisOpts.IdleTimeout = opts.IdleTimeout
+	redisOpts.IdleCheckFrequency = 30 * time.Second
+
+	return &RedisBackend{client: redis.NewClient(redisOpts)}, nil
+}
+
+// Client exposes the underlying client for features that need Redis itself
+func (r *RedisBackend) Client() *redis.Client { return r.client }
+
+func (r *RedisBackend) Name() string { return CacheBackendRedis }
+
+func (r *RedisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrCacheMiss
+	}
+	return data, err
+}
+
+func (r *RedisBackend) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	return r.client.Set(ctx, key, value, expiration).Err()
+}
+
+func (r *RedisBackend) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+func (r *RedisBackend) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r *RedisBackend) SetAdd(ctx context.Context, key, member string, expiration time.Duration) error {
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, key, member)
+	if expiration > 0 {
+		pipe.Expire(ctx, key, expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisBackend) SetPop(ctx context.Context, key string) ([]string, error) {
+	pipe := r.client.TxPipeline()
+	members := pipe.SMembers(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return members.Val(), nil
+}
+
+// redisSampleSize bounds the MEMORY USAGE calls made per KeyStats call
+const redisSampleSize = 50
+
+func (r *RedisBackend) KeyStats(ctx context.Context, prefix string) (KeyStats, error) {
+	var stats KeyStats
+	var sampled, sampledBytes int64
+
+	iter := r.client.Scan(ctx, 0, prefix+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		stats.Keys++
+		if sampled < redisSampleSize {
+			if size, err := r.client.MemoryUsage(ctx, iter.Val()).Result(); err == nil {
+				sampled++
+				sampledBytes += size
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return KeyStats{}, err
+	}
+
+	if sampled > 0 {
+		stats.Bytes = sampledBytes * stats.Keys / sampled
+		stats.Estimated = sampled < stats.Keys
+	}
+	return stats, nil
+}
+
+func (r *RedisBackend) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *RedisBackend) Stats(ctx context.Context) (map[string]interface{}, error) {
+	info, err := r.client.Info(ctx, "stats", "memory", "clients").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"backend":           CacheBackendRedis,
+		"keyspace_hits":     extractRedisStat(info, "keyspace_hits"),
+		"keyspace_misses":   extractRedisStat(info, "keyspace_misses"),
+		"used_memory":       extractRedisStat(info, "used_memory"),
+		"evicted_keys":      extractRedisStat(info, "evicted_keys"),
+		"expired_keys":      extractRedisStat(info, "expired_keys"),
+		"connected_clients": extractRedisStat(info, "connected_clients"),
+	}, nil
+}
+
+func (r *RedisBackend) Close() error {
+	return r.client.Close()
+}
+
+func extractRedisStat(info, key string) string {
+	lines := strings.Split(info, "\r\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, key+":") {
+			return strings.TrimPrefix(line, key+":")
+		}
+	}
+	return "0"
+}
+
+// NoopBackend caches nothing; every read is a miss
+type NoopBackend struct{}
+
+func (NoopBackend) Name() string { return CacheBackendNone }
+
+func (NoopBackend) Get(context.Context, string) ([]byte, error) { return nil, ErrCacheMiss }
+
+func (NoopBackend) Set(context.Context, string, []byte, time.Duration) error { return nil }
+
+func (NoopBackend) Delete(context.Context, ...string) error { return nil }
+
+func (NoopBackend) Incr(context.Context, string) (int64, error) { return 0, nil }
+
+func (NoopBackend) SetAdd(context.Context, string, string, time.Duration) error { return nil }
+
+func (NoopBackend) SetPop(context.Context, string) ([]string, error) { return nil, nil }
+
+func (NoopBackend) KeyStats(context.Context, string) (KeyStats, error) { return KeyStats{}, nil }
+
+func (NoopBackend) Ping(context.Context) error { return nil }
+
+func (NoopBackend) Stats(context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{"backend": CacheBackendNone}, nil
+}
+
+func (NoopBackend) Close() error { return nil }
@@ -0,0 +1,146 @@
+// backend/internal/database/cache_keys.go
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCacheNamespace prefixes every cache key unless configured otherwise
+const DefaultCacheNamespace = "ophelia"
+
+// generationRefresh bounds how long an instance keeps using a generation
+// after another instance has bumped it
+const generationRefresh = 5 * time.Second
+
+const (
+	generationKey     = "generation"
+	contentVersionKey = "content_version"
+	pageTagKey        = "tag:page:%s"
+)
+
+// key places a family key under the namespace and current generation
+func (c *Cache) key(ctx context.Context, key string) string {
+	return fmt.Sprintf("%s:g%d:%s", c.namespace, c.currentGeneration(ctx), key)
+}
+
+// currentGeneration returns the generation, re-reading it from the backend
+// at most every generationRefresh. The generation never moves backwards, so
+// a counter lost by the backend cannot resurrect entries that were cleared.
+func (c *Cache) currentGeneration(ctx context.Context) int64 {
+	c.genMu.Lock()
+	defer c.genMu.Unlock()
+
+	if !c.genFetched.IsZero() && time.Since(c.genFetched) < generationRefresh {
+		return c.generation
+	}
+	c.genFetched = time.Now()
+
+	data, err := c.backend.Get(ctx, c.namespace+":"+generationKey)
+	if err != nil {
+		if err != ErrCacheMiss {
+			c.logger.WithError(err).Debug("Failed to read cache generation")
+		}
+		return c.generation
+	}
+	if stored, err := strconv.ParseInt(string(data), 10, 64); err == nil && stored > c.generation {
+		c.generation = stored
+	}
+	return c.generation
+}
+
+// Generation returns the generation keys are currently written under
+func (c *Cache) Generation(ctx context.Context) int64 {
+	return c.currentGeneration(ctx)
+}
+
+// BumpGeneration moves the namespace to a new generation, invalidating every
+// existing entry for all instances within generationRefresh
+func (c *Cache) BumpGeneration(ctx context.Context) (int64, error) {
+	next, err := c.backend.Incr(ctx, c.namespace+":"+generationKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bump cache generation: %w", err)
+	}
+
+	c.genMu.Lock()
+	if next <= c.generation {
+		// The backend lost its counter; stay ahead of what we have served
+		next = c.generation + 1
+		if err := c.backend.Set(ctx, c.namespace+":"+generationKey, []byte(strconv.FormatInt(next, 10)), 0); err != nil {
+			c.genMu.Unlock()
+			return 0, fmt.Errorf("failed to store cache generation: %w", err)
+		}
+	}
+	c.generation = next
+	c.genFetched = time.Now()
+	c.genMu.Unlock()
+
+	c.logger.WithField("generation", next).Info("Cache generation bumped")
+	return next, nil
+}
+
+// MarkContentChanged records that indexed content changed, so servers
+// sharing the backend know to re-warm their caches
+func (c *Cache) MarkContentChanged(ctx context.Context) error {
+	_, err := c.backend.Incr(ctx, c.namespace+":"+contentVersionKey)
+	return err
+}
+
+// ContentVersion returns a counter that grows each time content changes
+func (c *Cache) ContentVersion(ctx context.Context) (int64, error) {
+	data, err := c.backend.Get(ctx, c.namespace+":"+contentVersionKey)
+	if err == ErrCacheMiss {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// tag records fullKey under each page's tag set so InvalidatePage can find it
+func (c *Cache) tag(ctx context.Context, fullKey string, expiration time.Duration, pages ...string) error {
+	seen := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		tag := PageTag(page)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+
+		if err := c.backend.SetAdd(ctx, c.key(ctx, fmt.Sprintf(pageTagKey, tag)), fullKey, expiration); err != nil {
+			return fmt.Errorf("failed to tag cache entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// InvalidatePage drops every cached search whose results came from the page
+// and returns how many entries were removed
+func (c *Cache) InvalidatePage(ctx context.Context, page string) (int, error) {
+	tag := PageTag(page)
+	if tag == "" {
+		return 0, nil
+	}
+
+	keys, err := c.backend.SetPop(ctx, c.key(ctx, fmt.Sprintf(pageTagKey, tag)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read page tag: %w", err)
+	}
+	if err := c.backend.Delete(ctx, keys...); err != nil {
+		return 0, fmt.Errorf("failed to delete tagged entries: %w", err)
+	}
+	return len(keys), nil
+}
+
+// PageTag normalizes a wiki page title so "General troubleshooting" and
+// "General_troubleshooting" share a tag. The whole title is kept, since
+// subpages such as Pacman/Troubleshooting are pages of their own; only a
+// #section anchor is dropped.
+func PageTag(title string) string {
+	title, _, _ = strings.Cut(strings.TrimSpace(title), "#")
+	return strings.ToLower(strings.ReplaceAll(title, " ", "_"))
+}
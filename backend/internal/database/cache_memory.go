@@ -0,0 +1,218 @@
+// backend/internal/database/cache_memory.go
+package database
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryBackend is a bounded in-process LRU cache with per-entry expiry. It
+// suits single-instance deployments and tests that should not need Redis.
+type MemoryBackend struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front is most recently used
+	items      map[string]*list.Element
+	evictions  int64
+	onEvict    func(key string)
+}
+
+type memoryItem struct {
+	key       string
+	value     []byte
+	members   map[string]struct{} // set entries, nil for plain values
+	expiresAt time.Time           // zero means no expiry
+}
+
+func NewMemoryBackend(maxEntries int) *MemoryBackend {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+	return &MemoryBackend{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (m *MemoryBackend) Name() string { return CacheBackendMemory }
+
+func (m *MemoryBackend) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := m.lookup(key)
+	if item == nil || item.members != nil {
+		return nil, ErrCacheMiss
+	}
+	return item.value, nil
+}
+
+func (m *MemoryBackend) Set(_ context.Context, key string, value []byte, expiration time.Duration) error {
+	var expiresAt time.Time
+	if expiration > 0 {
+		expiresAt = time.Now().Add(expiration)
+	}
+
+	// Callers may reuse their buffer, so keep a private copy
+	stored := make([]byte, len(value))
+	copy(stored, value)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store(&memoryItem{key: key, value: stored, expiresAt: expiresAt})
+	return nil
+}
+
+func (m *MemoryBackend) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := m.items[key]; ok {
+			m.removeElement(elem)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryBackend) Incr(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var current int64
+	if item := m.lookup(key); item != nil && item.members == nil {
+		current, _ = strconv.ParseInt(string(item.value), 10, 64)
+	}
+	current++
+	m.store(&memoryItem{key: key, value: []byte(strconv.FormatInt(current, 10))})
+	return current, nil
+}
+
+func (m *MemoryBackend) SetAdd(_ context.Context, key, member string, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := m.lookup(key)
+	if item == nil || item.members == nil {
+		item = &memoryItem{key: key, members: make(map[string]struct{})}
+	}
+	item.members[member] = struct{}{}
+	item.expiresAt = time.Time{}
+	if expiration > 0 {
+		item.expiresAt = time.Now().Add(expiration)
+	}
+	m.store(item)
+	return nil
+}
+
+func (m *MemoryBackend) SetPop(_ context.Context, key string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := m.lookup(key)
+	if item == nil || item.members == nil {
+		return nil, nil
+	}
+	m.removeElement(m.items[key])
+
+	members := make([]string, 0, len(item.members))
+	for member := range item.members {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// OnEvict registers fn to be called with each key evicted for capacity.
+// fn runs with the backend locked and must not call back into it.
+func (m *MemoryBackend) OnEvict(fn func(key string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEvict = fn
+}
+
+func (m *MemoryBackend) KeyStats(_ context.Context, prefix string) (KeyStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stats KeyStats
+	now := time.Now()
+	for key, elem := range m.items {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		item := elem.Value.(*memoryItem)
+		if !item.expiresAt.IsZero() && now.After(item.expiresAt) {
+			continue
+		}
+		stats.Keys++
+		stats.Bytes += int64(len(key) + len(item.value))
+		for member := range item.members {
+			stats.Bytes += int64(len(member))
+		}
+	}
+	return stats, nil
+}
+
+func (m *MemoryBackend) Ping(context.Context) error { return nil }
+
+func (m *MemoryBackend) Stats(context.Context) (map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return map[string]interface{}{
+		"backend":     CacheBackendMemory,
+		"entries":     m.order.Len(),
+		"max_entries": m.maxEntries,
+		"evictions":   m.evictions,
+	}, nil
+}
+
+func (m *MemoryBackend) Close() error { return nil }
+
+// lookup returns the live item at key, marking it recently used; callers
+// hold the lock
+func (m *MemoryBackend) lookup(key string) *memoryItem {
+	elem, ok := m.items[key]
+	if !ok {
+		return nil
+	}
+	item := elem.Value.(*memoryItem)
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		m.removeElement(elem)
+		return nil
+	}
+	m.order.MoveToFront(elem)
+	return item
+}
+
+// store inserts or replaces an item and evicts the least recently used
+// entries beyond capacity; callers hold the lock
+func (m *MemoryBackend) store(item *memoryItem) {
+	if elem, ok := m.items[item.key]; ok {
+		elem.Value = item
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	m.items[item.key] = m.order.PushFront(item)
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.removeElement(oldest)
+		m.evictions++
+		if m.onEvict != nil {
+			m.onEvict(oldest.Value.(*memoryItem).key)
+		}
+	}
+}
+
+// removeElement drops an entry; callers hold the lock
+func (m *MemoryBackend) removeElement(elem *list.Element) {
+	m.order.Remove(elem)
+	delete(m.items, elem.Value.(*memoryItem).key)
+}
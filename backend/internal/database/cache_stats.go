@@ -0,0 +1,134 @@
+// backend/internal/database/cache_stats.go
+package database
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Cache key families, as used in spans and statistics
+const (
+	FamilySearchResults   = "search_results"
+	FamilyContentMetadata = "content_metadata"
+	FamilyPopularQueries  = "popular_queries"
+	FamilySystemHealth    = "system_health"
+	FamilyPageTags        = "page_tags"
+)
+
+// familyPrefixes maps each family to the start of its keys within a
+// generation
+var familyPrefixes = []struct {
+	family string
+	prefix string
+}{
+	{FamilySearchResults, "search:results:"},
+	{FamilyContentMetadata, "content:metadata:"},
+	{FamilyPopularQueries, PopularQueriesKey},
+	{FamilySystemHealth, SystemHealthKey},
+	{FamilyPageTags, "tag:page:"},
+}
+
+// familyCounters are the application-side counts for one key family
+type familyCounters struct {
+	hits      atomic.Int64
+	misses    atomic.Int64
+	sets      atomic.Int64
+	errors    atomic.Int64
+	evictions atomic.Int64
+}
+
+// FamilyStats reports activity and current size for one key family
+type FamilyStats struct {
+	Hits      int64    `json:"hits"`
+	Misses    int64    `json:"misses"`
+	HitRate   float64  `json:"hit_rate"`
+	Sets      int64    `json:"sets"`
+	Errors    int64    `json:"errors"`
+	Evictions int64    `json:"evictions"`
+	Keys      KeyStats `json:"size"`
+}
+
+// CacheStats is the snapshot served by the admin API
+type CacheStats struct {
+	Backend     string                 `json:"backend"`
+	Namespace   string                 `json:"namespace"`
+	Generation  int64                  `json:"generation"`
+	Since       time.Time              `json:"since"`
+	Families    map[string]FamilyStats `json:"families"`
+	BackendInfo map[string]interface{} `json:"backend_info,omitempty"`
+}
+
+func newFamilyCounters() map[string]*familyCounters {
+	counters := make(map[string]*familyCounters, len(familyPrefixes))
+	for _, fp := range familyPrefixes {
+		counters[fp.family] = &familyCounters{}
+	}
+	return counters
+}
+
+// counters returns the counters for family; unknown families are not tracked
+func (c *Cache) counters(family string) *familyCounters {
+	if fc, ok := c.families[family]; ok {
+		return fc
+	}
+	return &familyCounters{}
+}
+
+// recordEviction attributes a key dropped by the backend to its family
+func (c *Cache) recordEviction(key string) {
+	// Keys look like namespace:g<generation>:<family key>
+	parts := strings.SplitN(key, ":", 3)
+	if len(parts) != 3 || parts[0] != c.namespace {
+		return
+	}
+	for _, fp := range familyPrefixes {
+		if strings.HasPrefix(parts[2], fp.prefix) {
+			c.counters(fp.family).evictions.Add(1)
+			return
+		}
+	}
+}
+
+// Stats returns per-family counters since startup together with key counts
+// and size estimates for the current generation
+func (c *Cache) Stats(ctx context.Context) (*CacheStats, error) {
+	generation := c.currentGeneration(ctx)
+	stats := &CacheStats{
+		Backend:    c.backend.Name(),
+		Namespace:  c.namespace,
+		Generation: generation,
+		Since:      c.started,
+		Families:   make(map[string]FamilyStats, len(familyPrefixes)),
+	}
+
+	for _, fp := range familyPrefixes {
+		fc := c.counters(fp.family)
+		fs := FamilyStats{
+			Hits:      fc.hits.Load(),
+			Misses:    fc.misses.Load(),
+			Sets:      fc.sets.Load(),
+			Errors:    fc.errors.Load(),
+			Evictions: fc.evictions.Load(),
+		}
+		if lookups := fs.Hits + fs.Misses; lookups > 0 {
+			fs.HitRate = float64(fs.Hits) / float64(lookups)
+		}
+
+		keys, err := c.backend.KeyStats(ctx, c.key(ctx, fp.prefix))
+		if err != nil {
+			return nil, err
+		}
+		fs.Keys = keys
+
+		stats.Families[fp.family] = fs
+	}
+
+	// Server-wide numbers are informative but optional
+	if info, err := c.backend.Stats(ctx); err == nil {
+		stats.BackendInfo = info
+	}
+
+	return stats, nil
+}
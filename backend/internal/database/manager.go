@@ -6,12 +6,13 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/Ayash-Bera/ophelia/backend/internal/models"
-	"github.com/go-redis/redis/v8"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
@@ -20,19 +21,45 @@ import (
 // Database connection manager
 type Manager struct {
 	DB     *gorm.DB
-	Redis  *redis.Client
+	Cache  Backend
 	logger *logrus.Logger
 }
 
 // Database configuration
 type Config struct {
-	DatabaseURL string
-	RedisURL    string
-	LogLevel    string
+	DatabaseURL     string
+	RedisURL        string
+	CacheBackend    string // redis, memory or none
+	CacheMaxEntries int
+	LogLevel        string
+
+	// Connection pool; zero values keep the defaults
+	MaxOpenConns       int
+	MaxIdleConns       int
+	ConnMaxLifetime    time.Duration
+	ConnMaxIdleTime    time.Duration
+	SlowQueryThreshold time.Duration
+	Redis              RedisOptions
 }
 
 // NewManager creates a new database manager with connection pooling
 func NewManager(config *Config, logger *logrus.Logger) (*Manager, error) {
+	if config.MaxOpenConns <= 0 {
+		config.MaxOpenConns = 100
+	}
+	if config.MaxIdleConns <= 0 {
+		config.MaxIdleConns = 10
+	}
+	if config.ConnMaxLifetime <= 0 {
+		config.ConnMaxLifetime = time.Hour
+	}
+	if config.ConnMaxIdleTime <= 0 {
+		config.ConnMaxIdleTime = 10 * time.Minute
+	}
+	if config.SlowQueryThreshold <= 0 {
+		config.SlowQueryThreshold = 200 * time.Millisecond
+	}
+
 	// Configure GORM logger
 	var gormLogger gormlogger.Interface
 	switch config.LogLevel {
@@ -40,7 +67,7 @@ func NewManager(config *Config, logger *logrus.Logger) (*Manager, error) {
 		gormLogger = gormlogger.New(
 			log.New(os.Stdout, "\r\n", log.LstdFlags),
 			gormlogger.Config{
-				SlowThreshold:             200 * time.Millisecond,
+				SlowThreshold:             config.SlowQueryThreshold,
 				LogLevel:                  gormlogger.Info,
 				IgnoreRecordNotFoundError: true,
 				Colorful:                  false,
@@ -67,68 +94,41 @@ func NewManager(config *Config, logger *logrus.Logger) (*Manager, error) {
 	}
 
 	// Connection pool settings
-	sqlDB.SetMaxIdleConns(10)                // Maximum idle connections
-	sqlDB.SetMaxOpenConns(100)               // Maximum open connections
-	sqlDB.SetConnMaxLifetime(time.Hour)      // Connection lifetime
-	sqlDB.SetConnMaxIdleTime(10 * time.Minute) // Maximum idle time
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)       // Maximum idle connections
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)       // Maximum open connections
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime) // Connection lifetime
+	sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime) // Maximum idle time
 
 	// Test database connection
 	if err := sqlDB.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Connect to Redis
-	redisOpts, err := redis.ParseURL(config.RedisURL)
+	// Connect the cache; a missing Redis degrades the cache but not startup
+	cacheBackend, err := NewCacheBackend(CacheConfig{
+		Backend:    config.CacheBackend,
+		RedisURL:   config.RedisURL,
+		Redis:      config.Redis,
+		MaxEntries: config.CacheMaxEntries,
+	}, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
-	}
-
-	// Configure Redis connection pool
-	redisOpts.PoolSize = 20
-	redisOpts.MinIdleConns = 5
-	redisOpts.MaxConnAge = time.Hour
-	redisOpts.IdleTimeout = 30 * time.Minute
-	redisOpts.IdleCheckFrequency = 30 * time.Second
-
-	redisClient := redis.NewClient(redisOpts)
-
-	// Test Redis connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	
-	if err := redisClient.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		return nil, err
 	}
 
-	logger.Info("Database and Redis connections established successfully")
+	logger.WithField("cache_backend", cacheBackend.Name()).Info("Database and cache connections established successfully")
 
 	return &Manager{
 		DB:     db,
-		Redis:  redisClient,
+		Cache:  cacheBackend,
 		logger: logger,
 	}, nil
 }
 
-// Migrate runs database migrations
-func (m *Manager) Migrate() error {
-	m.logger.Info("Running database migrations...")
-	
-	return m.DB.AutoMigrate(
-		&models.SearchQuery{},
-		&models.UserFeedback{},
-		&models.ContentMetadata{},
-		&models.WikiSection{},
-		&models.SearchAnalytics{},
-		&models.PopularQuery{},
-		&models.SystemHealth{},
-	)
-}
-
 // Close closes all database connections
 func (m *Manager) Close() error {
-	if m.Redis != nil {
-		if err := m.Redis.Close(); err != nil {
-			m.logger.WithError(err).Error("Failed to close Redis connection")
+	if m.Cache != nil {
+		if err := m.Cache.Close(); err != nil {
+			m.logger.WithError(err).Error("Failed to close cache connection")
 		}
 	}
 
@@ -152,23 +152,43 @@ func (m *Manager) PingDatabase() error {
 	return sqlDB.Ping()
 }
 
-func (m *Manager) PingRedis() error {
+func (m *Manager) PingCache() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return m.Redis.Ping(ctx).Err()
+	return m.Cache.Ping(ctx)
 }
 
-// Cache implementation
+// Cache implementation. Every key lives under namespace:g<generation>: so
+// the cache can share a backend with other applications and be cleared
+// without touching their data.
 type Cache struct {
-	client *redis.Client
-	logger *logrus.Logger
+	backend   Backend
+	namespace string
+	logger    *logrus.Logger
+
+	genMu      sync.Mutex
+	generation int64
+	genFetched time.Time
+
+	families map[string]*familyCounters
+	started  time.Time
 }
 
-func NewCache(client *redis.Client, logger *logrus.Logger) *Cache {
-	return &Cache{
-		client: client,
-		logger: logger,
+func NewCache(backend Backend, namespace string, logger *logrus.Logger) *Cache {
+	if namespace == "" {
+		namespace = DefaultCacheNamespace
+	}
+	c := &Cache{
+		backend:   backend,
+		namespace: namespace,
+		logger:    logger,
+		families:  newFamilyCounters(),
+		started:   time.Now(),
 	}
+	if notifier, ok := backend.(evictionNotifier); ok {
+		notifier.OnEvict(c.recordEviction)
+	}
+	return c
 }
 
 // Cache key constants
@@ -179,53 +199,102 @@ const (
 	SystemHealthKey     = "system:health"
 )
 
-// CacheSearchResults caches search results for a query
-func (c *Cache) CacheSearchResults(ctx context.Context, query string, results interface{}, expiration time.Duration) error {
+// get reads a raw cache entry inside a span tagged with the key family
+func (c *Cache) get(ctx context.Context, family, key string) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "cache.get",
+		attribute.String("cache.family", family),
+		attribute.String("db.system", c.backend.Name()),
+	)
+	data, err := c.backend.Get(ctx, c.key(ctx, key))
+	span.SetAttributes(attribute.Bool("cache.hit", err == nil))
+
+	counters := c.counters(family)
+	switch {
+	case err == nil:
+		counters.hits.Add(1)
+	case err == ErrCacheMiss:
+		// A miss is an expected outcome, not a span error
+		counters.misses.Add(1)
+		span.End()
+		return nil, err
+	default:
+		// An unreachable backend behaves like a miss for the caller
+		counters.misses.Add(1)
+		counters.errors.Add(1)
+	}
+	tracing.End(span, err)
+	return data, err
+}
+
+// set writes a raw cache entry inside a span tagged with the key family
+func (c *Cache) set(ctx context.Context, family, key string, data []byte, expiration time.Duration) error {
+	ctx, span := tracing.Start(ctx, "cache.set",
+		attribute.String("cache.family", family),
+		attribute.String("db.system", c.backend.Name()),
+		attribute.Int("cache.size", len(data)),
+	)
+	err := c.backend.Set(ctx, c.key(ctx, key), data, expiration)
+	if err != nil {
+		c.counters(family).errors.Add(1)
+	} else {
+		c.counters(family).sets.Add(1)
+	}
+	tracing.End(span, err)
+	return err
+}
+
+// CacheSearchResults caches search results for a query. Tags name the wiki
+// pages the results came from so the entry can be dropped when one of those
+// pages changes.
+func (c *Cache) CacheSearchResults(ctx context.Context, query string, results interface{}, expiration time.Duration, pages ...string) error {
 	key := fmt.Sprintf(SearchResultsKey, query)
-	
+
 	data, err := json.Marshal(results)
 	if err != nil {
 		return fmt.Errorf("failed to marshal search results: %w", err)
 	}
 
-	return c.client.Set(ctx, key, data, expiration).Err()
+	if err := c.set(ctx, FamilySearchResults, key, data, expiration); err != nil {
+		return err
+	}
+	return c.tag(ctx, c.key(ctx, key), expiration, pages...)
 }
 
 // GetCachedSearchResults retrieves cached search results
 func (c *Cache) GetCachedSearchResults(ctx context.Context, query string, result interface{}) error {
 	key := fmt.Sprintf(SearchResultsKey, query)
-	
-	data, err := c.client.Get(ctx, key).Result()
+
+	data, err := c.get(ctx, FamilySearchResults, key)
 	if err != nil {
 		return err
 	}
 
-	return json.Unmarshal([]byte(data), result)
+	return json.Unmarshal(data, result)
 }
 
 // CacheContentMetadata caches content metadata
 func (c *Cache) CacheContentMetadata(ctx context.Context, title string, metadata *models.ContentMetadata, expiration time.Duration) error {
 	key := fmt.Sprintf(ContentMetadataKey, title)
-	
+
 	data, err := json.Marshal(metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal content metadata: %w", err)
 	}
 
-	return c.client.Set(ctx, key, data, expiration).Err()
+	return c.set(ctx, FamilyContentMetadata, key, data, expiration)
 }
 
 // GetCachedContentMetadata retrieves cached content metadata
 func (c *Cache) GetCachedContentMetadata(ctx context.Context, title string) (*models.ContentMetadata, error) {
 	key := fmt.Sprintf(ContentMetadataKey, title)
-	
-	data, err := c.client.Get(ctx, key).Result()
+
+	data, err := c.get(ctx, FamilyContentMetadata, key)
 	if err != nil {
 		return nil, err
 	}
 
 	var metadata models.ContentMetadata
-	err = json.Unmarshal([]byte(data), &metadata)
+	err = json.Unmarshal(data, &metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -240,18 +309,18 @@ func (c *Cache) CachePopularQueries(ctx context.Context, queries []models.Popula
 		return fmt.Errorf("failed to marshal popular queries: %w", err)
 	}
 
-	return c.client.Set(ctx, PopularQueriesKey, data, expiration).Err()
+	return c.set(ctx, FamilyPopularQueries, PopularQueriesKey, data, expiration)
 }
 
 // GetCachedPopularQueries retrieves cached popular queries
 func (c *Cache) GetCachedPopularQueries(ctx context.Context) ([]models.PopularQuery, error) {
-	data, err := c.client.Get(ctx, PopularQueriesKey).Result()
+	data, err := c.get(ctx, FamilyPopularQueries, PopularQueriesKey)
 	if err != nil {
 		return nil, err
 	}
 
 	var queries []models.PopularQuery
-	err = json.Unmarshal([]byte(data), &queries)
+	err = json.Unmarshal(data, &queries)
 	return queries, err
 }
 
@@ -262,59 +331,47 @@ func (c *Cache) CacheSystemHealth(ctx context.Context, health []models.SystemHea
 		return fmt.Errorf("failed to marshal system health: %w", err)
 	}
 
-	return c.client.Set(ctx, SystemHealthKey, data, expiration).Err()
+	return c.set(ctx, FamilySystemHealth, SystemHealthKey, data, expiration)
 }
 
 // GetCachedSystemHealth retrieves cached system health
 func (c *Cache) GetCachedSystemHealth(ctx context.Context) ([]models.SystemHealth, error) {
-	data, err := c.client.Get(ctx, SystemHealthKey).Result()
+	data, err := c.get(ctx, FamilySystemHealth, SystemHealthKey)
 	if err != nil {
 		return nil, err
 	}
 
 	var health []models.SystemHealth
-	err = json.Unmarshal([]byte(data), &health)
+	err = json.Unmarshal(data, &health)
 	return health, err
 }
 
 // InvalidateSearchCache removes search result cache for a query
 func (c *Cache) InvalidateSearchCache(ctx context.Context, query string) error {
 	key := fmt.Sprintf(SearchResultsKey, query)
-	return c.client.Del(ctx, key).Err()
+	return c.backend.Delete(ctx, c.key(ctx, key))
 }
 
 // InvalidateContentCache removes content metadata cache
 func (c *Cache) InvalidateContentCache(ctx context.Context, title string) error {
 	key := fmt.Sprintf(ContentMetadataKey, title)
-	return c.client.Del(ctx, key).Err()
+	return c.backend.Delete(ctx, c.key(ctx, key))
 }
 
-// ClearAllCache clears all cache data
+// ClearAllCache invalidates every entry in the namespace by moving to a new
+// generation. Entries of older generations are never read again and expire
+// on their own TTLs.
 func (c *Cache) ClearAllCache(ctx context.Context) error {
-	return c.client.FlushDB(ctx).Err()
+	_, err := c.BumpGeneration(ctx)
+	return err
+}
+
+// Backend returns the storage behind the cache
+func (c *Cache) Backend() Backend {
+	return c.backend
 }
 
 // Cache statistics
 func (c *Cache) GetCacheStats(ctx context.Context) (map[string]interface{}, error) {
-	info := c.client.Info(ctx, "stats").Val()
-	
-	stats := map[string]interface{}{
-		"keyspace_hits":   c.extractStat(info, "keyspace_hits"),
-		"keyspace_misses": c.extractStat(info, "keyspace_misses"),
-		"used_memory":     c.extractStat(info, "used_memory"),
-		"connected_clients": c.extractStat(info, "connected_clients"),
-	}
-
-	return stats, nil
+	return c.backend.Stats(ctx)
 }
-
-func (c *Cache) extractStat(info, key string) string {
-	// Simple stat extraction - could be improved with regex
-	lines := strings.Split(info, "\r\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, key+":") {
-			return strings.TrimPrefix(line, key+":")
-		}
-	}
-	return "0"
-}
\ No newline at end of file
@@ -0,0 +1,264 @@
+// backend/internal/database/search_cache.go
+package database
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+// Freshness of a search result as reported to clients
+const (
+	FreshnessLive  = "live"  // computed for this request, or joined an identical in-flight search
+	FreshnessFresh = "fresh" // served from cache within the soft TTL
+	FreshnessStale = "stale" // served from cache past the soft TTL while a refresh runs
+)
+
+// searchCacheMetrics counts lookups by outcome (fresh, stale, live, failure)
+// and is published at /api/v1/admin/debug/vars
+var searchCacheMetrics = expvar.NewMap("search_cache")
+
+// SearchCacheKey hashes the normalized query together with every search
+// parameter, so requests that differ only in case or spacing share an entry
+// and requests with different parameters never do
+func SearchCacheKey(query string, limit int) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	return utils.MD5Hash(fmt.Sprintf("%s\x00limit=%d", normalized, limit))
+}
+
+// ErrUpstreamFailure is returned while a recent upstream failure for the same
+// key is negatively cached
+var ErrUpstreamFailure = errors.New("search recently failed upstream")
+
+// SearchCacheConfig controls how long search results are served
+type SearchCacheConfig struct {
+	SoftTTL        time.Duration // results younger than this are served as fresh
+	HardTTL        time.Duration // results are kept, and served stale, up to this age
+	NegativeTTL    time.Duration // upstream failures are remembered this long
+	RefreshTimeout time.Duration // bound on a single upstream search
+}
+
+// SearchFunc computes search results on a cache miss
+type SearchFunc func(ctx context.Context) ([]models.SearchResult, error)
+
+// SearchLookup is the outcome of a cached search
+type SearchLookup struct {
+	Results    []models.SearchResult
+	ComputedAt time.Time
+	Freshness  string
+}
+
+// cachedSearch is the entry stored under a search key. Failed searches are
+// stored with Error set and no results.
+type cachedSearch struct {
+	Results    []models.SearchResult `json:"results"`
+	ComputedAt time.Time             `json:"computed_at"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// SearchCache sits in front of the upstream search. Identical in-flight
+// searches share one upstream call, entries past the soft TTL are served
+// while a single background refresh runs, and failures are cached briefly so
+// an outage does not turn into a retry storm.
+type SearchCache struct {
+	cache  *Cache
+	group  singleflight.Group
+	logger *logrus.Logger
+
+	mu  sync.RWMutex
+	cfg SearchCacheConfig
+}
+
+func NewSearchCache(cache *Cache, cfg SearchCacheConfig, logger *logrus.Logger) *SearchCache {
+	return &SearchCache{
+		cache:  cache,
+		cfg:    cfg.withDefaults(),
+		logger: logger,
+	}
+}
+
+func (cfg SearchCacheConfig) withDefaults() SearchCacheConfig {
+	if cfg.SoftTTL <= 0 {
+		cfg.SoftTTL = 5 * time.Minute
+	}
+	if cfg.HardTTL < cfg.SoftTTL {
+		cfg.HardTTL = cfg.SoftTTL
+	}
+	if cfg.NegativeTTL <= 0 {
+		cfg.NegativeTTL = 30 * time.Second
+	}
+	if cfg.RefreshTimeout <= 0 {
+		cfg.RefreshTimeout = 10 * time.Second
+	}
+	return cfg
+}
+
+// SetConfig changes the TTLs of later lookups and writes. Entries already
+// stored keep the expiry they were written with.
+func (sc *SearchCache) SetConfig(cfg SearchCacheConfig) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.cfg = cfg.withDefaults()
+}
+
+func (sc *SearchCache) config() SearchCacheConfig {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.cfg
+}
+
+// Get returns cached results for key or computes them with search
+func (sc *SearchCache) Get(ctx context.Context, key string, search SearchFunc) (*SearchLookup, error) {
+	var entry cachedSearch
+	if err := sc.cache.GetCachedSearchResults(ctx, key, &entry); err == nil {
+		if entry.Error != "" {
+			searchCacheMetrics.Add("failure", 1)
+			return nil, ErrUpstreamFailure
+		}
+
+		lookup := &SearchLookup{
+			Results:    entry.Results,
+			ComputedAt: entry.ComputedAt,
+			Freshness:  FreshnessFresh,
+		}
+		if time.Since(entry.ComputedAt) >= sc.config().SoftTTL {
+			lookup.Freshness = FreshnessStale
+			sc.refreshInBackground(ctx, key, search)
+		}
+		searchCacheMetrics.Add(lookup.Freshness, 1)
+		return lookup, nil
+	}
+
+	ch := sc.group.DoChan(key, func() (interface{}, error) {
+		return sc.compute(context.WithoutCancel(ctx), key, search, true)
+	})
+
+	return waitLive(ctx, ch)
+}
+
+// waitLive waits for a search started or joined through the singleflight
+// group. The caller may give up early; the search itself carries on.
+func waitLive(ctx context.Context, ch <-chan singleflight.Result) (*SearchLookup, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			searchCacheMetrics.Add("failure", 1)
+			return nil, res.Err
+		}
+		searchCacheMetrics.Add(FreshnessLive, 1)
+		entry := res.Val.(*cachedSearch)
+		return &SearchLookup{
+			Results:    entry.Results,
+			ComputedAt: entry.ComputedAt,
+			Freshness:  FreshnessLive,
+		}, nil
+	}
+}
+
+// Peek returns the cached entry for key without searching or refreshing.
+// Negatively cached failures are reported as absent.
+func (sc *SearchCache) Peek(ctx context.Context, key string) (*SearchLookup, bool) {
+	var entry cachedSearch
+	if err := sc.cache.GetCachedSearchResults(ctx, key, &entry); err != nil || entry.Error != "" {
+		return nil, false
+	}
+
+	lookup := &SearchLookup{
+		Results:    entry.Results,
+		ComputedAt: entry.ComputedAt,
+		Freshness:  FreshnessFresh,
+	}
+	if time.Since(entry.ComputedAt) >= sc.config().SoftTTL {
+		lookup.Freshness = FreshnessStale
+	}
+	return lookup, true
+}
+
+// Refresh recomputes key and waits for the result, joining a refresh or
+// search for the same key that is already running. It is for callers that
+// have shown a stale entry and want the up-to-date one; a failed refresh
+// keeps the stale entry rather than replacing it with a negative one.
+func (sc *SearchCache) Refresh(ctx context.Context, key string, search SearchFunc) (*SearchLookup, error) {
+	ch := sc.group.DoChan(key, func() (interface{}, error) {
+		return sc.compute(context.WithoutCancel(ctx), key, search, false)
+	})
+
+	return waitLive(ctx, ch)
+}
+
+// Warm makes sure key holds a fresh entry, computing it only when it is
+// missing or past the soft TTL. It reports whether a search was run.
+// Failures are not negatively cached, since no user is waiting on them.
+func (sc *SearchCache) Warm(ctx context.Context, key string, search SearchFunc) (bool, error) {
+	var entry cachedSearch
+	if err := sc.cache.GetCachedSearchResults(ctx, key, &entry); err == nil &&
+		entry.Error == "" && time.Since(entry.ComputedAt) < sc.config().SoftTTL {
+		return false, nil
+	}
+
+	_, err, _ := sc.group.Do(key, func() (interface{}, error) {
+		return sc.compute(ctx, key, search, false)
+	})
+	return err == nil, err
+}
+
+// refreshInBackground recomputes a stale entry unless a refresh or search for
+// the same key is already running
+func (sc *SearchCache) refreshInBackground(ctx context.Context, key string, search SearchFunc) {
+	refreshCtx := context.WithoutCancel(ctx)
+	go func() {
+		_, err, _ := sc.group.Do(key, func() (interface{}, error) {
+			// A failed refresh keeps the stale entry rather than replacing it
+			// with a negative one
+			return sc.compute(refreshCtx, key, search, false)
+		})
+		if err != nil {
+			sc.logger.WithError(err).Warn("Background search refresh failed")
+		}
+	}()
+}
+
+// compute runs the upstream search and stores the outcome. It runs detached
+// from any single caller so that one client disconnecting does not fail the
+// others waiting on the same key.
+func (sc *SearchCache) compute(ctx context.Context, key string, search SearchFunc, cacheFailure bool) (*cachedSearch, error) {
+	// The timeout bounds only the search, so a timed-out search can still be
+	// recorded as a failure
+	cfg := sc.config()
+	searchCtx, cancel := context.WithTimeout(ctx, cfg.RefreshTimeout)
+	defer cancel()
+
+	results, err := search(searchCtx)
+	if err != nil {
+		if !cacheFailure {
+			return nil, err
+		}
+		failed := &cachedSearch{ComputedAt: time.Now(), Error: err.Error()}
+		if cerr := sc.cache.CacheSearchResults(ctx, key, failed, cfg.NegativeTTL); cerr != nil {
+			sc.logger.WithError(cerr).Warn("Failed to cache search failure")
+		}
+		return nil, err
+	}
+
+	pages := make([]string, 0, len(results))
+	for _, result := range results {
+		pages = append(pages, result.Page)
+	}
+
+	entry := &cachedSearch{Results: results, ComputedAt: time.Now()}
+	if err := sc.cache.CacheSearchResults(ctx, key, entry, cfg.HardTTL, pages...); err != nil {
+		sc.logger.WithError(err).Warn("Failed to cache search results")
+	}
+	return entry, nil
+}
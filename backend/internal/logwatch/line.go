@@ -0,0 +1,71 @@
+// backend/internal/logwatch/line.go
+package logwatch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Lines copied from journalctl, dmesg or syslog start with noise that does
+// not help a search
+var (
+	syslogPrefix  = regexp.MustCompile(`^[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2} \S+ `)
+	isoPrefix     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}\S* \S+ `)
+	kernelTime    = regexp.MustCompile(`^\[\s*\d+\.\d+\]\s*`)
+	processPID    = regexp.MustCompile(`\[\d+\]:`)
+	whitespaceRun = regexp.MustCompile(`\s+`)
+)
+
+// Variable parts of a message that should not split one problem into
+// several signatures
+var (
+	uuidPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hexPattern    = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{12,}\b`)
+	numberPattern = regexp.MustCompile(`\d+`)
+)
+
+// errorPattern matches lines worth looking up when the log carries no
+// priority of its own
+var errorPattern = regexp.MustCompile(`(?i)\b(error|errors|failed|failure|fatal|panic|segfault|critical|emerg|cannot|unable to|timed out|denied|corrupt(ed)?)\b`)
+
+// CleanLine strips timestamps, hosts and PIDs from a log line and collapses
+// whitespace
+func CleanLine(line string) string {
+	line = strings.TrimSpace(line)
+	line = syslogPrefix.ReplaceAllString(line, "")
+	line = isoPrefix.ReplaceAllString(line, "")
+	line = kernelTime.ReplaceAllString(line, "")
+	line = processPID.ReplaceAllString(line, ":")
+	return strings.TrimSpace(whitespaceRun.ReplaceAllString(line, " "))
+}
+
+// IsMarker reports whether a line is a journalctl separator such as
+// "-- Boot ... --" rather than a message
+func IsMarker(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "-- ")
+}
+
+// LooksLikeError reports whether a message without a priority reads like an
+// error
+func LooksLikeError(message string) bool {
+	return errorPattern.MatchString(message)
+}
+
+// Normalize replaces the variable parts of a cleaned message, such as
+// numbers, addresses and UUIDs, so repeats of one problem compare equal
+func Normalize(message string) string {
+	message = strings.ToLower(message)
+	message = uuidPattern.ReplaceAllString(message, "<uuid>")
+	message = hexPattern.ReplaceAllString(message, "<hex>")
+	message = numberPattern.ReplaceAllString(message, "#")
+	return message
+}
+
+// Signature identifies a problem by where it was logged and its normalized
+// message
+func Signature(identifier, message string) string {
+	sum := sha256.Sum256([]byte(identifier + "\x00" + Normalize(message)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,92 @@
+// backend/internal/logwatch/report.go
+package logwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportEntry is one signature as written to a JSON report
+type reportEntry struct {
+	Signature string `json:"signature"`
+	*SignatureState
+}
+
+// writeReport lists every signature, most frequent first, with the fixes
+// found for it. Paths ending in .json get JSON, anything else Markdown.
+func writeReport(path string, state *State, now time.Time) error {
+	entries := make([]reportEntry, 0, len(state.Signatures))
+	for key, sig := range state.Signatures {
+		entries = append(entries, reportEntry{Signature: key, SignatureState: sig})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].LastSeen.After(entries[j].LastSeen)
+	})
+
+	if strings.HasSuffix(path, ".json") {
+		data, err := json.MarshalIndent(struct {
+			GeneratedAt time.Time     `json:"generated_at"`
+			Signatures  []reportEntry `json:"signatures"`
+		}{now, entries}, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeAtomic(path, data)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Ophelia log report\n\n")
+	fmt.Fprintf(&b, "Generated %s from %d files; %d error signatures.\n", now.Format(time.RFC3339), len(state.Files), len(entries))
+
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownEscape(entry.Sample))
+		if entry.Identifier != "" {
+			fmt.Fprintf(&b, "- Program: %s\n", entry.Identifier)
+		}
+		fmt.Fprintf(&b, "- Seen %d times, first %s, last %s\n", entry.Count,
+			entry.FirstSeen.Format(time.RFC3339), entry.LastSeen.Format(time.RFC3339))
+		fmt.Fprintf(&b, "- Source: %s\n", entry.Source)
+		fmt.Fprintf(&b, "- Signature: `%s`\n\n", entry.Signature)
+
+		switch {
+		case entry.LookedUpAt == nil && entry.Error != "":
+			fmt.Fprintf(&b, "Lookup failed: %s\n", entry.Error)
+		case entry.LookedUpAt == nil:
+			fmt.Fprintf(&b, "Lookup pending.\n")
+		case len(entry.Suggestions) == 0:
+			fmt.Fprintf(&b, "No wiki matches.\n")
+		default:
+			fmt.Fprintf(&b, "Suggested fixes:\n\n")
+			for i, s := range entry.Suggestions {
+				fmt.Fprintf(&b, "%d. [%s](%s) (%s, %.0f%%)\n", i+1, markdownEscape(s.Title), s.URL, s.Relevance, s.Score*100)
+				for _, command := range s.Commands {
+					fmt.Fprintf(&b, "   - `%s`\n", command)
+				}
+				for _, warning := range s.Warnings {
+					line := fmt.Sprintf("   - **%s:** `%s`", strings.ReplaceAll(warning.Risk, "_", " "), warning.Command)
+					if len(warning.Reasons) > 0 {
+						line += " (" + strings.Join(warning.Reasons, ", ") + ")"
+					}
+					if warning.WikiWarning != "" {
+						line += ". Wiki warning: " + markdownEscape(warning.WikiWarning)
+					}
+					b.WriteString(line + "\n")
+				}
+			}
+		}
+	}
+
+	return writeAtomic(path, []byte(b.String()))
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "<", "&lt;")
+
+func markdownEscape(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,244 @@
+// backend/internal/logwatch/source.go
+package logwatch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is the layout of a watched file
+type Format string
+
+const (
+	FormatAuto   Format = "auto"   // decided from the start of the file
+	FormatPlain  Format = "plain"  // one message per line, e.g. syslog or journalctl output
+	FormatExport Format = "export" // journalctl -o export
+)
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case FormatAuto, FormatPlain, FormatExport:
+		return Format(name), nil
+	default:
+		return "", fmt.Errorf("unknown log format %q: want auto, plain or export", name)
+	}
+}
+
+// maxRead bounds how much new data one poll reads from a file
+const maxRead = 4 << 20
+
+// headSize is how much of the start of a file is fingerprinted to notice
+// it being replaced by rotation
+const headSize = 256
+
+var exportField = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*=`)
+
+// Entry is one message read from a log
+type Entry struct {
+	Source     string
+	Time       time.Time
+	Identifier string // program that logged it, when the log says
+	Message    string
+	Priority   int // syslog priority, -1 when the log carries none
+}
+
+// IsError reports whether an entry is error level or worse. Entries
+// without a priority are judged by their wording.
+func (e Entry) IsError() bool {
+	if e.Priority >= 0 {
+		return e.Priority <= 3
+	}
+	return LooksLikeError(e.Message)
+}
+
+// FileState is where reading stopped in one file, persisted across restarts
+type FileState struct {
+	Offset  int64  `json:"offset"`
+	Format  Format `json:"format"`
+	Head    string `json:"head"`     // hash of the first HeadLen bytes
+	HeadLen int    `json:"head_len"` // bytes covered by Head
+}
+
+// poll reads entries appended to path since the last call. A file that
+// shrank or whose first bytes changed has been rotated or replaced and is
+// read again from the start.
+func poll(path string, state *FileState, format Format) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, headSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	if info.Size() < state.Offset || (state.HeadLen > 0 && (len(head) < state.HeadLen || hashHead(head[:state.HeadLen]) != state.Head)) {
+		state.Offset = 0
+		state.Format = ""
+		state.Head, state.HeadLen = "", 0
+	}
+	if state.HeadLen < len(head) || hashHead(head[:state.HeadLen]) != state.Head {
+		state.Head, state.HeadLen = hashHead(head), len(head)
+	}
+
+	if state.Format == "" || state.Format == FormatAuto {
+		state.Format = format
+		if format == FormatAuto || format == "" {
+			state.Format = detectFormat(head)
+		}
+	}
+
+	if info.Size() == state.Offset {
+		return nil, nil
+	}
+
+	if _, err := file.Seek(state.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, maxRead)
+	n, err = io.ReadFull(file, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	var entries []Entry
+	var consumed int
+	if state.Format == FormatExport {
+		entries, consumed = parseExport(buf)
+	} else {
+		entries, consumed = parsePlain(buf)
+	}
+	if consumed == 0 && n == maxRead {
+		// A record larger than one read can never complete; skip it
+		consumed = n
+	}
+	state.Offset += int64(consumed)
+
+	for i := range entries {
+		entries[i].Source = path
+	}
+	return entries, nil
+}
+
+func hashHead(head []byte) string {
+	sum := sha256.Sum256(head)
+	return hex.EncodeToString(sum[:8])
+}
+
+// detectFormat recognizes journal export files by their KEY=value first line
+func detectFormat(head []byte) Format {
+	firstLine, _, _ := bytes.Cut(head, []byte("\n"))
+	if exportField.Match(firstLine) {
+		return FormatExport
+	}
+	return FormatPlain
+}
+
+// parsePlain returns an entry per complete line and the bytes consumed
+func parsePlain(buf []byte) ([]Entry, int) {
+	end := bytes.LastIndexByte(buf, '\n')
+	if end < 0 {
+		return nil, 0
+	}
+
+	now := time.Now()
+	var entries []Entry
+	for _, line := range strings.Split(string(buf[:end]), "\n") {
+		if IsMarker(line) {
+			continue
+		}
+		message := CleanLine(line)
+		if message == "" {
+			continue
+		}
+		entries = append(entries, Entry{Time: now, Message: message, Priority: -1})
+	}
+	return entries, end + 1
+}
+
+// parseExport decodes complete journal export entries: KEY=value lines, or
+// a KEY line followed by a little-endian length and binary data, with a
+// blank line after each entry. It returns the bytes consumed.
+func parseExport(buf []byte) ([]Entry, int) {
+	var entries []Entry
+	consumed := 0
+	pos := 0
+	fields := make(map[string]string)
+
+	for pos < len(buf) {
+		if buf[pos] == '\n' {
+			if len(fields) > 0 {
+				entries = append(entries, exportEntry(fields))
+				fields = make(map[string]string)
+			}
+			pos++
+			consumed = pos
+			continue
+		}
+
+		nl := bytes.IndexByte(buf[pos:], '\n')
+		if nl < 0 {
+			break
+		}
+		line := buf[pos : pos+nl]
+
+		if key, value, ok := bytes.Cut(line, []byte("=")); ok {
+			fields[string(key)] = string(value)
+			pos += nl + 1
+			continue
+		}
+
+		// Binary field: KEY\n<uint64 size><data>\n
+		start := pos + nl + 1
+		if start+8 > len(buf) {
+			break
+		}
+		size := binary.LittleEndian.Uint64(buf[start : start+8])
+		if size > maxRead || start+8+int(size)+1 > len(buf) {
+			break
+		}
+		fields[string(line)] = string(buf[start+8 : start+8+int(size)])
+		pos = start + 8 + int(size) + 1
+	}
+
+	return entries, consumed
+}
+
+func exportEntry(fields map[string]string) Entry {
+	entry := Entry{
+		Message:  CleanLine(fields["MESSAGE"]),
+		Priority: -1,
+		Time:     time.Now(),
+	}
+	if priority, err := strconv.Atoi(fields["PRIORITY"]); err == nil {
+		entry.Priority = priority
+	}
+	entry.Identifier = fields["SYSLOG_IDENTIFIER"]
+	if entry.Identifier == "" {
+		entry.Identifier = fields["_COMM"]
+	}
+	if usec, err := strconv.ParseInt(fields["__REALTIME_TIMESTAMP"], 10, 64); err == nil {
+		entry.Time = time.UnixMicro(usec)
+	}
+	return entry
+}
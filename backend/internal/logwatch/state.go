@@ -0,0 +1,123 @@
+// backend/internal/logwatch/state.go
+package logwatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+)
+
+// State is what the watcher remembers between runs: how far each file was
+// read and every error signature seen, with the result of looking it up
+type State struct {
+	Files      map[string]*FileState      `json:"files"`
+	Signatures map[string]*SignatureState `json:"signatures"`
+}
+
+// SignatureState groups the occurrences of one error
+type SignatureState struct {
+	Identifier  string       `json:"identifier,omitempty"`
+	Sample      string       `json:"sample"` // latest message with this signature
+	Source      string       `json:"source"`
+	Count       int          `json:"count"`
+	FirstSeen   time.Time    `json:"first_seen"`
+	LastSeen    time.Time    `json:"last_seen"`
+	LookedUpAt  *time.Time   `json:"looked_up_at,omitempty"`
+	LastAttempt *time.Time   `json:"last_attempt,omitempty"`
+	Error       string       `json:"error,omitempty"` // why the last lookup failed
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+}
+
+// Suggestion is a wiki result found for a signature
+type Suggestion struct {
+	Title     string                  `json:"title"`
+	URL       string                  `json:"url"`
+	Score     float64                 `json:"score"`
+	Relevance string                  `json:"relevance"`
+	Commands  []string                `json:"commands,omitempty"`
+	Warnings  []models.CommandWarning `json:"warnings,omitempty"`
+}
+
+// LoadState reads the state file, returning empty state when it does not
+// exist yet
+func LoadState(path string) (*State, error) {
+	state := &State{
+		Files:      make(map[string]*FileState),
+		Signatures: make(map[string]*SignatureState),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+	if state.Files == nil {
+		state.Files = make(map[string]*FileState)
+	}
+	if state.Signatures == nil {
+		state.Signatures = make(map[string]*SignatureState)
+	}
+	return state, nil
+}
+
+// Save writes the state atomically so a crash never leaves it half written
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, data)
+}
+
+// prune drops the signatures seen least recently beyond max
+func (s *State) prune(max int) {
+	if max <= 0 || len(s.Signatures) <= max {
+		return
+	}
+
+	keys := make([]string, 0, len(s.Signatures))
+	for key := range s.Signatures {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return s.Signatures[keys[i]].LastSeen.After(s.Signatures[keys[j]].LastSeen)
+	})
+	for _, key := range keys[max:] {
+		delete(s.Signatures, key)
+	}
+}
+
+// writeAtomic replaces path with data through a temporary file in the same
+// directory
+func writeAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,324 @@
+// backend/internal/logwatch/watcher.go
+package logwatch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/client"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/seeder"
+	"github.com/sirupsen/logrus"
+)
+
+// Config controls what the watcher reads and how hard it leans on the server
+type Config struct {
+	Paths          []string
+	Format         Format
+	FromStart      bool          // read files already present from the beginning instead of the end
+	StatePath      string        // where offsets and signatures are remembered
+	ReportPath     string        // Markdown report, or JSON when it ends in .json
+	PollInterval   time.Duration // how often files are checked for new lines
+	LookupInterval time.Duration // minimum time between searches
+	Relookup       time.Duration // age after which a signature is searched again
+	RetryAfter     time.Duration // wait after a failed search before trying that signature again
+	MaxSignatures  int           // signatures remembered, least recently seen dropped first
+	Limit          int           // results kept per signature
+}
+
+// Searcher looks up a query; *client.Client satisfies it
+type Searcher interface {
+	Search(ctx context.Context, request models.SearchRequest) (*models.SearchResponse, error)
+}
+
+// Watcher tails logs, groups new error lines by signature and looks each
+// signature up once, remembering what it did across restarts
+type Watcher struct {
+	cfg         Config
+	searcher    Searcher
+	state       *State
+	processor   *seeder.ContentProcessor
+	logger      *logrus.Logger
+	pausedUntil time.Time // set when the server asks us to back off
+	changed     bool      // report is out of date
+}
+
+func New(cfg Config, searcher Searcher, logger *logrus.Logger) (*Watcher, error) {
+	if cfg.Format == "" {
+		cfg.Format = FormatAuto
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.LookupInterval <= 0 {
+		cfg.LookupInterval = 10 * time.Second
+	}
+	if cfg.Relookup <= 0 {
+		cfg.Relookup = 7 * 24 * time.Hour
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = 10 * time.Minute
+	}
+	if cfg.MaxSignatures <= 0 {
+		cfg.MaxSignatures = 1000
+	}
+	if cfg.Limit <= 0 {
+		cfg.Limit = 3
+	}
+
+	state, err := LoadState(cfg.StatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		cfg:       cfg,
+		searcher:  searcher,
+		state:     state,
+		processor: seeder.NewContentProcessor(),
+		logger:    logger,
+		changed:   true,
+	}, nil
+}
+
+// Run polls files and looks up signatures until ctx is done, saving state
+// and the report as it goes and once more on the way out
+func (w *Watcher) Run(ctx context.Context) error {
+	w.logger.WithFields(logrus.Fields{
+		"files":      len(w.cfg.Paths),
+		"signatures": len(w.state.Signatures),
+	}).Info("Log watcher started")
+
+	pollTicker := time.NewTicker(w.cfg.PollInterval)
+	defer pollTicker.Stop()
+	lookupTicker := time.NewTicker(w.cfg.LookupInterval)
+	defer lookupTicker.Stop()
+
+	w.poll()
+	w.lookupNext(ctx)
+	w.flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.flush()
+			w.logger.Info("Log watcher stopped")
+			return nil
+		case <-pollTicker.C:
+			w.poll()
+			w.flush()
+		case <-lookupTicker.C:
+			w.lookupNext(ctx)
+			w.flush()
+		}
+	}
+}
+
+// Once reads what the files hold now, looks up every due signature at the
+// configured rate, then writes state and report. When the server asks it to
+// back off it waits out the pause rather than stopping early.
+func (w *Watcher) Once(ctx context.Context) error {
+	w.poll()
+	for first := true; ; first = false {
+		var wait time.Duration
+		if !first {
+			wait = w.cfg.LookupInterval
+		}
+		if paused := time.Until(w.pausedUntil); paused > wait {
+			wait = paused
+		}
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return w.flush()
+			case <-time.After(wait):
+			}
+		}
+		if !w.lookupNext(ctx) {
+			break
+		}
+	}
+	return w.flush()
+}
+
+// poll reads new entries from every file and records the errors among them
+func (w *Watcher) poll() {
+	for _, path := range w.cfg.Paths {
+		fileState, known := w.state.Files[path]
+		if !known {
+			fileState = &FileState{}
+			w.state.Files[path] = fileState
+			if !w.cfg.FromStart {
+				// Only lines written from now on are new
+				if info, err := os.Stat(path); err == nil {
+					fileState.Offset = info.Size()
+				}
+			}
+			w.changed = true
+		}
+
+		before := *fileState
+		entries, err := poll(path, fileState, w.cfg.Format)
+		if err != nil {
+			w.logger.WithError(err).WithField("file", path).Debug("Failed to read log file")
+			continue
+		}
+		if *fileState != before {
+			w.changed = true
+		}
+
+		for _, entry := range entries {
+			if entry.IsError() {
+				w.record(entry)
+			}
+		}
+	}
+	w.state.prune(w.cfg.MaxSignatures)
+}
+
+// record counts one error entry under its signature
+func (w *Watcher) record(entry Entry) {
+	signature := Signature(entry.Identifier, entry.Message)
+	sig, ok := w.state.Signatures[signature]
+	if !ok {
+		sig = &SignatureState{
+			Identifier: entry.Identifier,
+			FirstSeen:  entry.Time,
+		}
+		w.state.Signatures[signature] = sig
+		w.logger.WithFields(logrus.Fields{
+			"signature": signature,
+			"source":    entry.Source,
+		}).Info("New error signature")
+	}
+
+	sig.Sample = entry.Message
+	sig.Source = entry.Source
+	sig.Count++
+	if entry.Time.After(sig.LastSeen) {
+		sig.LastSeen = entry.Time
+	}
+	w.changed = true
+}
+
+// lookupNext searches for the most frequent signature that is due and
+// reports whether there was one
+func (w *Watcher) lookupNext(ctx context.Context) bool {
+	now := time.Now()
+	if now.Before(w.pausedUntil) {
+		return false
+	}
+
+	var due []string
+	for key, sig := range w.state.Signatures {
+		if sig.LookedUpAt != nil && now.Sub(*sig.LookedUpAt) < w.cfg.Relookup {
+			continue
+		}
+		if sig.LastAttempt != nil && now.Sub(*sig.LastAttempt) < w.cfg.RetryAfter {
+			continue
+		}
+		due = append(due, key)
+	}
+	if len(due) == 0 {
+		return false
+	}
+	sort.Slice(due, func(i, j int) bool {
+		a, b := w.state.Signatures[due[i]], w.state.Signatures[due[j]]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return due[i] < due[j]
+	})
+
+	key := due[0]
+	sig := w.state.Signatures[key]
+	log := w.logger.WithField("signature", key)
+
+	sig.LastAttempt = &now
+	w.changed = true
+
+	response, err := w.searcher.Search(ctx, models.SearchRequest{Query: lookupQuery(sig), Limit: w.cfg.Limit})
+	if err != nil {
+		sig.Error = err.Error()
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter != "" {
+			if seconds, convErr := strconv.Atoi(apiErr.RetryAfter); convErr == nil {
+				w.pausedUntil = now.Add(time.Duration(seconds) * time.Second)
+				// Being throttled says nothing about the signature, so it
+				// is due again as soon as the pause ends
+				sig.LastAttempt = nil
+				log.WithField("retry_after", seconds).Warn("Server asked to back off")
+			}
+		}
+		log.WithError(err).Warn("Lookup failed")
+		return true
+	}
+
+	sig.Error = ""
+	sig.LookedUpAt = &now
+	sig.Suggestions = sig.Suggestions[:0]
+	for _, result := range response.Results {
+		commands := w.processor.ExtractCommandExamples(result.Content)
+		if len(commands) > 3 {
+			commands = commands[:3]
+		}
+		sig.Suggestions = append(sig.Suggestions, Suggestion{
+			Title:     result.Title,
+			URL:       result.URL,
+			Score:     result.Score,
+			Relevance: result.Relevance,
+			Commands:  commands,
+			Warnings:  result.Warnings,
+		})
+	}
+
+	log.WithField("results", len(sig.Suggestions)).Info("Looked up error signature")
+	return true
+}
+
+// maxQueryLength matches the server's limit on query length in bytes
+const maxQueryLength = 2000
+
+// lookupQuery is the search sent for a signature: its latest message,
+// prefixed by the program that logged it
+func lookupQuery(sig *SignatureState) string {
+	query := sig.Sample
+	if sig.Identifier != "" && !strings.HasPrefix(query, sig.Identifier) {
+		query = sig.Identifier + ": " + query
+	}
+	if len(query) > maxQueryLength {
+		// Cut on a rune boundary; a split rune would be sent as U+FFFD and
+		// push the query back over the limit
+		end := maxQueryLength
+		for end > 0 && !utf8.RuneStart(query[end]) {
+			end--
+		}
+		query = query[:end]
+	}
+	return query
+}
+
+// flush saves state and rewrites the report when anything changed
+func (w *Watcher) flush() error {
+	if !w.changed {
+		return nil
+	}
+	if err := w.state.Save(w.cfg.StatePath); err != nil {
+		w.logger.WithError(err).Error("Failed to save watcher state")
+		return err
+	}
+	if w.cfg.ReportPath != "" {
+		if err := writeReport(w.cfg.ReportPath, w.state, time.Now()); err != nil {
+			w.logger.WithError(err).Error("Failed to write report")
+			return err
+		}
+	}
+	w.changed = false
+	return nil
+}
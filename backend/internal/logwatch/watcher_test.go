@@ -0,0 +1,295 @@
+// backend/internal/logwatch/watcher_test.go
+package logwatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/client"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeSearcher answers every query with one result, or with the errors
+// queued in fail first
+type fakeSearcher struct {
+	mu      sync.Mutex
+	queries []string
+	fail    []error
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, request models.SearchRequest) (*models.SearchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, request.Query)
+	if len(f.fail) > 0 {
+		err := f.fail[0]
+		f.fail = f.fail[1:]
+		return nil, err
+	}
+	return &models.SearchResponse{Results: []models.SearchResult{{
+		Title:     "Pacman",
+		URL:       "https://wiki.archlinux.org/title/Pacman",
+		Score:     0.9,
+		Relevance: "high",
+	}}}, nil
+}
+
+func newTestWatcher(t *testing.T, dir string, searcher Searcher, fromStart bool, paths ...string) *Watcher {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	w, err := New(Config{
+		Paths:          paths,
+		FromStart:      fromStart,
+		StatePath:      filepath.Join(dir, "state.json"),
+		ReportPath:     filepath.Join(dir, "report.md"),
+		LookupInterval: time.Millisecond,
+	}, searcher, logger)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	return w
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// samples returns the sample message and count of every signature, sorted
+func samples(w *Watcher) []string {
+	var out []string
+	for _, sig := range w.state.Signatures {
+		out = append(out, fmt.Sprintf("%s x%d", sig.Sample, sig.Count))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestWatcherTailsFromEnd(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "pacman.log")
+	writeFile(t, log, "error: old failure before the watcher started\n")
+
+	w := newTestWatcher(t, dir, &fakeSearcher{}, false, log)
+	w.poll()
+	if len(w.state.Signatures) != 0 {
+		t.Fatalf("existing lines were read: %q", samples(w))
+	}
+
+	appendFile(t, log, "info: nothing to see\nerror: failed to commit transaction\npartial line without newline error")
+	w.poll()
+	want := []string{"error: failed to commit transaction x1"}
+	if got := samples(w); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("signatures %q, want %q", got, want)
+	}
+
+	// The partial line is read once it is complete
+	appendFile(t, log, "\n")
+	w.poll()
+	if len(w.state.Signatures) != 2 {
+		t.Errorf("got %d signatures after completing the line, want 2: %q", len(w.state.Signatures), samples(w))
+	}
+}
+
+func TestWatcherFromStart(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "pacman.log")
+	writeFile(t, log, "error: old failure\n")
+
+	w := newTestWatcher(t, dir, &fakeSearcher{}, true, log)
+	w.poll()
+	if len(w.state.Signatures) != 1 {
+		t.Errorf("got %d signatures, want the existing line read", len(w.state.Signatures))
+	}
+}
+
+func TestWatcherTruncationAndRotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace func(t *testing.T, path string)
+	}{
+		{
+			name: "truncated in place",
+			replace: func(t *testing.T, path string) {
+				writeFile(t, path, "error: after truncate\n")
+			},
+		},
+		{
+			name: "rotated to a larger file",
+			replace: func(t *testing.T, path string) {
+				if err := os.Rename(path, path+".1"); err != nil {
+					t.Fatal(err)
+				}
+				writeFile(t, path, "warning: a different first line that is longer than before\nerror: after rotate\n")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			log := filepath.Join(dir, "app.log")
+			writeFile(t, log, "info: service started and running normally for a while\nerror: before\n")
+
+			w := newTestWatcher(t, dir, &fakeSearcher{}, true, log)
+			w.poll()
+			tt.replace(t, log)
+			w.poll()
+
+			got := samples(w)
+			if len(got) != 2 || !strings.HasPrefix(got[0], "error: after") || got[1] != "error: before x1" {
+				t.Errorf("signatures %q, want the new file read from the start", got)
+			}
+		})
+	}
+}
+
+func TestWatcherGroupsBySignature(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "kern.log")
+	writeFile(t, log, "")
+
+	w := newTestWatcher(t, dir, &fakeSearcher{}, false, log)
+	w.poll()
+	appendFile(t, log, strings.Join([]string{
+		"Jan  2 03:04:05 arch kernel: usb 1-2: device descriptor read/64, error -71",
+		"Jan  2 03:04:09 arch kernel: usb 1-3: device descriptor read/64, error -110",
+		"Jan  2 03:05:00 arch systemd[1]: Failed to start Network Manager.",
+		"Jan  2 03:06:00 arch systemd[412]: Failed to start Network Manager.",
+		"[   12.345678] nouveau 0000:01:00.0: DRM: failed to create kernel channel, -22",
+		"Jan  2 03:07:00 arch kernel: usb 1-2: new high-speed USB device number 3",
+	}, "\n")+"\n")
+	w.poll()
+
+	want := []string{
+		"kernel: usb 1-3: device descriptor read/64, error -110 x2",
+		"nouveau 0000:01:00.0: DRM: failed to create kernel channel, -22 x1",
+		"systemd: Failed to start Network Manager. x2",
+	}
+	if got := samples(w); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("signatures\n got  %q\n want %q", got, want)
+	}
+}
+
+func TestWatcherStateSurvivesReload(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "pacman.log")
+	writeFile(t, log, "error: failed to commit transaction\n")
+
+	searcher := &fakeSearcher{}
+	w := newTestWatcher(t, dir, searcher, true, log)
+	if err := w.Once(context.Background()); err != nil {
+		t.Fatalf("Once returned error: %v", err)
+	}
+	if len(searcher.queries) != 1 {
+		t.Fatalf("got %d searches, want 1", len(searcher.queries))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.md")); err != nil {
+		t.Errorf("report not written: %v", err)
+	}
+
+	// A new watcher picks up where the first stopped: old lines are not
+	// read again and a looked up signature is not searched again
+	appendFile(t, log, "error: failed to commit transaction\n")
+	w = newTestWatcher(t, dir, searcher, true, log)
+	if err := w.Once(context.Background()); err != nil {
+		t.Fatalf("Once returned error: %v", err)
+	}
+	if len(searcher.queries) != 1 {
+		t.Errorf("got %d searches after reload, want 1", len(searcher.queries))
+	}
+	want := []string{"error: failed to commit transaction x2"}
+	if got := samples(w); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("signatures %q, want %q", got, want)
+	}
+	for _, sig := range w.state.Signatures {
+		if sig.LookedUpAt == nil || len(sig.Suggestions) != 1 {
+			t.Errorf("lookup result lost on reload: %+v", sig)
+		}
+	}
+}
+
+func TestWatcherOnceWaitsOutThrottling(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "pacman.log")
+	writeFile(t, log, "error: first problem\nerror: second problem\nerror: third problem\n")
+
+	searcher := &fakeSearcher{fail: []error{
+		&client.APIError{StatusCode: 429, Message: "Rate limit exceeded", RetryAfter: "1"},
+	}}
+	w := newTestWatcher(t, dir, searcher, true, log)
+
+	start := time.Now()
+	if err := w.Once(context.Background()); err != nil {
+		t.Fatalf("Once returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Once returned after %s, want it to wait out Retry-After", elapsed)
+	}
+
+	// The throttled signature is tried again after the pause, so every
+	// signature ends up looked up
+	if len(searcher.queries) != 4 {
+		t.Errorf("got %d searches, want 4: %q", len(searcher.queries), searcher.queries)
+	}
+	for key, sig := range w.state.Signatures {
+		if sig.LookedUpAt == nil {
+			t.Errorf("signature %s (%s) was not looked up: %s", key, sig.Sample, sig.Error)
+		}
+	}
+}
+
+func TestWatcherOnceStopsOnCancel(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "pacman.log")
+	writeFile(t, log, "error: first problem\nerror: second problem\n")
+
+	searcher := &fakeSearcher{fail: []error{
+		&client.APIError{StatusCode: 429, Message: "Rate limit exceeded", RetryAfter: "3600"},
+	}}
+	w := newTestWatcher(t, dir, searcher, true, log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := w.Once(ctx); err != nil {
+		t.Fatalf("Once returned error: %v", err)
+	}
+	if len(searcher.queries) != 1 {
+		t.Errorf("got %d searches while paused, want 1", len(searcher.queries))
+	}
+}
+
+func TestLookupQueryCutsOnRuneBoundary(t *testing.T) {
+	sig := &SignatureState{Sample: strings.Repeat("a", maxQueryLength-1) + "é and more"}
+	query := lookupQuery(sig)
+	if len(query) != maxQueryLength-1 {
+		t.Errorf("query is %d bytes, want %d", len(query), maxQueryLength-1)
+	}
+	if !strings.HasSuffix(query, "a") {
+		t.Errorf("query ends in %q, want the split rune dropped", query[len(query)-3:])
+	}
+}
@@ -0,0 +1,208 @@
+// backend/internal/middleware/apikey.go
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownAPIKey is returned by an APIKeyStore for keys it does not hold
+var ErrUnknownAPIKey = errors.New("unknown API key")
+
+// apiKeyContextKey is where APIKeyAuth stores the authenticated key
+const apiKeyContextKey = "api_key"
+
+// APIKey identifies a client authenticated by key
+type APIKey struct {
+	ID            uint
+	Name          string
+	Scopes        []string
+	RatePerMinute int // 0 means unlimited
+	DailyQuota    int // quota units per UTC day, 0 means unlimited
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyStore resolves the secret a client sent to its key
+type APIKeyStore interface {
+	Lookup(ctx context.Context, secret string) (*APIKey, error)
+}
+
+// DBAPIKeys is an APIKeyStore over the api_keys table. Keys are cached for
+// a short time, so a revoked key stops working within ttl.
+type DBAPIKeys struct {
+	repo  models.APIKeyRepository
+	ttl   time.Duration
+	mu    sync.Mutex
+	cache map[string]cachedAPIKey
+}
+
+type cachedAPIKey struct {
+	key     *APIKey
+	expires time.Time
+}
+
+func NewDBAPIKeys(repo models.APIKeyRepository, ttl time.Duration) *DBAPIKeys {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &DBAPIKeys{
+		repo:  repo,
+		ttl:   ttl,
+		cache: make(map[string]cachedAPIKey),
+	}
+}
+
+func (s *DBAPIKeys) Lookup(_ context.Context, secret string) (*APIKey, error) {
+	hash := utils.HashAPIKey(secret)
+	now := time.Now()
+
+	s.mu.Lock()
+	if cached, ok := s.cache[hash]; ok && now.Before(cached.expires) {
+		s.mu.Unlock()
+		return cached.key, nil
+	}
+	s.mu.Unlock()
+
+	record, err := s.repo.GetByHash(hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.RevokedAt != nil {
+		return nil, ErrUnknownAPIKey
+	}
+
+	key := &APIKey{
+		ID:            record.ID,
+		Name:          record.Name,
+		Scopes:        record.Scopes,
+		RatePerMinute: record.RatePerMinute,
+		DailyQuota:    record.DailyQuota,
+	}
+
+	s.mu.Lock()
+	// Drop expired entries so unused keys do not pile up
+	for h, cached := range s.cache {
+		if now.After(cached.expires) {
+			delete(s.cache, h)
+		}
+	}
+	s.cache[hash] = cachedAPIKey{key: key, expires: now.Add(s.ttl)}
+	s.mu.Unlock()
+
+	return key, nil
+}
+
+// APIKeyAuth authenticates the API key sent in X-API-Key or as a bearer
+// token and makes it available through APIKeyFromContext. Requests without
+// a key pass through anonymously; routes decide with ScopePolicy whether
+// that is enough. A key that is sent but not valid is always rejected.
+func APIKeyAuth(store APIKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader("X-API-Key")
+		if secret == "" {
+			secret = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if secret == "" {
+			c.Next()
+			return
+		}
+
+		key, err := store.Lookup(c.Request.Context(), secret)
+		if err != nil {
+			if errors.Is(err, ErrUnknownAPIKey) {
+				utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid API key", nil)
+			} else {
+				utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to verify API key", err)
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// ScopePolicy decides which scopes a route needs and which of them
+// anonymous clients get without a key
+type ScopePolicy struct {
+	anonymous map[string]bool
+}
+
+func NewScopePolicy(anonymousScopes []string) *ScopePolicy {
+	p := &ScopePolicy{anonymous: make(map[string]bool)}
+	for _, scope := range anonymousScopes {
+		p.anonymous[strings.TrimSpace(scope)] = true
+	}
+	return p
+}
+
+// Require admits requests whose key grants scope, and anonymous requests
+// when scope is open to anonymous clients
+func (p *ScopePolicy) Require(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := APIKeyFromContext(c)
+		switch {
+		case key == nil && p.anonymous[scope]:
+			c.Next()
+		case key == nil:
+			utils.ErrorResponse(c, http.StatusUnauthorized, "API key required", nil)
+			c.Abort()
+		case !key.HasScope(scope):
+			utils.ErrorResponse(c, http.StatusForbidden, fmt.Sprintf("API key lacks the %s scope", scope), nil)
+			c.Abort()
+		default:
+			c.Next()
+		}
+	}
+}
+
+// RequireKey admits only requests that carry a key granting scope, even
+// when anonymous clients hold that scope elsewhere
+func (p *ScopePolicy) RequireKey(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := APIKeyFromContext(c)
+		switch {
+		case key == nil:
+			utils.ErrorResponse(c, http.StatusUnauthorized, "API key required", nil)
+			c.Abort()
+		case !key.HasScope(scope):
+			utils.ErrorResponse(c, http.StatusForbidden, fmt.Sprintf("API key lacks the %s scope", scope), nil)
+			c.Abort()
+		default:
+			c.Next()
+		}
+	}
+}
+
+// APIKeyFromContext returns the key authenticated by APIKeyAuth, or nil
+func APIKeyFromContext(c *gin.Context) *APIKey {
+	if value, ok := c.Get(apiKeyContextKey); ok {
+		if key, ok := value.(*APIKey); ok {
+			return key
+		}
+	}
+	return nil
+}
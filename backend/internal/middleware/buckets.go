@@ -0,0 +1,308 @@
+// backend/internal/middleware/buckets.go
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// Bucket describes a token bucket: it holds up to Burst tokens and refills
+// at Rate tokens per second
+type Bucket struct {
+	Rate  float64
+	Burst int
+}
+
+// BucketResult is the state of a bucket after a Take
+type BucketResult struct {
+	Allowed    bool
+	Remaining  int           // whole tokens left
+	RetryAfter time.Duration // until the request would have been allowed; zero when allowed
+	ResetAfter time.Duration // until the bucket is full again
+}
+
+// Window describes a fixed-window quota: Limit units per Length. Windows
+// are aligned to the clock, so daily windows follow UTC days.
+type Window struct {
+	Limit  int
+	Length time.Duration
+}
+
+// WindowResult is the state of a window after a Consume
+type WindowResult struct {
+	Allowed    bool
+	Remaining  int           // units left in the current window
+	ResetAfter time.Duration // until the next window starts
+}
+
+// BucketStore keeps token buckets and quota windows. Implementations must
+// be safe for concurrent use.
+type BucketStore interface {
+	Name() string
+	// Take removes n tokens from the bucket at key if it holds that many
+	Take(ctx context.Context, key string, bucket Bucket, n int) (BucketResult, error)
+	// Consume adds n units to the current window at key if they fit its limit
+	Consume(ctx context.Context, key string, window Window, n int) (WindowResult, error)
+}
+
+// result derives the reported state from the tokens left in a bucket
+func (b Bucket) result(allowed bool, tokens float64, n int) BucketResult {
+	r := BucketResult{
+		Allowed:    allowed,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsToDuration((float64(b.Burst) - tokens) / b.Rate),
+	}
+	if !allowed {
+		r.RetryAfter = secondsToDuration((float64(n) - tokens) / b.Rate)
+	}
+	return r
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// MemoryBuckets keeps buckets in this process. Limits are per replica and
+// quotas start over when the process restarts.
+type MemoryBuckets struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	windows map[string]*memoryWindow
+}
+
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // when the bucket will have refilled completely
+}
+
+type memoryWindow struct {
+	end  time.Time
+	used int
+}
+
+func NewMemoryBuckets() *MemoryBuckets {
+	m := &MemoryBuckets{
+		buckets: make(map[string]*memoryBucket),
+		windows: make(map[string]*memoryWindow),
+	}
+
+	// Start cleanup goroutine
+	go m.cleanup()
+
+	return m
+}
+
+func (m *MemoryBuckets) Name() string { return "memory" }
+
+func (m *MemoryBuckets) Take(_ context.Context, key string, bucket Bucket, n int) (BucketResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(bucket.Burst), last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(bucket.Burst), b.tokens+now.Sub(b.last).Seconds()*bucket.Rate)
+	b.last = now
+
+	allowed := b.tokens >= float64(n)
+	if allowed {
+		b.tokens -= float64(n)
+	}
+
+	result := bucket.result(allowed, b.tokens, n)
+	b.full = now.Add(result.ResetAfter)
+	return result, nil
+}
+
+func (m *MemoryBuckets) Consume(_ context.Context, key string, window Window, n int) (WindowResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	w, ok := m.windows[key]
+	if !ok || !now.Before(w.end) {
+		w = &memoryWindow{end: now.UTC().Truncate(window.Length).Add(window.Length)}
+		m.windows[key] = w
+	}
+
+	allowed := w.used+n <= window.Limit
+	if allowed {
+		w.used += n
+	}
+	return WindowResult{
+		Allowed:    allowed,
+		Remaining:  max(window.Limit-w.used, 0),
+		ResetAfter: w.end.Sub(now),
+	}, nil
+}
+
+// cleanup drops buckets that have refilled and windows that have ended,
+// which behave like new ones
+func (m *MemoryBuckets) cleanup() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+		m.mu.Lock()
+		for key, b := range m.buckets {
+			if now.After(b.full) {
+				delete(m.buckets, key)
+			}
+		}
+		for key, w := range m.windows {
+			if !now.Before(w.end) {
+				delete(m.windows, key)
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// takeScript refills and takes from a bucket stored as a hash, using the
+// Redis clock so replicas with skewed clocks agree
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('EXPIRE', KEYS[1], math.ceil(burst / rate) + 1)
+return {allowed, tostring(tokens)}
+`)
+
+// consumeScript adds to a fixed window stored as a hash, starting a new
+// window when the Redis clock has moved past the stored one
+var consumeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local length = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+
+local now = tonumber(redis.call('TIME')[1])
+local start = now - (now % length)
+
+local state = redis.call('HMGET', KEYS[1], 'start', 'used')
+local used = 0
+if tonumber(state[1]) == start then
+	used = tonumber(state[2]) or 0
+end
+
+local allowed = 0
+if used + n <= limit then
+	used = used + n
+	allowed = 1
+	redis.call('HSET', KEYS[1], 'start', start, 'used', used)
+	redis.call('EXPIRE', KEYS[1], start + length - now + 60)
+end
+return {allowed, used, start + length - now}
+`)
+
+// RedisBuckets keeps buckets in Redis so every replica shares them. When
+// Redis fails, requests are limited by local buckets instead of failing.
+type RedisBuckets struct {
+	client   *redis.Client
+	prefix   string
+	fallback BucketStore
+	logger   *logrus.Logger
+
+	mu         sync.Mutex
+	lastWarned time.Time
+}
+
+func NewRedisBuckets(client *redis.Client, prefix string, logger *logrus.Logger) *RedisBuckets {
+	return &RedisBuckets{
+		client:   client,
+		prefix:   prefix,
+		fallback: NewMemoryBuckets(),
+		logger:   logger,
+	}
+}
+
+func (r *RedisBuckets) Name() string { return "redis" }
+
+func (r *RedisBuckets) Take(ctx context.Context, key string, bucket Bucket, n int) (BucketResult, error) {
+	reply, err := takeScript.Run(ctx, r.client, []string{r.prefix + key},
+		bucket.Rate, bucket.Burst, n).Slice()
+	if err == nil && len(reply) == 2 {
+		allowed, _ := reply[0].(int64)
+		text, _ := reply[1].(string)
+		tokens, parseErr := strconv.ParseFloat(text, 64)
+		if parseErr == nil {
+			return bucket.result(allowed == 1, tokens, n), nil
+		}
+		err = parseErr
+	}
+	if err == nil {
+		err = fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+
+	r.warn(err)
+	return r.fallback.Take(ctx, key, bucket, n)
+}
+
+func (r *RedisBuckets) Consume(ctx context.Context, key string, window Window, n int) (WindowResult, error) {
+	length := int64(window.Length / time.Second)
+	if length < 1 {
+		length = 1
+	}
+	reply, err := consumeScript.Run(ctx, r.client, []string{r.prefix + key},
+		window.Limit, length, n).Int64Slice()
+	if err == nil && len(reply) == 3 {
+		return WindowResult{
+			Allowed:    reply[0] == 1,
+			Remaining:  max(window.Limit-int(reply[1]), 0),
+			ResetAfter: time.Duration(reply[2]) * time.Second,
+		}, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("unexpected quota reply %v", reply)
+	}
+
+	r.warn(err)
+	return r.fallback.Consume(ctx, key, window, n)
+}
+
+// warn logs Redis failures at most once a minute; during an outage every
+// request would otherwise log
+func (r *RedisBuckets) warn(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.lastWarned) < time.Minute {
+		return
+	}
+	r.lastWarned = time.Now()
+	r.logger.WithError(err).Warn("Rate limit store unavailable, limiting per replica")
+}
@@ -0,0 +1,204 @@
+// backend/internal/middleware/cors.go
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig describes which browser origins may call the API
+type CORSConfig struct {
+	// AllowedOrigins holds exact origins ("https://ophelia.example"),
+	// wildcard subdomains ("https://*.example.com") or "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string // response headers scripts may read
+	AllowCredentials bool
+	MaxAge           time.Duration // how long browsers may cache a preflight
+}
+
+// cors is a CORSConfig prepared for matching
+type cors struct {
+	anyOrigin   bool
+	origins     map[string]bool
+	wildcards   []wildcardOrigin
+	methods     map[string]bool
+	headers     map[string]bool
+	credentials bool
+
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	maxAge        string
+}
+
+// wildcardOrigin matches origins such as https://a.example.com against
+// https://*.example.com
+type wildcardOrigin struct {
+	prefix string // scheme and "://"
+	suffix string // "." and the parent domain, with any port
+}
+
+func (w wildcardOrigin) match(origin string) bool {
+	if len(origin) <= len(w.prefix)+len(w.suffix) {
+		return false
+	}
+	if !strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+		return false
+	}
+	sub := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+	return !strings.ContainsAny(sub, "/:@")
+}
+
+// CORS answers preflight requests and adds CORS headers for allowed
+// origins. Requests from other origins get no CORS headers, so browsers
+// keep their responses from the calling page. Register it before rate
+// limiting and authentication so their errors are readable too.
+func CORS(cfg CORSConfig) (gin.HandlerFunc, error) {
+	c := &cors{
+		origins:     make(map[string]bool),
+		methods:     make(map[string]bool),
+		headers:     make(map[string]bool),
+		credentials: cfg.AllowCredentials,
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "":
+		case origin == "*":
+			c.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			scheme, host, ok := strings.Cut(origin, "://")
+			if !ok || !strings.HasPrefix(host, "*.") || strings.Count(origin, "*") != 1 {
+				return nil, fmt.Errorf("invalid CORS origin %q: wildcards must look like https://*.example.com", origin)
+			}
+			c.wildcards = append(c.wildcards, wildcardOrigin{prefix: scheme + "://", suffix: host[1:]})
+		default:
+			if !strings.Contains(origin, "://") {
+				return nil, fmt.Errorf("invalid CORS origin %q: include the scheme", origin)
+			}
+			c.origins[origin] = true
+		}
+	}
+	if c.anyOrigin && c.credentials {
+		return nil, errors.New("CORS cannot allow credentials for any origin; list the origins instead")
+	}
+
+	methods := make([]string, 0, len(cfg.AllowedMethods))
+	for _, method := range cfg.AllowedMethods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if method != "" && !c.methods[method] {
+			c.methods[method] = true
+			methods = append(methods, method)
+		}
+	}
+	for _, header := range cfg.AllowedHeaders {
+		if header = strings.TrimSpace(header); header != "" {
+			c.headers[strings.ToLower(header)] = true
+		}
+	}
+
+	c.allowMethods = strings.Join(methods, ", ")
+	c.allowHeaders = strings.Join(cfg.AllowedHeaders, ", ")
+	c.exposeHeaders = strings.Join(cfg.ExposedHeaders, ", ")
+	if cfg.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
+	return c.handle, nil
+}
+
+func (cfg *cors) handle(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+	// Responses differ by origin unless every origin gets the same answer,
+	// so shared caches must key on it
+	if !cfg.anyOrigin {
+		c.Writer.Header().Add("Vary", "Origin")
+	}
+	if preflight {
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+	}
+
+	if origin == "" {
+		c.Next()
+		return
+	}
+
+	if !cfg.allowOrigin(origin) {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+		return
+	}
+	if preflight && (!cfg.methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] ||
+		!cfg.allowRequestHeaders(c.GetHeader("Access-Control-Request-Headers"))) {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	if cfg.anyOrigin {
+		c.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		c.Header("Access-Control-Allow-Origin", origin)
+	}
+	if cfg.credentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+
+	if !preflight {
+		if cfg.exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", cfg.exposeHeaders)
+		}
+		c.Next()
+		return
+	}
+
+	c.Header("Access-Control-Allow-Methods", cfg.allowMethods)
+	if cfg.allowHeaders != "" {
+		c.Header("Access-Control-Allow-Headers", cfg.allowHeaders)
+	}
+	if cfg.maxAge != "" {
+		c.Header("Access-Control-Max-Age", cfg.maxAge)
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+func (cfg *cors) allowOrigin(origin string) bool {
+	if cfg.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if cfg.origins[origin] {
+		return true
+	}
+	for _, w := range cfg.wildcards {
+		if w.match(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowRequestHeaders checks the headers a preflight asks to send
+func (cfg *cors) allowRequestHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header != "" && !cfg.headers[header] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+// backend/internal/middleware/logging.go
+package middleware
+
+import (
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestLogger replaces gin.Logger with a structured access log. It stores a
+// request-scoped entry carrying request_id, session and route in the request
+// context, where handlers, services and clients pick it up through
+// utils.LoggerFromContext. It must run after RequestID.
+func RequestLogger(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		session := utils.SessionFromRequest(c)
+		c.Set("user_session", session)
+
+		entry := logger.WithFields(logrus.Fields{
+			"request_id": c.GetString("request_id"),
+			"session":    session,
+			"route":      route,
+			"method":     c.Request.Method,
+		})
+		c.Request = c.Request.WithContext(utils.WithLogger(c.Request.Context(), entry))
+
+		c.Next()
+
+		// Pick up fields added further down the chain (e.g. trace_id)
+		entry = utils.LoggerFromContext(c.Request.Context(), logger)
+
+		status := c.Writer.Status()
+		fields := logrus.Fields{
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  utils.RedactIP(c.ClientIP()),
+			"bytes_out":  c.Writer.Size(),
+			"path":       c.Request.URL.Path,
+		}
+		if len(c.Errors) > 0 {
+			fields["errors"] = c.Errors.String()
+		}
+
+		accessLog := entry.WithFields(fields)
+		switch {
+		case status >= 500:
+			accessLog.Error("Request completed")
+		case status >= 400:
+			accessLog.Warn("Request completed")
+		default:
+			accessLog.Info("Request completed")
+		}
+	}
+}
@@ -2,97 +2,166 @@
 package middleware
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
-// RateLimiter implements a simple in-memory rate limiter
+// Identities a rate limit policy can count requests by
+const (
+	IdentityIP      = "ip"
+	IdentitySession = "session"
+)
+
+// Policy limits requests per identity with a token bucket
+type Policy struct {
+	Name     string // distinguishes buckets of different policies
+	Identity string // IdentityIP or IdentitySession
+	Limit    int    // requests allowed per Period once the burst is spent
+	Period   time.Duration
+	Burst    int // requests allowed at once
+}
+
+func (p Policy) bucket() Bucket {
+	return Bucket{Rate: float64(p.Limit) / p.Period.Seconds(), Burst: p.Burst}
+}
+
+// Limits are the policies a RateLimiter applies
+type Limits struct {
+	Global  []Policy            // every request by client IP, before authentication
+	Default []Policy            // for routes without their own policies
+	Routes  map[string][]Policy // by route path
+}
+
+// RateLimiter applies token bucket policies per route and identity.
+// Anonymous requests are counted by client IP and session; requests with an
+// API key are counted against the key's own rate instead.
 type RateLimiter struct {
-	visitors map[string]*Visitor
-	mu       sync.RWMutex
-	rate     int           // requests per minute
-	cleanup  time.Duration // cleanup interval
+	store  BucketStore
+	logger *logrus.Logger
+
+	mu     sync.RWMutex
+	limits Limits
 }
 
-type Visitor struct {
-	lastSeen time.Time
-	count    int
+func NewRateLimiter(store BucketStore, limits Limits, logger *logrus.Logger) *RateLimiter {
+	return &RateLimiter{
+		store:  store,
+		limits: limits,
+		logger: logger,
+	}
+}
+
+// SetLimits replaces the policies applied to later requests. Buckets keep
+// the tokens they hold and refill at the new rate.
+func (rl *RateLimiter) SetLimits(limits Limits) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limits = limits
+}
+
+func (rl *RateLimiter) current() Limits {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return rl.limits
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(rate int) *RateLimiter {
-	rl := &RateLimiter{
-		visitors: make(map[string]*Visitor),
-		rate:     rate,
-		cleanup:  time.Minute,
+// Global limits every request by client IP, whatever route or credentials
+// it has. Run it before authentication so failed attempts are counted too.
+func (rl *RateLimiter) Global() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rl.allow(c, rl.current().Global) {
+			return
+		}
+		c.Next()
 	}
-	
-	// Start cleanup goroutine
-	go rl.cleanupVisitors()
-	
-	return rl
 }
 
-// RateLimit middleware function
+// RateLimit applies the route's policies, or the API key's rate for
+// requests made with a key. Run it after APIKeyAuth.
 func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		
-		rl.mu.Lock()
-		v, exists := rl.visitors[ip]
-		if !exists {
-			rl.visitors[ip] = &Visitor{
-				lastSeen: time.Now(),
-				count:    1,
+		if key := APIKeyFromContext(c); key != nil {
+			if key.RatePerMinute > 0 {
+				policy := Policy{
+					Name:   "key",
+					Limit:  key.RatePerMinute,
+					Period: time.Minute,
+					Burst:  key.RatePerMinute,
+				}
+				if !rl.take(c, policy, "key:"+strconv.FormatUint(uint64(key.ID), 10)) {
+					return
+				}
 			}
-			rl.mu.Unlock()
 			c.Next()
 			return
 		}
-		
-		// Reset count if more than a minute has passed
-		if time.Since(v.lastSeen) > time.Minute {
-			v.count = 1
-			v.lastSeen = time.Now()
-			rl.mu.Unlock()
-			c.Next()
-			return
+
+		limits := rl.current()
+		policies, ok := limits.Routes[c.FullPath()]
+		if !ok {
+			policies = limits.Default
 		}
-		
-		// Check if rate limit exceeded
-		if v.count >= rl.rate {
-			rl.mu.Unlock()
-			utils.ErrorResponse(c, http.StatusTooManyRequests, "Rate limit exceeded", nil)
-			c.Abort()
+		if !rl.allow(c, policies) {
 			return
 		}
-		
-		v.count++
-		v.lastSeen = time.Now()
-		rl.mu.Unlock()
-		
 		c.Next()
 	}
 }
 
-// cleanupVisitors removes old visitor entries
-func (rl *RateLimiter) cleanupVisitors() {
-	ticker := time.NewTicker(rl.cleanup)
-	defer ticker.Stop()
-	
-	for range ticker.C {
-		rl.mu.Lock()
-		for ip, v := range rl.visitors {
-			if time.Since(v.lastSeen) > time.Minute*5 {
-				delete(rl.visitors, ip)
-			}
+// allow takes a token from each policy's bucket for this client, stopping
+// at the first that is empty
+func (rl *RateLimiter) allow(c *gin.Context, policies []Policy) bool {
+	for _, policy := range policies {
+		var identity string
+		switch policy.Identity {
+		case IdentitySession:
+			identity = "session:" + utils.SessionFromRequest(c)
+		default:
+			identity = "ip:" + c.ClientIP()
+		}
+		if !rl.take(c, policy, identity) {
+			return false
 		}
-		rl.mu.Unlock()
 	}
+	return true
+}
+
+// take spends one token and sets the X-RateLimit-* headers. When several
+// policies apply, the last one checked sets them; a rejection stops there,
+// so a 429 always describes the bucket that ran out.
+func (rl *RateLimiter) take(c *gin.Context, policy Policy, identity string) bool {
+	result, err := rl.store.Take(c.Request.Context(), policy.Name+":"+identity, policy.bucket(), 1)
+	if err != nil {
+		// Limiting is best effort; an unavailable store must not take the API down
+		utils.LoggerFromContext(c.Request.Context(), rl.logger).WithError(err).Warn("Rate limit check failed")
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(policy.Burst))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	c.Header("X-RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d;name=%q",
+		policy.Limit, int(policy.Period.Seconds()), policy.Burst, policy.Name))
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		utils.ErrorResponse(c, http.StatusTooManyRequests, "Rate limit exceeded", nil)
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
 
 // Security middleware
@@ -117,6 +186,7 @@ func RequestID() gin.HandlerFunc {
 		
 		c.Header("X-Request-ID", requestID)
 		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(utils.WithRequestID(c.Request.Context(), requestID))
 		c.Next()
 	}
 }
\ No newline at end of file
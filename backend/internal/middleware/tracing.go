@@ -0,0 +1,57 @@
+// backend/internal/middleware/tracing.go
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for every request and stores it in the request
+// context so handlers, services and clients create child spans. It must run
+// after RequestID so the span can be tagged with the request ID.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []attribute.KeyValue{
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+		}
+		if requestID, ok := c.Get("request_id"); ok {
+			attrs = append(attrs, attribute.String("request.id", fmt.Sprint(requestID)))
+		}
+
+		ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		if sc := span.SpanContext(); sc.IsValid() {
+			entry := utils.LoggerFromContext(ctx, nil).WithField("trace_id", sc.TraceID().String())
+			ctx = utils.WithLogger(ctx, entry)
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+	}
+}
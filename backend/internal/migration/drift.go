@@ -0,0 +1,592 @@
+// backend/internal/migration/drift.go
+package migration
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// Difference is one way the database departs from the models
+type Difference struct {
+	Table  string
+	Kind   string // table, column, primary key, index, check or foreign key
+	Name   string // the column or constraint, empty for tables
+	Detail string
+}
+
+func (d Difference) String() string {
+	if d.Name == "" {
+		return fmt.Sprintf("%s %s: %s", d.Kind, d.Table, d.Detail)
+	}
+	return fmt.Sprintf("%s %s.%s: %s", d.Kind, d.Table, d.Name, d.Detail)
+}
+
+// tableDef describes a table the same way for the models and the database,
+// so the two can be compared
+type tableDef struct {
+	Name        string
+	Columns     map[string]columnDef
+	PrimaryKey  []string
+	Indexes     []indexDef
+	Checks      []checkDef
+	ForeignKeys []foreignKeyDef
+}
+
+type columnDef struct {
+	Name    string
+	Type    string          // SQL type; for models only when the tag pins one
+	Kind    schema.DataType // Go kind of a model field, when Type is empty
+	NotNull bool
+}
+
+type indexDef struct {
+	Name    string
+	Unique  bool
+	Columns []string // "col" or "col DESC"
+}
+
+func (i indexDef) key() string {
+	kind := "index"
+	if i.Unique {
+		kind = "unique index"
+	}
+	return fmt.Sprintf("%s (%s)", kind, strings.Join(i.Columns, ", "))
+}
+
+type checkDef struct {
+	Name       string
+	Columns    []string
+	Definition string
+}
+
+// key compares checks by their columns and the string literals they allow,
+// since Postgres rewrites the expression: IN ('a','b') comes back as
+// = ANY (ARRAY['a'::character varying, ...]). Checks without literals
+// match on their columns alone.
+func (c checkDef) key() string {
+	return fmt.Sprintf("check on (%s) allowing [%s]",
+		strings.Join(c.Columns, ", "), strings.Join(checkLiterals(c.Definition), ", "))
+}
+
+type foreignKeyDef struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+}
+
+func (f foreignKeyDef) key() string {
+	return fmt.Sprintf("(%s) references %s (%s) on delete %s",
+		strings.Join(f.Columns, ", "), f.RefTable, strings.Join(f.RefColumns, ", "), f.OnDelete)
+}
+
+// ignoredTables exist in the database but have no model
+var ignoredTables = map[string]bool{
+	"schema_migrations": true,
+}
+
+// Drift compares the tables, columns, types, nullability, indexes and
+// constraints of the current database schema against the models, which
+// are the GORM structs of every mapped table. Names of indexes and
+// constraints are not compared, only what they enforce.
+func (r *Runner) Drift(ctx context.Context, tables []interface{}) ([]Difference, error) {
+	expected, err := modelTables(tables, r.dbManager.DB.NamingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	db, err := r.dbManager.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	live, err := liveTables(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return compareTables(expected, live), nil
+}
+
+// modelTables describes the tables the models map to
+func modelTables(tables []interface{}, namer schema.Namer) (map[string]*tableDef, error) {
+	cache := &sync.Map{}
+	schemas := make([]*schema.Schema, 0, len(tables))
+	defs := make(map[string]*tableDef)
+	for _, model := range tables {
+		s, err := schema.Parse(model, cache, namer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		schemas = append(schemas, s)
+
+		t := &tableDef{
+			Name:       s.Table,
+			Columns:    make(map[string]columnDef),
+			PrimaryKey: s.PrimaryFieldDBNames,
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			t.Columns[field.DBName] = modelColumn(field)
+			if field.Unique {
+				t.Indexes = append(t.Indexes, indexDef{
+					Name:    namer.UniqueName(s.Table, field.DBName),
+					Unique:  true,
+					Columns: []string{field.DBName},
+				})
+			}
+		}
+		for _, idx := range s.ParseIndexes() {
+			def := indexDef{Name: idx.Name, Unique: idx.Class == "UNIQUE"}
+			for _, opt := range idx.Fields {
+				column := opt.Expression
+				if column == "" {
+					column = opt.DBName
+				}
+				if strings.EqualFold(opt.Sort, "desc") {
+					column += " DESC"
+				}
+				def.Columns = append(def.Columns, column)
+			}
+			t.Indexes = append(t.Indexes, def)
+		}
+		for _, chk := range s.ParseCheckConstraints() {
+			t.Checks = append(t.Checks, checkDef{
+				Name:       chk.Name,
+				Columns:    []string{chk.Field.DBName},
+				Definition: chk.Constraint,
+			})
+		}
+		defs[s.Table] = t
+	}
+
+	// A relationship's foreign key lives on whichever table holds the
+	// column, which for has-many is the other model
+	for _, s := range schemas {
+		for _, rel := range s.Relationships.Relations {
+			c := rel.ParseConstraint()
+			if c == nil || c.Schema == nil || c.ReferenceSchema == nil {
+				continue
+			}
+			t, ok := defs[c.Schema.Table]
+			if !ok {
+				continue
+			}
+			fk := foreignKeyDef{
+				Name:     c.Name,
+				RefTable: c.ReferenceSchema.Table,
+				OnDelete: strings.ToUpper(c.OnDelete),
+			}
+			if fk.OnDelete == "" {
+				fk.OnDelete = "NO ACTION"
+			}
+			for i := range c.ForeignKeys {
+				fk.Columns = append(fk.Columns, c.ForeignKeys[i].DBName)
+				fk.RefColumns = append(fk.RefColumns, c.References[i].DBName)
+			}
+			if !hasForeignKey(t.ForeignKeys, fk) {
+				t.ForeignKeys = append(t.ForeignKeys, fk)
+			}
+		}
+	}
+	return defs, nil
+}
+
+func hasForeignKey(list []foreignKeyDef, fk foreignKeyDef) bool {
+	for _, f := range list {
+		if f.key() == fk.key() {
+			return true
+		}
+	}
+	return false
+}
+
+func modelColumn(field *schema.Field) columnDef {
+	col := columnDef{
+		Name:    field.DBName,
+		Kind:    field.GORMDataType,
+		NotNull: field.NotNull || field.PrimaryKey,
+	}
+	switch field.DataType {
+	case schema.Bool, schema.Int, schema.Uint, schema.Float, schema.String, schema.Time, schema.Bytes, "":
+	default:
+		// type:... in the tag names the exact SQL type
+		col.Type = normalizeType(string(field.DataType))
+	}
+	return col
+}
+
+// typeAliases maps the spellings migrations use to the names Postgres
+// reports through format_type
+var typeAliases = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"serial":      "integer",
+	"int8":        "bigint",
+	"bigserial":   "bigint",
+	"int2":        "smallint",
+	"smallserial": "smallint",
+	"bool":        "boolean",
+	"float4":      "real",
+	"float8":      "double precision",
+	"decimal":     "numeric",
+	"varchar":     "character varying",
+	"char":        "character",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"time":        "time without time zone",
+	"timetz":      "time with time zone",
+}
+
+// normalizeType spells a SQL type the way format_type does, so that
+// VARCHAR(255) and character varying(255) compare equal
+func normalizeType(t string) string {
+	t = strings.ToLower(strings.Join(strings.Fields(t), " "))
+	array := ""
+	for strings.HasSuffix(t, "[]") {
+		array += "[]"
+		t = strings.TrimSpace(strings.TrimSuffix(t, "[]"))
+	}
+	name, args := splitTypeArgs(t)
+	if alias, ok := typeAliases[name]; ok {
+		name = alias
+	}
+	// format_type puts the length before the time zone qualifier
+	if strings.HasPrefix(name, "timestamp ") || strings.HasPrefix(name, "time ") {
+		base, zone, _ := strings.Cut(name, " ")
+		return base + args + " " + zone + array
+	}
+	return name + args + array
+}
+
+// splitTypeArgs separates a type's modifier, as in timestamp(6) with time
+// zone, from its name
+func splitTypeArgs(t string) (name, args string) {
+	open, close := strings.IndexByte(t, '('), strings.IndexByte(t, ')')
+	if open < 0 || close < open {
+		return t, ""
+	}
+	name = strings.Join(strings.Fields(t[:open]+" "+t[close+1:]), " ")
+	return name, strings.ReplaceAll(t[open:close+1], " ", "")
+}
+
+// typeFamilies lists the database types a model field of each Go kind can
+// be stored in when the model leaves the exact type to the migration
+var typeFamilies = map[schema.DataType][]string{
+	schema.Bool:   {"boolean"},
+	schema.Int:    {"smallint", "integer", "bigint"},
+	schema.Uint:   {"smallint", "integer", "bigint"},
+	schema.Float:  {"real", "double precision", "numeric"},
+	schema.String: {"text", "character varying", "character"},
+	schema.Time:   {"timestamp without time zone", "timestamp with time zone", "date"},
+	schema.Bytes:  {"bytea"},
+}
+
+// typeMatches reports whether the database type live suits the model column
+func typeMatches(model columnDef, live string) bool {
+	live = normalizeType(live)
+	if model.Type != "" {
+		return model.Type == live
+	}
+	family, ok := typeFamilies[model.Kind]
+	if !ok {
+		return true
+	}
+	live, _ = splitTypeArgs(live)
+	for _, t := range family {
+		if live == t {
+			return true
+		}
+	}
+	return false
+}
+
+func describeType(col columnDef) string {
+	if col.Type != "" {
+		return col.Type
+	}
+	return "a type holding a Go " + string(col.Kind)
+}
+
+var literalPattern = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// checkLiterals returns the sorted string literals in a check expression
+func checkLiterals(def string) []string {
+	var literals []string
+	for _, m := range literalPattern.FindAllStringSubmatch(def, -1) {
+		literals = append(literals, "'"+m[1]+"'")
+	}
+	sort.Strings(literals)
+	return literals
+}
+
+// compareTables lists the differences between the model tables and the
+// live ones, sorted by table
+func compareTables(expected, live map[string]*tableDef) []Difference {
+	var diffs []Difference
+	for name, want := range expected {
+		got, ok := live[name]
+		if !ok {
+			diffs = append(diffs, Difference{Table: name, Kind: "table", Detail: "missing from the database"})
+			continue
+		}
+		diffs = append(diffs, compareTable(want, got)...)
+	}
+	for name := range live {
+		if _, ok := expected[name]; !ok && !ignoredTables[name] {
+			diffs = append(diffs, Difference{Table: name, Kind: "table", Detail: "not in the models"})
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Table != diffs[j].Table {
+			return diffs[i].Table < diffs[j].Table
+		}
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+func compareTable(want, got *tableDef) []Difference {
+	var diffs []Difference
+	add := func(kind, name, format string, args ...interface{}) {
+		diffs = append(diffs, Difference{Table: want.Name, Kind: kind, Name: name, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	for name, w := range want.Columns {
+		g, ok := got.Columns[name]
+		if !ok {
+			add("column", name, "missing from the database")
+			continue
+		}
+		if !typeMatches(w, g.Type) {
+			add("column", name, "type is %s, the model expects %s", g.Type, describeType(w))
+		}
+		if w.NotNull != g.NotNull {
+			if w.NotNull {
+				add("column", name, "nullable, the model expects NOT NULL")
+			} else {
+				add("column", name, "NOT NULL, the model expects it nullable")
+			}
+		}
+	}
+	for name := range got.Columns {
+		if _, ok := want.Columns[name]; !ok {
+			add("column", name, "not in the model")
+		}
+	}
+
+	if strings.Join(want.PrimaryKey, ",") != strings.Join(got.PrimaryKey, ",") {
+		add("primary key", "", "is (%s), the model expects (%s)",
+			strings.Join(got.PrimaryKey, ", "), strings.Join(want.PrimaryKey, ", "))
+	}
+
+	// Indexes and constraints match on what they enforce; the SQL and GORM
+	// name them differently and a database may hold duplicates
+	wantIdx, gotIdx := make(map[string]string), make(map[string]string)
+	for _, i := range want.Indexes {
+		wantIdx[i.key()] = i.Name
+	}
+	for _, i := range got.Indexes {
+		gotIdx[i.key()] = i.Name
+	}
+	diffKeys(wantIdx, gotIdx, func(key, name string, missing bool) {
+		if missing {
+			add("index", name, "%s missing from the database", key)
+		} else {
+			add("index", name, "%s not in the model", key)
+		}
+	})
+
+	wantChk, gotChk := make(map[string]string), make(map[string]string)
+	for _, c := range want.Checks {
+		wantChk[c.key()] = c.Name
+	}
+	for _, c := range got.Checks {
+		gotChk[c.key()] = c.Name
+	}
+	diffKeys(wantChk, gotChk, func(key, name string, missing bool) {
+		if missing {
+			add("check", name, "%s missing from the database", key)
+		} else {
+			add("check", name, "%s not in the model", key)
+		}
+	})
+
+	wantFK, gotFK := make(map[string]string), make(map[string]string)
+	for _, f := range want.ForeignKeys {
+		wantFK[f.key()] = f.Name
+	}
+	for _, f := range got.ForeignKeys {
+		gotFK[f.key()] = f.Name
+	}
+	diffKeys(wantFK, gotFK, func(key, name string, missing bool) {
+		if missing {
+			add("foreign key", name, "%s missing from the database", key)
+		} else {
+			add("foreign key", name, "%s not in the model", key)
+		}
+	})
+	return diffs
+}
+
+// diffKeys calls report for every key only one side has
+func diffKeys(want, got map[string]string, report func(key, name string, missing bool)) {
+	for key, name := range want {
+		if _, ok := got[key]; !ok {
+			report(key, name, true)
+		}
+	}
+	for key, name := range got {
+		if _, ok := want[key]; !ok {
+			report(key, name, false)
+		}
+	}
+}
+
+const (
+	columnsQuery = `
+SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p')
+  AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY c.relname, a.attnum`
+
+	indexesQuery = `
+SELECT t.relname, i.relname, ix.indisunique, ix.indisprimary,
+       array_to_string(ARRAY(
+           SELECT pg_get_indexdef(ix.indexrelid, k, true)
+                  || CASE WHEN ix.indoption[k - 1] & 1 = 1 THEN ' DESC' ELSE '' END
+           FROM generate_series(1, ix.indnkeyatts) AS k
+           ORDER BY k), ',')
+FROM pg_index ix
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE n.nspname = current_schema()
+ORDER BY t.relname, i.relname`
+
+	constraintsQuery = `
+SELECT t.relname, con.conname, con.contype::text, pg_get_constraintdef(con.oid, true),
+       array_to_string(ARRAY(
+           SELECT a.attname FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+           JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+           ORDER BY k.ord), ','),
+       COALESCE(f.relname, ''),
+       array_to_string(ARRAY(
+           SELECT a.attname FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+           JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+           ORDER BY k.ord), ','),
+       con.confdeltype::text
+FROM pg_constraint con
+JOIN pg_class t ON t.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+LEFT JOIN pg_class f ON f.oid = con.confrelid
+WHERE n.nspname = current_schema() AND con.contype IN ('c', 'f')
+ORDER BY t.relname, con.conname`
+)
+
+// onDeleteActions maps pg_constraint.confdeltype to its SQL spelling
+var onDeleteActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// liveTables describes the tables in the database's current schema
+func liveTables(ctx context.Context, q querier) (map[string]*tableDef, error) {
+	defs := make(map[string]*tableDef)
+	table := func(name string) *tableDef {
+		t, ok := defs[name]
+		if !ok {
+			t = &tableDef{Name: name, Columns: make(map[string]columnDef)}
+			defs[name] = t
+		}
+		return t
+	}
+
+	rows, err := q.QueryContext(ctx, columnsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tableName string
+		var col columnDef
+		if err := rows.Scan(&tableName, &col.Name, &col.Type, &col.NotNull); err != nil {
+			return nil, err
+		}
+		table(tableName).Columns[col.Name] = col
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.QueryContext(ctx, indexesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tableName, columns string
+		var idx indexDef
+		var primary bool
+		if err := rows.Scan(&tableName, &idx.Name, &idx.Unique, &primary, &columns); err != nil {
+			return nil, err
+		}
+		idx.Columns = splitList(columns)
+		if primary {
+			table(tableName).PrimaryKey = idx.Columns
+			continue
+		}
+		table(tableName).Indexes = append(table(tableName).Indexes, idx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.QueryContext(ctx, constraintsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tableName, name, kind, def, columns, refTable, refColumns, onDelete string
+		if err := rows.Scan(&tableName, &name, &kind, &def, &columns, &refTable, &refColumns, &onDelete); err != nil {
+			return nil, err
+		}
+		t := table(tableName)
+		if kind == "c" {
+			t.Checks = append(t.Checks, checkDef{Name: name, Columns: splitList(columns), Definition: def})
+			continue
+		}
+		t.ForeignKeys = append(t.ForeignKeys, foreignKeyDef{
+			Name:       name,
+			Columns:    splitList(columns),
+			RefTable:   refTable,
+			RefColumns: splitList(refColumns),
+			OnDelete:   onDeleteActions[onDelete],
+		})
+	}
+	return defs, rows.Err()
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,471 @@
+// backend/internal/migration/drift_test.go
+package migration
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "text", want: "text"},
+		{in: "JSONB", want: "jsonb"},
+		{in: "VARCHAR(255)", want: "character varying(255)"},
+		{in: "character varying(255)", want: "character varying(255)"},
+		{in: "int", want: "integer"},
+		{in: "INT4", want: "integer"},
+		{in: "serial", want: "integer"},
+		{in: "bigserial", want: "bigint"},
+		{in: "bool", want: "boolean"},
+		{in: "float8", want: "double precision"},
+		{in: "  double   precision ", want: "double precision"},
+		{in: "decimal(10, 2)", want: "numeric(10,2)"},
+		{in: "timestamp", want: "timestamp without time zone"},
+		{in: "TIMESTAMPTZ", want: "timestamp with time zone"},
+		{in: "timestamptz(3)", want: "timestamp(3) with time zone"},
+		{in: "timestamp(6) with time zone", want: "timestamp(6) with time zone"},
+		{in: "timetz", want: "time with time zone"},
+		{in: "text[]", want: "text[]"},
+		{in: "INT[]", want: "integer[]"},
+		{in: "varchar(20)[][]", want: "character varying(20)[][]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := normalizeType(tt.in); got != tt.want {
+				t.Errorf("normalizeType(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		model columnDef
+		live  string
+		want  bool
+	}{
+		{
+			name:  "pinned type equal",
+			model: columnDef{Type: "jsonb"},
+			live:  "jsonb",
+			want:  true,
+		},
+		{
+			name:  "pinned type differs",
+			model: columnDef{Type: "jsonb"},
+			live:  "json",
+			want:  false,
+		},
+		{
+			name:  "pinned type spelled differently",
+			model: columnDef{Type: normalizeType("VARCHAR(255)")},
+			live:  "character varying(255)",
+			want:  true,
+		},
+		{
+			name:  "pinned type length differs",
+			model: columnDef{Type: normalizeType("VARCHAR(255)")},
+			live:  "character varying(100)",
+			want:  false,
+		},
+		{
+			name:  "string in text",
+			model: columnDef{Kind: schema.String},
+			live:  "text",
+			want:  true,
+		},
+		{
+			name:  "string in varchar with length",
+			model: columnDef{Kind: schema.String},
+			live:  "character varying(255)",
+			want:  true,
+		},
+		{
+			name:  "string in integer",
+			model: columnDef{Kind: schema.String},
+			live:  "integer",
+			want:  false,
+		},
+		{
+			name:  "uint in bigint",
+			model: columnDef{Kind: schema.Uint},
+			live:  "bigint",
+			want:  true,
+		},
+		{
+			name:  "time in timestamp with precision",
+			model: columnDef{Kind: schema.Time},
+			live:  "timestamp(6) with time zone",
+			want:  true,
+		},
+		{
+			name:  "bool in integer",
+			model: columnDef{Kind: schema.Bool},
+			live:  "integer",
+			want:  false,
+		},
+		{
+			name:  "unknown kind matches anything",
+			model: columnDef{Kind: "custom"},
+			live:  "tsvector",
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typeMatches(tt.model, tt.live); got != tt.want {
+				t.Errorf("typeMatches(%+v, %q) = %v, want %v", tt.model, tt.live, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLiterals(t *testing.T) {
+	tests := []struct {
+		name string
+		def  string
+		want []string
+	}{
+		{
+			name: "no literals",
+			def:  "rating >= 1 AND rating <= 5",
+			want: nil,
+		},
+		{
+			name: "model IN list sorted",
+			def:  "status IN ('pending','done')",
+			want: []string{"'done'", "'pending'"},
+		},
+		{
+			name: "postgres rewritten ANY array",
+			def:  "CHECK (((status)::text = ANY ((ARRAY['pending'::character varying, 'done'::character varying])::text[])))",
+			want: []string{"'done'", "'pending'"},
+		},
+		{
+			name: "doubled quote kept",
+			def:  "name <> 'it''s'",
+			want: []string{"'it''s'"},
+		},
+		{
+			name: "empty literal",
+			def:  "name <> ''",
+			want: []string{"''"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkLiterals(tt.def); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkLiterals(%q) = %q, want %q", tt.def, got, tt.want)
+			}
+		})
+	}
+}
+
+// modelFixture describes two tables as the models would
+func modelFixture() map[string]*tableDef {
+	return map[string]*tableDef{
+		"users": {
+			Name: "users",
+			Columns: map[string]columnDef{
+				"id":     {Name: "id", Kind: schema.Uint, NotNull: true},
+				"email":  {Name: "email", Type: "character varying(255)", NotNull: true},
+				"status": {Name: "status", Kind: schema.String},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []indexDef{{Name: "idx_users_email", Unique: true, Columns: []string{"email"}}},
+			Checks: []checkDef{{
+				Name:       "chk_users_status",
+				Columns:    []string{"status"},
+				Definition: "status IN ('active','disabled')",
+			}},
+		},
+		"orders": {
+			Name: "orders",
+			Columns: map[string]columnDef{
+				"id":      {Name: "id", Kind: schema.Uint, NotNull: true},
+				"user_id": {Name: "user_id", Kind: schema.Uint, NotNull: true},
+			},
+			PrimaryKey: []string{"id"},
+			ForeignKeys: []foreignKeyDef{{
+				Name:       "fk_users_orders",
+				Columns:    []string{"user_id"},
+				RefTable:   "users",
+				RefColumns: []string{"id"},
+				OnDelete:   "CASCADE",
+			}},
+		},
+	}
+}
+
+// liveFixture describes the same tables as Postgres reports them, with the
+// names and spellings the database uses
+func liveFixture() map[string]*tableDef {
+	return map[string]*tableDef{
+		"users": {
+			Name: "users",
+			Columns: map[string]columnDef{
+				"id":     {Name: "id", Type: "bigint", NotNull: true},
+				"email":  {Name: "email", Type: "character varying(255)", NotNull: true},
+				"status": {Name: "status", Type: "text"},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []indexDef{{Name: "users_email_key", Unique: true, Columns: []string{"email"}}},
+			Checks: []checkDef{{
+				Name:       "users_status_check",
+				Columns:    []string{"status"},
+				Definition: "CHECK (status = ANY (ARRAY['active'::text, 'disabled'::text]))",
+			}},
+		},
+		"orders": {
+			Name: "orders",
+			Columns: map[string]columnDef{
+				"id":      {Name: "id", Type: "bigint", NotNull: true},
+				"user_id": {Name: "user_id", Type: "bigint", NotNull: true},
+			},
+			PrimaryKey: []string{"id"},
+			ForeignKeys: []foreignKeyDef{{
+				Name:       "orders_user_id_fkey",
+				Columns:    []string{"user_id"},
+				RefTable:   "users",
+				RefColumns: []string{"id"},
+				OnDelete:   "CASCADE",
+			}},
+		},
+	}
+}
+
+func TestCompareTables(t *testing.T) {
+	tests := []struct {
+		name  string
+		model func(tables map[string]*tableDef)
+		live  func(tables map[string]*tableDef)
+		want  []Difference
+	}{
+		{
+			name: "no drift",
+			want: nil,
+		},
+		{
+			name: "schema_migrations ignored",
+			live: func(tables map[string]*tableDef) {
+				tables["schema_migrations"] = &tableDef{Name: "schema_migrations", Columns: map[string]columnDef{}}
+			},
+			want: nil,
+		},
+		{
+			name: "missing table",
+			live: func(tables map[string]*tableDef) {
+				delete(tables, "orders")
+			},
+			want: []Difference{
+				{Table: "orders", Kind: "table", Detail: "missing from the database"},
+			},
+		},
+		{
+			name: "extra table",
+			live: func(tables map[string]*tableDef) {
+				tables["audit_log"] = &tableDef{Name: "audit_log", Columns: map[string]columnDef{}}
+			},
+			want: []Difference{
+				{Table: "audit_log", Kind: "table", Detail: "not in the models"},
+			},
+		},
+		{
+			name: "missing and extra columns",
+			live: func(tables map[string]*tableDef) {
+				delete(tables["users"].Columns, "status")
+				tables["users"].Columns["legacy"] = columnDef{Name: "legacy", Type: "text"}
+				tables["users"].Checks = nil
+			},
+			model: func(tables map[string]*tableDef) {
+				tables["users"].Checks = nil
+			},
+			want: []Difference{
+				{Table: "users", Kind: "column", Name: "legacy", Detail: "not in the model"},
+				{Table: "users", Kind: "column", Name: "status", Detail: "missing from the database"},
+			},
+		},
+		{
+			name: "column types",
+			live: func(tables map[string]*tableDef) {
+				tables["users"].Columns["email"] = columnDef{Name: "email", Type: "text", NotNull: true}
+				tables["users"].Columns["id"] = columnDef{Name: "id", Type: "uuid", NotNull: true}
+			},
+			want: []Difference{
+				{Table: "users", Kind: "column", Name: "email", Detail: "type is text, the model expects character varying(255)"},
+				{Table: "users", Kind: "column", Name: "id", Detail: "type is uuid, the model expects a type holding a Go uint"},
+			},
+		},
+		{
+			name: "column nullability",
+			live: func(tables map[string]*tableDef) {
+				tables["users"].Columns["email"] = columnDef{Name: "email", Type: "character varying(255)"}
+				tables["users"].Columns["status"] = columnDef{Name: "status", Type: "text", NotNull: true}
+			},
+			want: []Difference{
+				{Table: "users", Kind: "column", Name: "email", Detail: "nullable, the model expects NOT NULL"},
+				{Table: "users", Kind: "column", Name: "status", Detail: "NOT NULL, the model expects it nullable"},
+			},
+		},
+		{
+			name: "primary key",
+			live: func(tables map[string]*tableDef) {
+				tables["orders"].PrimaryKey = []string{"id", "user_id"}
+			},
+			want: []Difference{
+				{Table: "orders", Kind: "primary key", Detail: "is (id, user_id), the model expects (id)"},
+			},
+		},
+		{
+			name: "index no longer unique",
+			live: func(tables map[string]*tableDef) {
+				tables["users"].Indexes[0].Unique = false
+			},
+			want: []Difference{
+				{Table: "users", Kind: "index", Name: "idx_users_email", Detail: "unique index (email) missing from the database"},
+				{Table: "users", Kind: "index", Name: "users_email_key", Detail: "index (email) not in the model"},
+			},
+		},
+		{
+			name: "duplicate indexes in the database",
+			live: func(tables map[string]*tableDef) {
+				tables["users"].Indexes = append(tables["users"].Indexes,
+					indexDef{Name: "idx_users_email", Unique: true, Columns: []string{"email"}})
+			},
+			want: nil,
+		},
+		{
+			name: "index column order",
+			model: func(tables map[string]*tableDef) {
+				tables["orders"].Indexes = []indexDef{{Name: "idx_orders_user", Columns: []string{"user_id", "id DESC"}}}
+			},
+			live: func(tables map[string]*tableDef) {
+				tables["orders"].Indexes = []indexDef{{Name: "idx_orders_user", Columns: []string{"user_id", "id"}}}
+			},
+			want: []Difference{
+				{Table: "orders", Kind: "index", Name: "idx_orders_user", Detail: "index (user_id, id DESC) missing from the database"},
+				{Table: "orders", Kind: "index", Name: "idx_orders_user", Detail: "index (user_id, id) not in the model"},
+			},
+		},
+		{
+			name: "check allows other values",
+			live: func(tables map[string]*tableDef) {
+				tables["users"].Checks[0].Definition = "CHECK (status = ANY (ARRAY['active'::text]))"
+			},
+			want: []Difference{
+				{Table: "users", Kind: "check", Name: "chk_users_status", Detail: "check on (status) allowing ['active', 'disabled'] missing from the database"},
+				{Table: "users", Kind: "check", Name: "users_status_check", Detail: "check on (status) allowing ['active'] not in the model"},
+			},
+		},
+		{
+			name: "foreign key on delete",
+			live: func(tables map[string]*tableDef) {
+				tables["orders"].ForeignKeys[0].OnDelete = "NO ACTION"
+			},
+			want: []Difference{
+				{Table: "orders", Kind: "foreign key", Name: "fk_users_orders", Detail: "(user_id) references users (id) on delete CASCADE missing from the database"},
+				{Table: "orders", Kind: "foreign key", Name: "orders_user_id_fkey", Detail: "(user_id) references users (id) on delete NO ACTION not in the model"},
+			},
+		},
+		{
+			name: "sorted by table, kind and name",
+			live: func(tables map[string]*tableDef) {
+				tables["users"].Columns["status"] = columnDef{Name: "status", Type: "text", NotNull: true}
+				tables["users"].Columns["email"] = columnDef{Name: "email", Type: "character varying(255)"}
+				tables["orders"].ForeignKeys = nil
+				tables["audit_log"] = &tableDef{Name: "audit_log", Columns: map[string]columnDef{}}
+			},
+			want: []Difference{
+				{Table: "audit_log", Kind: "table", Detail: "not in the models"},
+				{Table: "orders", Kind: "foreign key", Name: "fk_users_orders", Detail: "(user_id) references users (id) on delete CASCADE missing from the database"},
+				{Table: "users", Kind: "column", Name: "email", Detail: "nullable, the model expects NOT NULL"},
+				{Table: "users", Kind: "column", Name: "status", Detail: "NOT NULL, the model expects it nullable"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, live := modelFixture(), liveFixture()
+			if tt.model != nil {
+				tt.model(expected)
+			}
+			if tt.live != nil {
+				tt.live(live)
+			}
+			got := compareTables(expected, live)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compareTables\n got  %#v\n want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffKeys(t *testing.T) {
+	type report struct {
+		key     string
+		name    string
+		missing bool
+	}
+	tests := []struct {
+		name string
+		want map[string]string
+		got  map[string]string
+		out  []report
+	}{
+		{
+			name: "both empty",
+			out:  nil,
+		},
+		{
+			name: "same keys with different names",
+			want: map[string]string{"unique index (email)": "idx_users_email"},
+			got:  map[string]string{"unique index (email)": "users_email_key"},
+			out:  nil,
+		},
+		{
+			name: "only in want",
+			want: map[string]string{"a": "x"},
+			got:  map[string]string{},
+			out:  []report{{key: "a", name: "x", missing: true}},
+		},
+		{
+			name: "only in got",
+			want: nil,
+			got:  map[string]string{"b": "y"},
+			out:  []report{{key: "b", name: "y", missing: false}},
+		},
+		{
+			name: "both sides",
+			want: map[string]string{"a": "x", "b": "y"},
+			got:  map[string]string{"b": "z", "c": "w"},
+			out: []report{
+				{key: "a", name: "x", missing: true},
+				{key: "c", name: "w", missing: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out []report
+			diffKeys(tt.want, tt.got, func(key, name string, missing bool) {
+				out = append(out, report{key: key, name: name, missing: missing})
+			})
+			sort.Slice(out, func(i, j int) bool { return out[i].key < out[j].key })
+			if !reflect.DeepEqual(out, tt.out) {
+				t.Errorf("diffKeys reported %+v, want %+v", out, tt.out)
+			}
+		})
+	}
+}
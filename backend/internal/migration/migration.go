@@ -0,0 +1,199 @@
+// backend/internal/migration/migration.go
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// downSuffix marks the file that reverts a migration: 003_api_keys.sql is
+// rolled back by 003_api_keys.down.sql
+const downSuffix = ".down.sql"
+
+// Migration is one versioned schema change read from NNN_name.sql
+type Migration struct {
+	Version  int64
+	Name     string // file name of the up migration
+	Up       string
+	Down     string // empty when the migration cannot be rolled back
+	Checksum string // SHA-256 of Up
+}
+
+// DownName is the file name of the migration's rollback
+func (m Migration) DownName() string {
+	return strings.TrimSuffix(m.Name, ".sql") + downSuffix
+}
+
+// Applied records a migration in the schema_migrations table
+type Applied struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status pairs a migration with its record, if applied. Migrations applied
+// to the database but no longer on disk have a nil Migration.
+type Status struct {
+	Version   int64
+	Name      string
+	Migration *Migration
+	Applied   *Applied
+}
+
+// Modified reports whether the file changed after it was applied
+func (s Status) Modified() bool {
+	return s.Migration != nil && s.Applied != nil && s.Migration.Checksum != s.Applied.Checksum
+}
+
+// Load reads every migration in fsys, ordered by version
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	downs := make(map[int64]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		version, err := parseVersion(name)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		// Catch unreadable scripts before anything runs
+		if _, err := Split(name, string(content)); err != nil {
+			return nil, err
+		}
+
+		if strings.HasSuffix(name, downSuffix) {
+			if _, dup := downs[version]; dup {
+				return nil, fmt.Errorf("more than one down migration for version %d", version)
+			}
+			downs[version] = string(content)
+			continue
+		}
+		if existing, dup := byVersion[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", existing.Name, name, version)
+		}
+		byVersion[version] = &Migration{
+			Version:  version,
+			Name:     name,
+			Up:       string(content),
+			Checksum: checksum(content),
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, down := range downs {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("down migration for version %d has no up migration", version)
+		}
+		m.Down = down
+	}
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseVersion reads the numeric prefix of a file name such as 002_privacy.sql
+func parseVersion(name string) (int64, error) {
+	prefix, _, found := strings.Cut(name, "_")
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if !found || err != nil || version <= 0 {
+		return 0, fmt.Errorf("migration %s must be named NNN_description.sql", name)
+	}
+	return version, nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// statuses merges the migrations on disk with the applied records
+func statuses(migrations []Migration, applied []Applied) []Status {
+	byVersion := make(map[int64]*Status)
+	for i := range migrations {
+		m := &migrations[i]
+		byVersion[m.Version] = &Status{Version: m.Version, Name: m.Name, Migration: m}
+	}
+	for i := range applied {
+		a := &applied[i]
+		if s, ok := byVersion[a.Version]; ok {
+			s.Applied = a
+			continue
+		}
+		byVersion[a.Version] = &Status{Version: a.Version, Name: a.Name, Applied: a}
+	}
+
+	list := make([]Status, 0, len(byVersion))
+	for _, s := range byVersion {
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list
+}
+
+// checkModified fails when an applied migration changed on disk, since the
+// database no longer matches what the file says
+func checkModified(list []Status) error {
+	var modified []string
+	for _, s := range list {
+		if s.Modified() {
+			modified = append(modified, fmt.Sprintf("%s (applied %s, checksum %.12s, now %.12s)",
+				s.Name, s.Applied.AppliedAt.Format(time.RFC3339), s.Applied.Checksum, s.Migration.Checksum))
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("applied migrations were modified; add a new migration instead: %s", strings.Join(modified, "; "))
+	}
+	return nil
+}
+
+// pending lists the migrations not yet applied, in the order to apply them
+func pending(list []Status) []Migration {
+	var result []Migration
+	for _, s := range list {
+		if s.Migration != nil && s.Applied == nil {
+			result = append(result, *s.Migration)
+		}
+	}
+	return result
+}
+
+// rollbacks lists the applied migrations newer than target, newest first
+func rollbacks(list []Status, target int64) ([]Migration, error) {
+	var result []Migration
+	for i := len(list) - 1; i >= 0; i-- {
+		s := list[i]
+		if s.Version <= target || s.Applied == nil {
+			continue
+		}
+		if s.Migration == nil {
+			return nil, fmt.Errorf("migration %s is applied but missing on disk, cannot roll it back", s.Name)
+		}
+		if s.Migration.Down == "" {
+			return nil, fmt.Errorf("migration %s has no %s file, cannot roll it back", s.Name, s.Migration.DownName())
+		}
+		result = append(result, *s.Migration)
+	}
+	return result, nil
+}
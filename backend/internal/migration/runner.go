@@ -1,39 +1,51 @@
 package migration
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"io/ioutil"
-	"path/filepath"
-	"sort"
-	"strings"
+	"io/fs"
+	"time"
 
 	"github.com/Ayash-Bera/ophelia/backend/internal/database"
 	"github.com/sirupsen/logrus"
 )
 
+// lockKey names the Postgres advisory lock held while migrating, so
+// replicas starting together apply each migration once
+const lockKey int64 = 0x6f7068656c6961 // "ophelia"
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// Runner applies the versioned SQL migrations in a source directory and
+// records them in schema_migrations. Applied migrations never run again;
+// editing one after it was applied is an error.
 type Runner struct {
 	dbManager *database.Manager
+	source    fs.FS
 	logger    *logrus.Logger
 }
 
-func NewRunner(dbManager *database.Manager, logger *logrus.Logger) *Runner {
+func NewRunner(dbManager *database.Manager, source fs.FS, logger *logrus.Logger) *Runner {
 	return &Runner{
 		dbManager: dbManager,
+		source:    source,
 		logger:    logger,
 	}
 }
 
-// RunMigrations executes all pending migrations
-func (r *Runner) RunMigrations(migrationsPath string) error {
+// RunMigrations brings the schema up to date at server start
+func (r *Runner) RunMigrations(ctx context.Context) error {
 	r.logger.Info("Starting database migrations...")
 
-	// First run GORM auto-migrations
-	if err := r.dbManager.Migrate(); err != nil {
-		return fmt.Errorf("GORM auto-migration failed: %w", err)
-	}
-
-	// Then run SQL migrations
-	if err := r.runSQLMigrations(migrationsPath); err != nil {
+	// The SQL migrations are the only source of the schema; the models are
+	// checked against it with "migrate drift", never auto-migrated
+	if _, err := r.Up(ctx); err != nil {
 		return fmt.Errorf("SQL migrations failed: %w", err)
 	}
 
@@ -41,116 +53,215 @@ func (r *Runner) RunMigrations(migrationsPath string) error {
 	return nil
 }
 
-func (r *Runner) runSQLMigrations(migrationsPath string) error {
-	files, err := ioutil.ReadDir(migrationsPath)
+// Status lists every migration on disk or in the database with whether,
+// and when, it was applied
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	db, err := r.dbManager.DB.DB()
 	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+		return nil, err
 	}
+	return r.status(ctx, db)
+}
 
-	var sqlFiles []string
-	for _, file := range files {
-		if strings.HasSuffix(file.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, file.Name())
-		}
+// Pending lists the migrations Up would apply, without applying them
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	list, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	sort.Strings(sqlFiles) // Ensure migrations run in order
-
-	for _, fileName := range sqlFiles {
-		if err := r.runSQLFile(filepath.Join(migrationsPath, fileName)); err != nil {
-			return fmt.Errorf("failed to run migration %s: %w", fileName, err)
-		}
-		r.logger.WithField("file", fileName).Info("Migration executed successfully")
+	if err := checkModified(list); err != nil {
+		return nil, err
 	}
-
-	return nil
+	return pending(list), nil
 }
 
-func (r *Runner) runSQLFile(filePath string) error {
-	content, err := ioutil.ReadFile(filePath)
+// Rollbacks lists the migrations DownTo(target) would revert, newest first,
+// without reverting them
+func (r *Runner) Rollbacks(ctx context.Context, target int64) ([]Migration, error) {
+	list, err := r.Status(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return rollbacks(list, target)
+}
 
-	// For PostgreSQL, we need to handle dollar-quoted strings properly
-	// Simple approach: execute the entire file as one statement if it contains $
-	sqlContent := string(content)
-
-	if strings.Contains(sqlContent, "$") {
-		r.logger.WithField("file", filepath.Base(filePath)).Debug("Executing SQL file with dollar-quoted functions")
+// Up applies the pending migrations in version order, each in its own
+// transaction, and returns the ones applied
+func (r *Runner) Up(ctx context.Context) (applied []Migration, err error) {
+	err = r.withLock(ctx, func(conn *sql.Conn) error {
+		list, err := r.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkModified(list); err != nil {
+			return err
+		}
 
-		// Remove comments but keep the structure intact
-		cleanedSQL := r.removeComments(sqlContent)
+		todo := pending(list)
+		if len(todo) == 0 {
+			r.logger.Info("Database schema is up to date")
+			return nil
+		}
+		for _, m := range todo {
+			started := time.Now()
+			if err := r.apply(ctx, conn, m); err != nil {
+				return fmt.Errorf("failed to run migration %s: %w", m.Name, err)
+			}
+			applied = append(applied, m)
+			r.logger.WithFields(logrus.Fields{
+				"file":        m.Name,
+				"duration_ms": time.Since(started).Milliseconds(),
+			}).Info("Migration applied")
+		}
+		return nil
+	})
+	return applied, err
+}
 
-		if err := r.dbManager.DB.Exec(cleanedSQL).Error; err != nil {
-			return fmt.Errorf("failed to execute %s: %w", filepath.Base(filePath), err)
+// DownTo reverts the applied migrations newer than target, newest first,
+// each in its own transaction. A target of 0 reverts everything.
+func (r *Runner) DownTo(ctx context.Context, target int64) (reverted []Migration, err error) {
+	err = r.withLock(ctx, func(conn *sql.Conn) error {
+		list, err := r.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		todo, err := rollbacks(list, target)
+		if err != nil {
+			return err
+		}
+		for _, m := range todo {
+			if err := r.revert(ctx, conn, m); err != nil {
+				return fmt.Errorf("failed to roll back migration %s: %w", m.Name, err)
+			}
+			reverted = append(reverted, m)
+			r.logger.WithField("file", m.Name).Info("Migration rolled back")
 		}
 		return nil
-	}
+	})
+	return reverted, err
+}
 
-	// For simple SQL files, split by statements
-	statements := r.splitSQLStatements(sqlContent)
+// querier is satisfied by *sql.DB, *sql.Conn and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
 
-	for i, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
-		}
+func (r *Runner) status(ctx context.Context, q querier) ([]Status, error) {
+	migrations, err := Load(r.source)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := readApplied(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return statuses(migrations, applied), nil
+}
 
-		r.logger.WithFields(logrus.Fields{
-			"file":      filepath.Base(filePath),
-			"statement": i + 1,
-		}).Debug("Executing SQL statement")
+// readApplied returns the schema_migrations records, or none before the
+// first migration created the table
+func readApplied(ctx context.Context, q querier) ([]Applied, error) {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to look up schema_migrations: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
 
-		if err := r.dbManager.DB.Exec(stmt).Error; err != nil {
-			return fmt.Errorf("failed to execute statement %d in %s: %w", i+1, filepath.Base(filePath), err)
-		}
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	var applied []Applied
+	for rows.Next() {
+		var a Applied
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+	return applied, rows.Err()
 }
 
-// removeComments removes SQL comments while preserving structure
-func (r *Runner) removeComments(sql string) string {
-	lines := strings.Split(sql, "\n")
-	var result []string
+// withLock runs fn on one connection holding the migration advisory lock,
+// creating schema_migrations first. Other runners wait for the lock.
+func (r *Runner) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	db, err := r.dbManager.DB.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a database connection: %w", err)
+	}
+	defer conn.Close()
 
-	for _, line := range lines {
-		// Remove comment lines but keep empty lines for structure
-		if strings.HasPrefix(strings.TrimSpace(line), "--") {
-			continue
+	r.logger.Debug("Waiting for the migration lock")
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer func() {
+		// The connection goes back to the pool, so the lock must be released
+		// even when ctx is done
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			r.logger.WithError(err).Error("Failed to release the migration lock")
 		}
-		result = append(result, line)
+	}()
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
 	}
+	return fn(conn)
+}
 
-	return strings.Join(result, "\n")
+func (r *Runner) apply(ctx context.Context, conn *sql.Conn, m Migration) error {
+	return r.inTx(ctx, conn, m.Name, m.Up, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+			m.Version, m.Name, m.Checksum)
+		return err
+	})
 }
 
-// splitSQLStatements splits SQL content into individual statements
-func (r *Runner) splitSQLStatements(sql string) []string {
-	// Remove comments and split by semicolon
-	lines := strings.Split(sql, "\n")
-	var cleanedLines []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Skip comment lines and empty lines
-		if line != "" && !strings.HasPrefix(line, "--") {
-			cleanedLines = append(cleanedLines, line)
-		}
+func (r *Runner) revert(ctx context.Context, conn *sql.Conn, m Migration) error {
+	return r.inTx(ctx, conn, m.DownName(), m.Down, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+		return err
+	})
+}
+
+// inTx executes a migration script and its bookkeeping in one transaction
+func (r *Runner) inTx(ctx context.Context, conn *sql.Conn, name, script string, record func(*sql.Tx) error) error {
+	statements, err := Split(name, script)
+	if err != nil {
+		return err
 	}
 
-	// Join back and split by semicolon
-	cleanedSQL := strings.Join(cleanedLines, " ")
-	statements := strings.Split(cleanedSQL, ";")
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, stmt := range statements {
+		r.logger.WithFields(logrus.Fields{
+			"file":      name,
+			"statement": i + 1,
+			"line":      stmt.Line,
+		}).Debug("Executing SQL statement")
 
-	var result []string
-	for _, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt != "" {
-			result = append(result, stmt)
+		if _, err := tx.ExecContext(ctx, stmt.SQL); err != nil {
+			return fmt.Errorf("%s:%d: statement %d: %w", name, stmt.Line, i+1, err)
 		}
 	}
-
-	return result
+	if err := record(tx); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,261 @@
+// backend/internal/migration/split.go
+package migration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Statement is one SQL statement of a migration script
+type Statement struct {
+	SQL  string
+	Line int // line the statement starts on, counting from 1
+}
+
+// SplitError reports a script the splitter cannot read
+type SplitError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *SplitError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Split cuts a script into statements at the semicolons between them. It
+// understands what Postgres lets hide a semicolon: single-quoted strings
+// (with doubled quotes and, in E-strings, backslash escapes), double-quoted
+// identifiers, -- line comments, nested /* */ block comments and
+// dollar-quoted strings such as $$...$$ or $body$...$body$. Statements that
+// are empty or only comments are dropped. file is only used in errors.
+func Split(file, script string) ([]Statement, error) {
+	s := &splitter{file: file, src: script, line: 1}
+	return s.run()
+}
+
+type splitter struct {
+	file string
+	src  string
+	pos  int
+	line int
+
+	statements []Statement
+	start      int  // offset where the current statement began
+	startLine  int  // line of the statement's first token
+	hasContent bool // the statement has more than whitespace and comments
+}
+
+func (s *splitter) run() ([]Statement, error) {
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == ';':
+			s.emit(s.pos)
+			s.pos++
+			s.start = s.pos
+		case c == '-' && s.peek(1) == '-':
+			s.skipLineComment()
+		case c == '/' && s.peek(1) == '*':
+			if err := s.skipBlockComment(); err != nil {
+				return nil, err
+			}
+		case c == '\'':
+			s.mark()
+			if err := s.skipString(s.escapesBackslash()); err != nil {
+				return nil, err
+			}
+		case c == '"':
+			s.mark()
+			if err := s.skipQuoted('"', "quoted identifier"); err != nil {
+				return nil, err
+			}
+		case c == '$':
+			s.mark()
+			if tag, ok := s.dollarTag(); ok {
+				if err := s.skipDollarString(tag); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s.pos++
+		default:
+			if c == '\n' {
+				s.line++
+			} else if !isSpace(c) {
+				s.mark()
+			}
+			s.pos++
+		}
+	}
+	s.emit(len(s.src))
+	return s.statements, nil
+}
+
+func (s *splitter) peek(offset int) byte {
+	if s.pos+offset < len(s.src) {
+		return s.src[s.pos+offset]
+	}
+	return 0
+}
+
+// mark records that the current statement has content starting here
+func (s *splitter) mark() {
+	if !s.hasContent {
+		s.hasContent = true
+		s.startLine = s.line
+	}
+}
+
+// emit ends the current statement at end
+func (s *splitter) emit(end int) {
+	if s.hasContent {
+		s.statements = append(s.statements, Statement{
+			SQL:  strings.TrimSpace(s.src[s.start:end]),
+			Line: s.startLine,
+		})
+	}
+	s.hasContent = false
+}
+
+func (s *splitter) fail(line int, format string, args ...interface{}) error {
+	return &SplitError{File: s.file, Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (s *splitter) skipLineComment() {
+	for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+		s.pos++
+	}
+}
+
+// skipBlockComment skips a /* */ comment, which nests in Postgres
+func (s *splitter) skipBlockComment() error {
+	startLine := s.line
+	depth := 0
+	for s.pos < len(s.src) {
+		switch {
+		case s.src[s.pos] == '/' && s.peek(1) == '*':
+			depth++
+			s.pos += 2
+		case s.src[s.pos] == '*' && s.peek(1) == '/':
+			depth--
+			s.pos += 2
+			if depth == 0 {
+				return nil
+			}
+		default:
+			if s.src[s.pos] == '\n' {
+				s.line++
+			}
+			s.pos++
+		}
+	}
+	return s.fail(startLine, "unterminated block comment")
+}
+
+// escapesBackslash reports whether the quote at pos opens an E-string,
+// in which a backslash escapes the next character
+func (s *splitter) escapesBackslash() bool {
+	if s.pos == 0 || (s.src[s.pos-1] != 'E' && s.src[s.pos-1] != 'e') {
+		return false
+	}
+	return s.pos == 1 || !isIdentChar(s.src[s.pos-2])
+}
+
+// skipString skips a single-quoted string starting at pos
+func (s *splitter) skipString(backslash bool) error {
+	if !backslash {
+		return s.skipQuoted('\'', "string")
+	}
+
+	startLine := s.line
+	s.pos++
+	for s.pos < len(s.src) {
+		switch c := s.src[s.pos]; {
+		case c == '\\':
+			if s.peek(1) == '\n' {
+				s.line++
+			}
+			s.pos += 2
+		case c == '\'' && s.peek(1) == '\'':
+			s.pos += 2
+		case c == '\'':
+			s.pos++
+			return nil
+		default:
+			if c == '\n' {
+				s.line++
+			}
+			s.pos++
+		}
+	}
+	return s.fail(startLine, "unterminated string")
+}
+
+// skipQuoted skips text between two quote characters, where a doubled
+// quote stands for itself
+func (s *splitter) skipQuoted(quote byte, what string) error {
+	startLine := s.line
+	s.pos++
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		if c == quote {
+			if s.peek(1) == quote {
+				s.pos += 2
+				continue
+			}
+			s.pos++
+			return nil
+		}
+		if c == '\n' {
+			s.line++
+		}
+		s.pos++
+	}
+	return s.fail(startLine, "unterminated %s", what)
+}
+
+// dollarTag reads the $tag$ opening a dollar-quoted string at pos. A $ that
+// follows an identifier or starts a parameter such as $1 opens nothing.
+func (s *splitter) dollarTag() (string, bool) {
+	if s.pos > 0 && isIdentChar(s.src[s.pos-1]) {
+		return "", false
+	}
+	end := s.pos + 1
+	for end < len(s.src) && s.src[end] != '$' {
+		c := s.src[end]
+		if !isIdentChar(c) || (end == s.pos+1 && isDigit(c)) {
+			return "", false
+		}
+		end++
+	}
+	if end >= len(s.src) {
+		return "", false
+	}
+	return s.src[s.pos : end+1], true
+}
+
+// skipDollarString skips a dollar-quoted string whose opening tag is at pos
+func (s *splitter) skipDollarString(tag string) error {
+	startLine := s.line
+	s.pos += len(tag)
+	closing := strings.Index(s.src[s.pos:], tag)
+	if closing < 0 {
+		return s.fail(startLine, "unterminated dollar-quoted string %s", tag)
+	}
+	s.line += strings.Count(s.src[s.pos:s.pos+closing], "\n")
+	s.pos += closing + len(tag)
+	return nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
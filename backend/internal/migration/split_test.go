@@ -0,0 +1,217 @@
+// backend/internal/migration/split_test.go
+package migration
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []Statement
+	}{
+		{
+			name:   "empty",
+			script: "",
+			want:   nil,
+		},
+		{
+			name:   "single statement without semicolon",
+			script: "SELECT 1",
+			want:   []Statement{{SQL: "SELECT 1", Line: 1}},
+		},
+		{
+			name:   "statements on separate lines",
+			script: "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n",
+			want: []Statement{
+				{SQL: "CREATE TABLE a (id INT)", Line: 1},
+				{SQL: "CREATE TABLE b (id INT)", Line: 3},
+			},
+		},
+		{
+			name:   "empty statements dropped",
+			script: ";;SELECT 1;; ;",
+			want:   []Statement{{SQL: "SELECT 1", Line: 1}},
+		},
+		{
+			name:   "semicolon in string",
+			script: "INSERT INTO t VALUES ('a;b');SELECT 2",
+			want: []Statement{
+				{SQL: "INSERT INTO t VALUES ('a;b')", Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "doubled quote in string",
+			script: "SELECT 'it''s; fine';SELECT 2",
+			want: []Statement{
+				{SQL: "SELECT 'it''s; fine'", Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "backslash escape in E string",
+			script: `SELECT E'a\'; b';SELECT 2`,
+			want: []Statement{
+				{SQL: `SELECT E'a\'; b'`, Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "backslash is literal in plain string",
+			script: `SELECT 'C:\';SELECT 2`,
+			want: []Statement{
+				{SQL: `SELECT 'C:\'`, Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "semicolon in quoted identifier",
+			script: `CREATE TABLE "odd;name" ("a""b" INT);SELECT 2`,
+			want: []Statement{
+				{SQL: `CREATE TABLE "odd;name" ("a""b" INT)`, Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "semicolon in line comment",
+			script: "SELECT 1 -- not here; really\n;SELECT 2",
+			want: []Statement{
+				{SQL: "SELECT 1 -- not here; really", Line: 1},
+				{SQL: "SELECT 2", Line: 2},
+			},
+		},
+		{
+			name:   "comment only statements dropped",
+			script: "-- header\n-- Migration: 001.sql\n\n/* block */\nSELECT 1;\n-- trailing comment\n",
+			want:   []Statement{{SQL: "-- header\n-- Migration: 001.sql\n\n/* block */\nSELECT 1", Line: 5}},
+		},
+		{
+			name:   "nested block comment",
+			script: "/* outer /* inner; */ still; comment */ SELECT 1;SELECT 2",
+			want: []Statement{
+				{SQL: "/* outer /* inner; */ still; comment */ SELECT 1", Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "dollar quoted function body",
+			script: "CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.updated_at = NOW();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\nSELECT 2;",
+			want: []Statement{
+				{SQL: "CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.updated_at = NOW();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql", Line: 1},
+				{SQL: "SELECT 2", Line: 7},
+			},
+		},
+		{
+			name:   "tagged dollar quote containing $$",
+			script: "DO $body$ BEGIN PERFORM $$;$$; END $body$;SELECT 2",
+			want: []Statement{
+				{SQL: "DO $body$ BEGIN PERFORM $$;$$; END $body$", Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "positional parameters are not dollar quotes",
+			script: "PREPARE p AS SELECT $1, $2;SELECT 2",
+			want: []Statement{
+				{SQL: "PREPARE p AS SELECT $1, $2", Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "dollar inside identifier",
+			script: "SELECT a$b$c FROM t;SELECT 2",
+			want: []Statement{
+				{SQL: "SELECT a$b$c FROM t", Line: 1},
+				{SQL: "SELECT 2", Line: 1},
+			},
+		},
+		{
+			name:   "dollar in regular expression string",
+			script: "UPDATE t SET a = NULL WHERE a ~ '^[0-9]+$';\nSELECT 2",
+			want: []Statement{
+				{SQL: "UPDATE t SET a = NULL WHERE a ~ '^[0-9]+$'", Line: 1},
+				{SQL: "SELECT 2", Line: 2},
+			},
+		},
+		{
+			name:   "lines counted through multi-line strings",
+			script: "SELECT 'a\nb';\nSELECT 2",
+			want: []Statement{
+				{SQL: "SELECT 'a\nb'", Line: 1},
+				{SQL: "SELECT 2", Line: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split("test.sql", tt.script)
+			if err != nil {
+				t.Fatalf("Split returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q)\n got  %#v\n want %#v", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		line   int
+		want   string
+	}{
+		{
+			name:   "unterminated string",
+			script: "SELECT 1;\nSELECT 'abc;\n",
+			line:   2,
+			want:   "test.sql:2: unterminated string",
+		},
+		{
+			name:   "unterminated E string ending in backslash",
+			script: `SELECT E'abc\'`,
+			line:   1,
+			want:   "test.sql:1: unterminated string",
+		},
+		{
+			name:   "unterminated quoted identifier",
+			script: "\n\nSELECT \"abc",
+			line:   3,
+			want:   "test.sql:3: unterminated quoted identifier",
+		},
+		{
+			name:   "unterminated block comment",
+			script: "SELECT 1;\n/* open /* nested */\nSELECT 2;",
+			line:   2,
+			want:   "test.sql:2: unterminated block comment",
+		},
+		{
+			name:   "unterminated dollar quote",
+			script: "SELECT 1;\n\nDO $fn$ BEGIN END $$;",
+			line:   3,
+			want:   "test.sql:3: unterminated dollar-quoted string $fn$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Split("test.sql", tt.script)
+			var splitErr *SplitError
+			if !errors.As(err, &splitErr) {
+				t.Fatalf("Split returned %v, want a *SplitError", err)
+			}
+			if splitErr.File != "test.sql" || splitErr.Line != tt.line {
+				t.Errorf("error at %s:%d, want test.sql:%d", splitErr.File, splitErr.Line, tt.line)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("error %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
@@ -1,22 +1,106 @@
 package models
 
+import "time"
+
 type SearchRequest struct {
 	Query string `json:"query" binding:"required"`
+	Limit int    `json:"limit,omitempty"` // maximum results, 1-10 (default 10)
+
+	Summarize bool `json:"summarize,omitempty"` // include a solution summary
 }
 
 type SearchResponse struct {
-	Results      []SearchResult `json:"results"`
-	Total        int            `json:"total"`
-	ResponseTime int            `json:"response_time_ms"`
+	Results      []SearchResult   `json:"results"`
+	Total        int              `json:"total"`
+	ResponseTime int              `json:"response_time_ms"`
+	ComputedAt   time.Time        `json:"computed_at"`
+	Freshness    string           `json:"freshness"` // live, fresh or stale
+	Summary      *SolutionSummary `json:"summary,omitempty"`
+}
+
+// BatchSearchRequest runs several searches in one call
+type BatchSearchRequest struct {
+	Queries []BatchQuery `json:"queries" binding:"required"`
+}
+
+type BatchQuery struct {
+	ID        string `json:"id,omitempty"` // echoed back to match results to queries
+	Query     string `json:"query"`
+	Limit     int    `json:"limit,omitempty"`
+	Summarize bool   `json:"summarize,omitempty"`
+}
+
+type BatchSearchResponse struct {
+	Items        []BatchItem `json:"items"` // in request order
+	Succeeded    int         `json:"succeeded"`
+	Failed       int         `json:"failed"`
+	ResponseTime int         `json:"response_time_ms"`
+}
+
+// BatchItem is the outcome of one query; Error is set when it failed
+type BatchItem struct {
+	ID         string           `json:"id,omitempty"`
+	Query      string           `json:"query"`
+	Results    []SearchResult   `json:"results"`
+	Total      int              `json:"total"`
+	ComputedAt *time.Time       `json:"computed_at,omitempty"`
+	Freshness  string           `json:"freshness,omitempty"`
+	Summary    *SolutionSummary `json:"summary,omitempty"`
+	Error      string           `json:"error,omitempty"`
 }
 
 type SearchResult struct {
-	ContextID   string  `json:"context_id"`
-	Title       string  `json:"title"`
-	Content     string  `json:"content"`
-	URL         string  `json:"url"`
-	Score       float64 `json:"score"`
-	Relevance   string  `json:"relevance"`
+	ContextID   string           `json:"context_id"`
+	Title       string           `json:"title"`
+	Content     string           `json:"content"`
+	URL         string           `json:"url"`
+	Page        string           `json:"page,omitempty"` // wiki page title the result came from
+	Score       float64          `json:"score"`
+	Relevance   string           `json:"relevance"`
+	Warnings    []CommandWarning `json:"warnings,omitempty"` // risky commands in Content
+}
+
+// CommandWarning flags a command that can damage the system if copied
+// blindly, with the wiki's own Warning box when one sits next to it
+type CommandWarning struct {
+	Command     string   `json:"command"`
+	Risk        string   `json:"risk"` // read_only, modifies_system, destructive or data_loss
+	Reasons     []string `json:"reasons,omitempty"`
+	WikiWarning string   `json:"wiki_warning,omitempty"`
+}
+
+// SolutionSummary is an extractive digest of the top results: steps with
+// their commands, files to look at and supporting sentences. Every item
+// cites an entry in Sources.
+type SolutionSummary struct {
+	Steps     []SolutionStep     `json:"steps"`
+	Files     []SolutionFile     `json:"files"`
+	Sentences []SolutionSentence `json:"sentences"`
+	Sources   []SolutionSource   `json:"sources"`
+}
+
+type SolutionStep struct {
+	Text     string           `json:"text"` // sentence introducing the commands, may be empty
+	Commands []string         `json:"commands"`
+	Risk     string           `json:"risk"` // highest risk among Commands
+	Warnings []CommandWarning `json:"warnings,omitempty"`
+	Source   int              `json:"source"` // index into Sources
+}
+
+type SolutionFile struct {
+	Path   string `json:"path"`
+	Source int    `json:"source"`
+}
+
+type SolutionSentence struct {
+	Text   string `json:"text"`
+	Source int    `json:"source"`
+}
+
+type SolutionSource struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Page  string `json:"page,omitempty"`
 }
 
 type FeedbackRequest struct {
@@ -30,4 +114,26 @@ type HealthResponse struct {
 	Service   string            `json:"service"`
 	Timestamp string            `json:"timestamp"`
 	Services  map[string]string `json:"services"`
-}
\ No newline at end of file
+}
+// IndexedPage is a wiki page as listed by the admin content API
+type IndexedPage struct {
+	ID           uint       `json:"id"`
+	Title        string     `json:"title"`
+	URL          string     `json:"url"`
+	CrawlStatus  string     `json:"crawl_status"`
+	ContentHash  string     `json:"content_hash"`
+	IsActive     bool       `json:"is_active"`
+	WordCount    int        `json:"word_count"`
+	SectionCount int        `json:"section_count"`
+	LastCrawled  *time.Time `json:"last_crawled"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IndexedSection is one stored section of an indexed page
+type IndexedSection struct {
+	ID            uint     `json:"id"`
+	Title         string   `json:"title"`
+	Order         int      `json:"order"`
+	Length        int      `json:"length"` // characters of section content
+	ErrorPatterns []string `json:"error_patterns"`
+}
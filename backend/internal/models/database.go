@@ -3,6 +3,7 @@ package models
 // GORM models
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"strings"
@@ -59,25 +60,27 @@ type BaseModel struct {
 type SearchQuery struct {
 	BaseModel
 	QueryText       string    `json:"query_text" gorm:"not null"`
-	UserSession     string    `json:"user_session"`
+	UserSession     string    `json:"user_session" gorm:"index:idx_search_queries_session"`
 	ResultsCount    int       `json:"results_count" gorm:"default:0"`
 	ClickedResultID *string   `json:"clicked_result_id"`
-	SearchTimestamp time.Time `json:"search_timestamp" gorm:"default:NOW()"`
+	SearchTimestamp time.Time `json:"search_timestamp" gorm:"default:NOW();index:idx_search_queries_timestamp"`
 	ResponseTimeMs  int       `json:"response_time_ms"`
 	UserAgent       string    `json:"user_agent"`
-	IPAddress       string    `json:"ip_address" gorm:"type:inet"`
+	IPAddress       string    `json:"ip_address" gorm:"type:text"` // raw, truncated or hashed depending on privacy.ip_mode
 
 	// Associations
-	Feedback []UserFeedback `json:"feedback" gorm:"foreignKey:QueryID"`
+	Feedback []UserFeedback `json:"feedback" gorm:"foreignKey:QueryID;constraint:OnDelete:CASCADE"`
 }
 
 // UserFeedback represents user feedback on search results
 type UserFeedback struct {
-	BaseModel
-	QueryID      uint   `json:"query_id" gorm:"not null"`
-	FeedbackType string `json:"feedback_type" gorm:"not null;check:feedback_type IN ('helpful','not_helpful','partially_helpful')"`
-	FeedbackText string `json:"feedback_text"`
-	UserSession  string `json:"user_session"`
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	QueryID      uint      `json:"query_id" gorm:"not null;index:idx_user_feedback_query"`
+	FeedbackType string    `json:"feedback_type" gorm:"not null;index:idx_user_feedback_type;check:feedback_type IN ('helpful','not_helpful','partially_helpful')"`
+	FeedbackText string    `json:"feedback_text"`
+	UserSession  string    `json:"user_session" gorm:"index:idx_user_feedback_session"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index:idx_user_feedback_created"` // retention deletes by age
+	UpdatedAt    time.Time `json:"updated_at"`
 
 	// Associations
 	Query SearchQuery `json:"query" gorm:"foreignKey:QueryID"`
@@ -86,27 +89,27 @@ type UserFeedback struct {
 // ContentMetadata represents cached wiki page metadata
 type ContentMetadata struct {
 	BaseModel
-	WikiPageTitle      string      `json:"wiki_page_title" gorm:"unique;not null"`
+	WikiPageTitle      string      `json:"wiki_page_title" gorm:"unique;not null;index:idx_content_metadata_title"`
 	AlchemystContextID *string     `json:"alchemyst_context_id"`
 	ErrorPatterns      StringArray `json:"error_patterns" gorm:"type:text[]"`
 	ContentHash        string      `json:"content_hash"`
 	PageURL            string      `json:"page_url"`
 	ContentType        string      `json:"content_type" gorm:"default:'wiki_page'"`
 	LastCrawled        *time.Time  `json:"last_crawled"`
-	LastUpdated        time.Time   `json:"last_updated" gorm:"default:NOW()"`
-	IsActive           bool        `json:"is_active" gorm:"default:true"`
-	CrawlStatus        string      `json:"crawl_status" gorm:"default:'pending';check:crawl_status IN ('pending','crawling','completed','failed')"`
+	LastUpdated        time.Time   `json:"last_updated" gorm:"default:NOW();index:idx_content_metadata_updated"`
+	IsActive           bool        `json:"is_active" gorm:"default:true;index:idx_content_metadata_active"`
+	CrawlStatus        string      `json:"crawl_status" gorm:"default:'pending';index:idx_content_metadata_status;check:crawl_status IN ('pending','crawling','completed','failed')"`
 	WordCount          int         `json:"word_count"`
 	SectionCount       int         `json:"section_count"`
 
 	// Associations
-	Sections []WikiSection `json:"sections" gorm:"foreignKey:ContentMetadataID"`
+	Sections []WikiSection `json:"sections" gorm:"foreignKey:ContentMetadataID;constraint:OnDelete:CASCADE"`
 }
 
 // WikiSection represents individual sections of wiki pages
 type WikiSection struct {
 	BaseModel
-	ContentMetadataID  uint        `json:"content_metadata_id" gorm:"not null"`
+	ContentMetadataID  uint        `json:"content_metadata_id" gorm:"not null;index:idx_wiki_sections_metadata"`
 	SectionTitle       string      `json:"section_title" gorm:"not null"`
 	SectionContent     string      `json:"section_content" gorm:"not null"`
 	SectionOrder       int         `json:"section_order" gorm:"not null"`
@@ -120,7 +123,7 @@ type WikiSection struct {
 // SearchAnalytics represents hourly search performance metrics
 type SearchAnalytics struct {
 	ID                 uint      `json:"id" gorm:"primaryKey"`
-	DateHour           time.Time `json:"date_hour" gorm:"unique;not null"`
+	DateHour           time.Time `json:"date_hour" gorm:"unique;not null;index:idx_search_analytics_date"`
 	TotalSearches      int       `json:"total_searches" gorm:"default:0"`
 	AvgResponseTimeMs  int       `json:"avg_response_time_ms" gorm:"default:0"`
 	SuccessfulSearches int       `json:"successful_searches" gorm:"default:0"`
@@ -133,7 +136,7 @@ type SearchAnalytics struct {
 type PopularQuery struct {
 	BaseModel
 	QueryText         string    `json:"query_text" gorm:"unique;not null"`
-	SearchCount       int       `json:"search_count" gorm:"default:1"`
+	SearchCount       int       `json:"search_count" gorm:"default:1;index:idx_popular_queries_count,sort:desc"`
 	AvgResultsCount   float64   `json:"avg_results_count" gorm:"type:decimal(5,2);default:0"`
 	AvgResponseTimeMs int       `json:"avg_response_time_ms" gorm:"default:0"`
 	LastSearched      time.Time `json:"last_searched" gorm:"default:NOW()"`
@@ -142,50 +145,140 @@ type PopularQuery struct {
 // SystemHealth represents service health monitoring
 type SystemHealth struct {
 	ID             uint      `json:"id" gorm:"primaryKey"`
-	ServiceName    string    `json:"service_name" gorm:"not null"`
+	ServiceName    string    `json:"service_name" gorm:"not null;index:idx_system_health_service"`
 	Status         string    `json:"status" gorm:"not null;check:status IN ('healthy','degraded','unhealthy')"`
 	ResponseTimeMs int       `json:"response_time_ms"`
 	ErrorMessage   string    `json:"error_message"`
 	CheckedAt      time.Time `json:"checked_at" gorm:"default:NOW()"`
 }
 
-// Database interfaces for repository pattern
+// API key scopes
+const (
+	ScopeSearch    = "search"
+	ScopeFeedback  = "feedback"
+	ScopeAdmin     = "admin"
+	ScopeAnalytics = "analytics"
+)
+
+// ValidScopes lists every scope a key may be given
+var ValidScopes = []string{ScopeSearch, ScopeFeedback, ScopeAdmin, ScopeAnalytics}
+
+// APIKey is an issued API key. Only a hash of the secret is stored.
+type APIKey struct {
+	BaseModel
+	Name          string      `json:"name" gorm:"unique;not null"`
+	Owner         string      `json:"owner"`
+	Prefix        string      `json:"prefix" gorm:"not null"`   // first characters of the secret, for recognising a key
+	KeyHash       string      `json:"-" gorm:"unique;not null"` // hex SHA-256 of the secret
+	Scopes        StringArray `json:"scopes" gorm:"type:text[];not null"`
+	RatePerMinute int         `json:"rate_per_minute" gorm:"default:0"` // 0 means unlimited
+	DailyQuota    int         `json:"daily_quota" gorm:"default:0"`     // quota units per UTC day, 0 means unlimited
+	LastUsedAt    *time.Time  `json:"last_used_at"`
+	RevokedAt     *time.Time  `json:"revoked_at"`
+}
+
+// APIKeyUsage counts one key's traffic on one route for one UTC day
+type APIKeyUsage struct {
+	BaseModel
+	APIKeyID uint      `json:"api_key_id" gorm:"not null;uniqueIndex:idx_api_key_usage_key_day_route"`
+	Day      time.Time `json:"day" gorm:"type:date;not null;uniqueIndex:idx_api_key_usage_key_day_route;index:idx_api_key_usage_day"`
+	Route    string    `json:"route" gorm:"not null;uniqueIndex:idx_api_key_usage_key_day_route"`
+	Requests int       `json:"requests" gorm:"default:0"`
+	Units    int       `json:"units" gorm:"default:0"`  // quota units, e.g. one per query in a batch
+	Errors   int       `json:"errors" gorm:"default:0"` // responses with status 400 or above
+
+	// Associations
+	APIKey APIKey `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// APIKeyUsageDelta is usage accumulated in memory before it is written
+type APIKeyUsageDelta struct {
+	APIKeyID uint
+	Day      time.Time
+	Route    string
+	Requests int
+	Units    int
+	Errors   int
+}
+
+// Tables lists every model backed by a table, referenced tables first. The
+// SQL migrations create the tables; the drift check compares the database
+// against these models.
+var Tables = []interface{}{
+	&SearchQuery{},
+	&UserFeedback{},
+	&ContentMetadata{},
+	&WikiSection{},
+	&SearchAnalytics{},
+	&PopularQuery{},
+	&SystemHealth{},
+	&APIKey{},
+	&APIKeyUsage{},
+}
+
+// Database interfaces for repository pattern.
+// Write methods take a context so they join the caller's trace.
 type SearchQueryRepository interface {
-	Create(query *SearchQuery) error
+	Create(ctx context.Context, query *SearchQuery) error
 	GetByID(id uint) (*SearchQuery, error)
 	GetBySession(session string) ([]SearchQuery, error)
 	GetRecentSearches(limit int) ([]SearchQuery, error)
-	UpdateClickedResult(id uint, resultID string) error
+	UpdateClickedResult(ctx context.Context, id uint, resultID string) error
 	GetSearchAnalytics(from, to time.Time) ([]SearchAnalytics, error)
+	DeleteBySession(ctx context.Context, session string) (int64, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+	AggregateOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
 }
 
 type ContentMetadataRepository interface {
-	Create(content *ContentMetadata) error
+	Create(ctx context.Context, content *ContentMetadata) error
 	GetByID(id uint) (*ContentMetadata, error)
 	GetByTitle(title string) (*ContentMetadata, error)
 	GetAll() ([]ContentMetadata, error)
 	GetActive() ([]ContentMetadata, error)
-	Update(content *ContentMetadata) error
-	UpdateCrawlStatus(id uint, status string) error
+	GetActiveOutlines() ([]ContentMetadata, error)
+	List() ([]ContentMetadata, error)
+	Update(ctx context.Context, content *ContentMetadata) error
+	UpdateCrawlStatus(ctx context.Context, id uint, status string) error
+	SetActive(ctx context.Context, id uint, active bool) error
 	GetByCrawlStatus(status string) ([]ContentMetadata, error)
-	Delete(id uint) error
+	Delete(ctx context.Context, id uint) error
+	ReplaceSections(ctx context.Context, contentID uint, sections []WikiSection) error
+	GetSections(contentID uint) ([]WikiSection, error)
 }
 
 type UserFeedbackRepository interface {
-	Create(feedback *UserFeedback) error
+	Create(ctx context.Context, feedback *UserFeedback) error
 	GetByQueryID(queryID uint) ([]UserFeedback, error)
 	GetByType(feedbackType string) ([]UserFeedback, error)
 	GetRecentFeedback(limit int) ([]UserFeedback, error)
+	DeleteBySession(ctx context.Context, session string) (int64, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
 }
 
 type PopularQueryRepository interface {
-	IncrementCount(queryText string) error
+	IncrementCount(ctx context.Context, queryText string) error
 	GetTop(limit int) ([]PopularQuery, error)
-	UpdateStats(queryText string, resultsCount float64, responseTime int) error
+	UpdateStats(ctx context.Context, queryText string, resultsCount float64, responseTime int) error
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByHash(hash string) (*APIKey, error)
+	GetByName(name string) (*APIKey, error)
+	GetAll() ([]APIKey, error)
+	Revoke(ctx context.Context, id uint) error
+	TouchLastUsed(ctx context.Context, ids []uint, at time.Time) error
+}
+
+type APIKeyUsageRepository interface {
+	Add(ctx context.Context, deltas []APIKeyUsageDelta) error
+	GetByKey(keyID uint, from, to time.Time) ([]APIKeyUsage, error)
+	GetAll(from, to time.Time) ([]APIKeyUsage, error)
 }
 
 type SystemHealthRepository interface {
-	UpdateServiceHealth(serviceName, status string, responseTime int, errorMsg string) error
+	UpdateServiceHealth(ctx context.Context, serviceName, status string, responseTime int, errorMsg string) error
 	GetServiceHealth(serviceName string) (*SystemHealth, error)
 	GetAllServicesHealth() ([]SystemHealth, error)
 	GetUnhealthyServices() ([]SystemHealth, error)
@@ -199,6 +292,8 @@ func (WikiSection) TableName() string     { return "wiki_sections" }
 func (SearchAnalytics) TableName() string { return "search_analytics" }
 func (PopularQuery) TableName() string    { return "popular_queries" }
 func (SystemHealth) TableName() string    { return "system_health" }
+func (APIKey) TableName() string          { return "api_keys" }
+func (APIKeyUsage) TableName() string     { return "api_key_usage" }
 
 // Model validation methods
 func (sq *SearchQuery) Validate() error {
@@ -242,6 +337,44 @@ func (cm *ContentMetadata) Validate() error {
 	return nil
 }
 
+func (k *APIKey) Validate() error {
+	if k.Name == "" {
+		return fmt.Errorf("key name is required")
+	}
+	if k.KeyHash == "" {
+		return fmt.Errorf("key hash is required")
+	}
+	for _, scope := range k.Scopes {
+		if !IsValidScope(scope) {
+			return fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+	if k.RatePerMinute < 0 || k.DailyQuota < 0 {
+		return fmt.Errorf("limits cannot be negative")
+	}
+	return nil
+}
+
+// IsValidScope reports whether scope is one of ValidScopes
+func IsValidScope(scope string) bool {
+	for _, valid := range ValidScopes {
+		if scope == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // GORM hooks
 func (sq *SearchQuery) BeforeCreate(tx *gorm.DB) error {
 	return sq.Validate()
@@ -258,3 +391,7 @@ func (cm *ContentMetadata) BeforeCreate(tx *gorm.DB) error {
 func (cm *ContentMetadata) BeforeUpdate(tx *gorm.DB) error {
 	return cm.Validate()
 }
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	return k.Validate()
+}
@@ -0,0 +1,109 @@
+// backend/internal/privacy/anonymizer.go
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// IP handling modes applied before search analytics are stored
+const (
+	IPModeNone     = "none"     // store the address unchanged
+	IPModeTruncate = "truncate" // zero the host part (/24 for IPv4, /48 for IPv6)
+	IPModeHash     = "hash"     // store a salted HMAC of the address
+	IPModeDrop     = "drop"     // do not store the address at all
+)
+
+// Config controls what personal data is kept when analytics are written
+type Config struct {
+	IPMode         string
+	IPHashSalt     string
+	StoreUserAgent bool
+	ScrubText      bool // replace usernames and hostnames in stored query and feedback text
+}
+
+// Anonymizer applies the configured privacy rules to data before storage
+type Anonymizer struct {
+	config Config
+}
+
+var (
+	// /home/alice/... and /Users/alice/...
+	homeDirPattern = regexp.MustCompile(`(/home/|/Users/)[A-Za-z0-9._-]+`)
+	// alice@workstation, ssh://alice@host.example.com
+	userAtHostPattern = regexp.MustCompile(`\b[A-Za-z0-9._-]+@[A-Za-z0-9][A-Za-z0-9.-]*[A-Za-z0-9]\b`)
+	// Shell prompts such as [alice@archbox ~]$ are covered by userAtHostPattern;
+	// syslog/journal prefixes carry the hostname after the timestamp:
+	// "Jan 02 15:04:05 archbox kernel: ..."
+	syslogHostPattern = regexp.MustCompile(`(?m)^([A-Z][a-z]{2} +\d{1,2} \d{2}:\d{2}:\d{2}) +[A-Za-z0-9._-]+ `)
+	// ISO timestamps used by journalctl -o short-iso
+	isoHostPattern = regexp.MustCompile(`(?m)^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}[^ ]*) +[A-Za-z0-9._-]+ `)
+)
+
+func NewAnonymizer(config Config) *Anonymizer {
+	config.IPMode = strings.ToLower(strings.TrimSpace(config.IPMode))
+	if config.IPMode == "" {
+		config.IPMode = IPModeNone
+	}
+	return &Anonymizer{config: config}
+}
+
+// IP returns the address to store for the configured mode. An empty string
+// means nothing should be stored.
+func (a *Anonymizer) IP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+
+	switch a.config.IPMode {
+	case IPModeNone:
+		return ip
+	case IPModeTruncate:
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return ""
+		}
+		if v4 := parsed.To4(); v4 != nil {
+			return v4.Mask(net.CIDRMask(24, 32)).String()
+		}
+		return parsed.Mask(net.CIDRMask(48, 128)).String()
+	case IPModeHash:
+		mac := hmac.New(sha256.New, []byte(a.config.IPHashSalt))
+		mac.Write([]byte(ip))
+		return hex.EncodeToString(mac.Sum(nil))[:32]
+	default:
+		return ""
+	}
+}
+
+// UserAgent returns the user agent to store, or an empty string when user
+// agents are not kept
+func (a *Anonymizer) UserAgent(userAgent string) string {
+	if !a.config.StoreUserAgent {
+		return ""
+	}
+	return userAgent
+}
+
+// Text scrubs usernames and hostnames from pasted logs when scrubbing is
+// enabled; otherwise the text is returned unchanged
+func (a *Anonymizer) Text(text string) string {
+	if !a.config.ScrubText {
+		return text
+	}
+	return ScrubText(text)
+}
+
+// ScrubText replaces usernames and hostnames commonly found in pasted logs
+// with placeholders
+func ScrubText(text string) string {
+	text = syslogHostPattern.ReplaceAllString(text, "$1 <host> ")
+	text = isoHostPattern.ReplaceAllString(text, "$1 <host> ")
+	text = userAtHostPattern.ReplaceAllString(text, "<user>@<host>")
+	text = homeDirPattern.ReplaceAllString(text, "$1<user>")
+	return text
+}
@@ -0,0 +1,105 @@
+// backend/internal/privacy/retention.go
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// Retention modes for search_queries rows past their retention period
+const (
+	RetentionAggregate = "aggregate" // roll up into search_analytics, then delete
+	RetentionPurge     = "purge"     // delete without keeping aggregates
+)
+
+// RetentionConfig controls how long raw analytics rows are kept. A zero
+// number of days keeps rows forever.
+type RetentionConfig struct {
+	SearchQueryDays int
+	FeedbackDays    int
+	Mode            string
+	Interval        time.Duration
+}
+
+// RetentionJob periodically removes old search_queries and user_feedback rows
+type RetentionJob struct {
+	repoManager *repository.RepositoryManager
+	config      RetentionConfig
+	logger      *logrus.Logger
+}
+
+func NewRetentionJob(repoManager *repository.RepositoryManager, config RetentionConfig, logger *logrus.Logger) *RetentionJob {
+	if config.Interval <= 0 {
+		config.Interval = 6 * time.Hour
+	}
+	if config.Mode == "" {
+		config.Mode = RetentionAggregate
+	}
+	return &RetentionJob{
+		repoManager: repoManager,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// Start runs the job immediately and then on every interval until ctx is done
+func (j *RetentionJob) Start(ctx context.Context) {
+	if j.config.SearchQueryDays <= 0 && j.config.FeedbackDays <= 0 {
+		j.logger.Info("Data retention disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			j.logger.WithError(err).Error("Data retention run failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies the retention policy once
+func (j *RetentionJob) RunOnce(ctx context.Context) error {
+	now := time.Now()
+	fields := logrus.Fields{"mode": j.config.Mode}
+
+	if j.config.FeedbackDays > 0 {
+		cutoff := now.AddDate(0, 0, -j.config.FeedbackDays)
+		deleted, err := j.repoManager.UserFeedback.DeleteOlderThan(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		fields["feedback_deleted"] = deleted
+	}
+
+	if j.config.SearchQueryDays > 0 {
+		cutoff := now.AddDate(0, 0, -j.config.SearchQueryDays)
+
+		var (
+			deleted int64
+			err     error
+		)
+		if j.config.Mode == RetentionPurge {
+			deleted, err = j.repoManager.SearchQuery.DeleteOlderThan(ctx, cutoff)
+		} else {
+			deleted, err = j.repoManager.SearchQuery.AggregateOlderThan(ctx, cutoff)
+		}
+		if err != nil {
+			return err
+		}
+		fields["search_queries_deleted"] = deleted
+	}
+
+	j.logger.WithFields(fields).Info("Data retention run completed")
+	return nil
+}
@@ -1,12 +1,26 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
+// traceWrite opens a span around a repository write and binds the GORM
+// session to it so the statement runs under the caller's context
+func traceWrite(ctx context.Context, db *gorm.DB, operation, table string) (*gorm.DB, trace.Span) {
+	ctx, span := tracing.Start(ctx, "repository."+operation,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.collection.name", table),
+	)
+	return db.WithContext(ctx), span
+}
+
 // SearchQueryRepositoryImpl implements SearchQueryRepository
 type SearchQueryRepositoryImpl struct {
 	db *gorm.DB
@@ -16,8 +30,11 @@ func NewSearchQueryRepository(db *gorm.DB) models.SearchQueryRepository {
 	return &SearchQueryRepositoryImpl{db: db}
 }
 
-func (r *SearchQueryRepositoryImpl) Create(query *models.SearchQuery) error {
-	return r.db.Create(query).Error
+func (r *SearchQueryRepositoryImpl) Create(ctx context.Context, query *models.SearchQuery) error {
+	tx, span := traceWrite(ctx, r.db, "SearchQuery.Create", "search_queries")
+	err := tx.Create(query).Error
+	tracing.End(span, err)
+	return err
 }
 
 func (r *SearchQueryRepositoryImpl) GetByID(id uint) (*models.SearchQuery, error) {
@@ -45,10 +62,13 @@ func (r *SearchQueryRepositoryImpl) GetRecentSearches(limit int) ([]models.Searc
 	return queries, err
 }
 
-func (r *SearchQueryRepositoryImpl) UpdateClickedResult(id uint, resultID string) error {
-	return r.db.Model(&models.SearchQuery{}).
+func (r *SearchQueryRepositoryImpl) UpdateClickedResult(ctx context.Context, id uint, resultID string) error {
+	tx, span := traceWrite(ctx, r.db, "SearchQuery.UpdateClickedResult", "search_queries")
+	err := tx.Model(&models.SearchQuery{}).
 		Where("id = ?", id).
 		Update("clicked_result_id", resultID).Error
+	tracing.End(span, err)
+	return err
 }
 
 func (r *SearchQueryRepositoryImpl) GetSearchAnalytics(from, to time.Time) ([]models.SearchAnalytics, error) {
@@ -59,6 +79,107 @@ func (r *SearchQueryRepositoryImpl) GetSearchAnalytics(from, to time.Time) ([]mo
 	return analytics, err
 }
 
+// DeleteBySession removes every search made by a session together with the
+// feedback attached to those searches
+func (r *SearchQueryRepositoryImpl) DeleteBySession(ctx context.Context, session string) (int64, error) {
+	tx, span := traceWrite(ctx, r.db, "SearchQuery.DeleteBySession", "search_queries")
+	var deleted int64
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			DELETE FROM user_feedback
+			WHERE query_id IN (SELECT id FROM search_queries WHERE user_session = ?)
+		`, session).Error; err != nil {
+			return err
+		}
+		result := tx.Where("user_session = ?", session).Delete(&models.SearchQuery{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	tracing.End(span, err)
+	return deleted, err
+}
+
+// DeleteOlderThan purges searches made before cutoff. Searches that still
+// have feedback are scrubbed instead and stay until feedback retention
+// removes it.
+func (r *SearchQueryRepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, span := traceWrite(ctx, r.db, "SearchQuery.DeleteOlderThan", "search_queries")
+	var deleted int64
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = deleteSearchesBefore(tx, cutoff)
+		return err
+	})
+	tracing.End(span, err)
+	return deleted, err
+}
+
+// AggregateOlderThan rolls the whole hours before cutoff up into hourly
+// search_analytics rows and then deletes their searches. Each hour is rolled
+// up once: distinct session counts cannot be added, so an hour that already
+// has a row keeps it, and searches kept for their feedback are not counted
+// again.
+func (r *SearchQueryRepositoryImpl) AggregateOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, span := traceWrite(ctx, r.db, "SearchQuery.AggregateOlderThan", "search_queries")
+	var deleted int64
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		// Truncate in the database so the boundary matches date_trunc below
+		var hour time.Time
+		if err := tx.Raw(`SELECT date_trunc('hour', ?::timestamptz)`, cutoff).Row().Scan(&hour); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO search_analytics
+				(date_hour, total_searches, avg_response_time_ms, successful_searches, failed_searches, unique_sessions, created_at)
+			SELECT
+				date_trunc('hour', search_timestamp),
+				COUNT(*),
+				COALESCE(AVG(response_time_ms), 0)::INTEGER,
+				COUNT(*) FILTER (WHERE results_count > 0),
+				COUNT(*) FILTER (WHERE results_count = 0),
+				COUNT(DISTINCT user_session),
+				NOW()
+			FROM search_queries
+			WHERE search_timestamp < ?
+			GROUP BY 1
+			ON CONFLICT (date_hour) DO NOTHING
+		`, hour).Error; err != nil {
+			return err
+		}
+
+		var err error
+		deleted, err = deleteSearchesBefore(tx, hour)
+		return err
+	})
+	tracing.End(span, err)
+	return deleted, err
+}
+
+// deleteSearchesBefore removes searches made before cutoff. A search that
+// feedback still refers to is kept for privacy.feedback_days, but is
+// scrubbed down to the row the feedback points at: its text, session,
+// address and user agent go with the rest.
+func deleteSearchesBefore(tx *gorm.DB, cutoff time.Time) (int64, error) {
+	if err := tx.Model(&models.SearchQuery{}).
+		Where("search_timestamp < ?", cutoff).
+		Where("query_text <> '' OR user_session IS NOT NULL OR user_agent IS NOT NULL OR ip_address IS NOT NULL").
+		Updates(map[string]interface{}{
+			"query_text":   "",
+			"user_session": nil,
+			"user_agent":   nil,
+			"ip_address":   nil,
+		}).Error; err != nil {
+		return 0, err
+	}
+
+	result := tx.
+		Where("search_timestamp < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM user_feedback WHERE user_feedback.query_id = search_queries.id)").
+		Delete(&models.SearchQuery{})
+	return result.RowsAffected, result.Error
+}
+
 // ContentMetadataRepositoryImpl implements ContentMetadataRepository
 type ContentMetadataRepositoryImpl struct {
 	db *gorm.DB
@@ -68,8 +189,11 @@ func NewContentMetadataRepository(db *gorm.DB) models.ContentMetadataRepository
 	return &ContentMetadataRepositoryImpl{db: db}
 }
 
-func (r *ContentMetadataRepositoryImpl) Create(content *models.ContentMetadata) error {
-	return r.db.Create(content).Error
+func (r *ContentMetadataRepositoryImpl) Create(ctx context.Context, content *models.ContentMetadata) error {
+	tx, span := traceWrite(ctx, r.db, "ContentMetadata.Create", "content_metadata")
+	err := tx.Create(content).Error
+	tracing.End(span, err)
+	return err
 }
 
 func (r *ContentMetadataRepositoryImpl) GetByID(id uint) (*models.ContentMetadata, error) {
@@ -106,17 +230,52 @@ func (r *ContentMetadataRepositoryImpl) GetActive() ([]models.ContentMetadata, e
 	return contents, err
 }
 
-func (r *ContentMetadataRepositoryImpl) Update(content *models.ContentMetadata) error {
-	return r.db.Save(content).Error
+// GetActiveOutlines returns the active pages with only the title of each
+// section loaded, for callers that need the headings but not the content
+func (r *ContentMetadataRepositoryImpl) GetActiveOutlines() ([]models.ContentMetadata, error) {
+	var contents []models.ContentMetadata
+	err := r.db.Where("is_active = ?", true).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "content_metadata_id", "section_title").Order("section_order")
+		}).
+		Find(&contents).Error
+	return contents, err
+}
+
+// List returns every page without its sections, ordered by title
+func (r *ContentMetadataRepositoryImpl) List() ([]models.ContentMetadata, error) {
+	var contents []models.ContentMetadata
+	err := r.db.Order("wiki_page_title").Find(&contents).Error
+	return contents, err
 }
 
-func (r *ContentMetadataRepositoryImpl) UpdateCrawlStatus(id uint, status string) error {
-	return r.db.Model(&models.ContentMetadata{}).
+func (r *ContentMetadataRepositoryImpl) Update(ctx context.Context, content *models.ContentMetadata) error {
+	tx, span := traceWrite(ctx, r.db, "ContentMetadata.Update", "content_metadata")
+	err := tx.Save(content).Error
+	tracing.End(span, err)
+	return err
+}
+
+func (r *ContentMetadataRepositoryImpl) UpdateCrawlStatus(ctx context.Context, id uint, status string) error {
+	tx, span := traceWrite(ctx, r.db, "ContentMetadata.UpdateCrawlStatus", "content_metadata")
+	err := tx.Model(&models.ContentMetadata{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"crawl_status":  status,
 			"last_crawled": time.Now(),
 		}).Error
+	tracing.End(span, err)
+	return err
+}
+
+// SetActive includes a page in search results or excludes it
+func (r *ContentMetadataRepositoryImpl) SetActive(ctx context.Context, id uint, active bool) error {
+	tx, span := traceWrite(ctx, r.db, "ContentMetadata.SetActive", "content_metadata")
+	err := tx.Model(&models.ContentMetadata{}).
+		Where("id = ?", id).
+		Update("is_active", active).Error
+	tracing.End(span, err)
+	return err
 }
 
 func (r *ContentMetadataRepositoryImpl) GetByCrawlStatus(status string) ([]models.ContentMetadata, error) {
@@ -126,8 +285,44 @@ func (r *ContentMetadataRepositoryImpl) GetByCrawlStatus(status string) ([]model
 	return contents, err
 }
 
-func (r *ContentMetadataRepositoryImpl) Delete(id uint) error {
-	return r.db.Delete(&models.ContentMetadata{}, id).Error
+func (r *ContentMetadataRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	tx, span := traceWrite(ctx, r.db, "ContentMetadata.Delete", "content_metadata")
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("content_metadata_id = ?", id).Delete(&models.WikiSection{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ContentMetadata{}, id).Error
+	})
+	tracing.End(span, err)
+	return err
+}
+
+// ReplaceSections swaps the stored sections of a page for a freshly crawled set
+func (r *ContentMetadataRepositoryImpl) ReplaceSections(ctx context.Context, contentID uint, sections []models.WikiSection) error {
+	tx, span := traceWrite(ctx, r.db, "ContentMetadata.ReplaceSections", "wiki_sections")
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("content_metadata_id = ?", contentID).Delete(&models.WikiSection{}).Error; err != nil {
+			return err
+		}
+		if len(sections) == 0 {
+			return nil
+		}
+		for i := range sections {
+			sections[i].ContentMetadataID = contentID
+		}
+		return tx.Omit("ContentMetadata").Create(&sections).Error
+	})
+	tracing.End(span, err)
+	return err
+}
+
+// GetSections returns a page's sections in page order
+func (r *ContentMetadataRepositoryImpl) GetSections(contentID uint) ([]models.WikiSection, error) {
+	var sections []models.WikiSection
+	err := r.db.Where("content_metadata_id = ?", contentID).
+		Order("section_order").
+		Find(&sections).Error
+	return sections, err
 }
 
 // UserFeedbackRepositoryImpl implements UserFeedbackRepository
@@ -139,8 +334,11 @@ func NewUserFeedbackRepository(db *gorm.DB) models.UserFeedbackRepository {
 	return &UserFeedbackRepositoryImpl{db: db}
 }
 
-func (r *UserFeedbackRepositoryImpl) Create(feedback *models.UserFeedback) error {
-	return r.db.Create(feedback).Error
+func (r *UserFeedbackRepositoryImpl) Create(ctx context.Context, feedback *models.UserFeedback) error {
+	tx, span := traceWrite(ctx, r.db, "UserFeedback.Create", "user_feedback")
+	err := tx.Create(feedback).Error
+	tracing.End(span, err)
+	return err
 }
 
 func (r *UserFeedbackRepositoryImpl) GetByQueryID(queryID uint) ([]models.UserFeedback, error) {
@@ -167,6 +365,22 @@ func (r *UserFeedbackRepositoryImpl) GetRecentFeedback(limit int) ([]models.User
 	return feedback, err
 }
 
+// DeleteBySession removes feedback left by a session
+func (r *UserFeedbackRepositoryImpl) DeleteBySession(ctx context.Context, session string) (int64, error) {
+	tx, span := traceWrite(ctx, r.db, "UserFeedback.DeleteBySession", "user_feedback")
+	result := tx.Where("user_session = ?", session).Delete(&models.UserFeedback{})
+	tracing.End(span, result.Error)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteOlderThan removes feedback created before cutoff
+func (r *UserFeedbackRepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, span := traceWrite(ctx, r.db, "UserFeedback.DeleteOlderThan", "user_feedback")
+	result := tx.Where("created_at < ?", cutoff).Delete(&models.UserFeedback{})
+	tracing.End(span, result.Error)
+	return result.RowsAffected, result.Error
+}
+
 // PopularQueryRepositoryImpl implements PopularQueryRepository
 type PopularQueryRepositoryImpl struct {
 	db *gorm.DB
@@ -176,8 +390,9 @@ func NewPopularQueryRepository(db *gorm.DB) models.PopularQueryRepository {
 	return &PopularQueryRepositoryImpl{db: db}
 }
 
-func (r *PopularQueryRepositoryImpl) IncrementCount(queryText string) error {
-	return r.db.Exec(`
+func (r *PopularQueryRepositoryImpl) IncrementCount(ctx context.Context, queryText string) error {
+	tx, span := traceWrite(ctx, r.db, "PopularQuery.IncrementCount", "popular_queries")
+	err := tx.Exec(`
 		INSERT INTO popular_queries (query_text, search_count, last_searched, created_at, updated_at)
 		VALUES (?, 1, NOW(), NOW(), NOW())
 		ON CONFLICT (query_text) 
@@ -186,6 +401,8 @@ func (r *PopularQueryRepositoryImpl) IncrementCount(queryText string) error {
 			last_searched = NOW(),
 			updated_at = NOW()
 	`, queryText).Error
+	tracing.End(span, err)
+	return err
 }
 
 func (r *PopularQueryRepositoryImpl) GetTop(limit int) ([]models.PopularQuery, error) {
@@ -196,8 +413,9 @@ func (r *PopularQueryRepositoryImpl) GetTop(limit int) ([]models.PopularQuery, e
 	return queries, err
 }
 
-func (r *PopularQueryRepositoryImpl) UpdateStats(queryText string, resultsCount float64, responseTime int) error {
-	return r.db.Exec(`
+func (r *PopularQueryRepositoryImpl) UpdateStats(ctx context.Context, queryText string, resultsCount float64, responseTime int) error {
+	tx, span := traceWrite(ctx, r.db, "PopularQuery.UpdateStats", "popular_queries")
+	err := tx.Exec(`
 		UPDATE popular_queries 
 		SET 
 			avg_results_count = (avg_results_count * (search_count - 1) + ?) / search_count,
@@ -205,6 +423,8 @@ func (r *PopularQueryRepositoryImpl) UpdateStats(queryText string, resultsCount
 			updated_at = NOW()
 		WHERE query_text = ?
 	`, resultsCount, responseTime, queryText).Error
+	tracing.End(span, err)
+	return err
 }
 
 // SystemHealthRepositoryImpl implements SystemHealthRepository
@@ -216,11 +436,14 @@ func NewSystemHealthRepository(db *gorm.DB) models.SystemHealthRepository {
 	return &SystemHealthRepositoryImpl{db: db}
 }
 
-func (r *SystemHealthRepositoryImpl) UpdateServiceHealth(serviceName, status string, responseTime int, errorMsg string) error {
-	return r.db.Exec(`
+func (r *SystemHealthRepositoryImpl) UpdateServiceHealth(ctx context.Context, serviceName, status string, responseTime int, errorMsg string) error {
+	tx, span := traceWrite(ctx, r.db, "SystemHealth.UpdateServiceHealth", "system_health")
+	err := tx.Exec(`
 		INSERT INTO system_health (service_name, status, response_time_ms, error_message, checked_at)
 		VALUES (?, ?, ?, ?, NOW())
 	`, serviceName, status, responseTime, errorMsg).Error
+	tracing.End(span, err)
+	return err
 }
 
 func (r *SystemHealthRepositoryImpl) GetServiceHealth(serviceName string) (*models.SystemHealth, error) {
@@ -255,6 +478,123 @@ func (r *SystemHealthRepositoryImpl) GetUnhealthyServices() ([]models.SystemHeal
 	return health, err
 }
 
+// APIKeyRepositoryImpl implements APIKeyRepository
+type APIKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) models.APIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *models.APIKey) error {
+	tx, span := traceWrite(ctx, r.db, "APIKey.Create", "api_keys")
+	err := tx.Create(key).Error
+	tracing.End(span, err)
+	return err
+}
+
+// GetByHash returns the key with the given secret hash, revoked or not
+func (r *APIKeyRepositoryImpl) GetByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.Where("key_hash = ?", hash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) GetByName(name string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.Where("name = ?", name).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) GetAll() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Order("name").Find(&keys).Error
+	return keys, err
+}
+
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, id uint) error {
+	tx, span := traceWrite(ctx, r.db, "APIKey.Revoke", "api_keys")
+	err := tx.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at": time.Now(),
+			"updated_at": time.Now(),
+		}).Error
+	tracing.End(span, err)
+	return err
+}
+
+func (r *APIKeyRepositoryImpl) TouchLastUsed(ctx context.Context, ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	tx, span := traceWrite(ctx, r.db, "APIKey.TouchLastUsed", "api_keys")
+	err := tx.Model(&models.APIKey{}).
+		Where("id IN ?", ids).
+		UpdateColumn("last_used_at", at).Error
+	tracing.End(span, err)
+	return err
+}
+
+// APIKeyUsageRepositoryImpl implements APIKeyUsageRepository
+type APIKeyUsageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyUsageRepository(db *gorm.DB) models.APIKeyUsageRepository {
+	return &APIKeyUsageRepositoryImpl{db: db}
+}
+
+// Add adds the deltas onto the stored daily counters in one transaction
+func (r *APIKeyUsageRepositoryImpl) Add(ctx context.Context, deltas []models.APIKeyUsageDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	tx, span := traceWrite(ctx, r.db, "APIKeyUsage.Add", "api_key_usage")
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		for _, d := range deltas {
+			if err := tx.Exec(`
+				INSERT INTO api_key_usage (api_key_id, day, route, requests, units, errors, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+				ON CONFLICT (api_key_id, day, route)
+				DO UPDATE SET
+					requests = api_key_usage.requests + EXCLUDED.requests,
+					units = api_key_usage.units + EXCLUDED.units,
+					errors = api_key_usage.errors + EXCLUDED.errors,
+					updated_at = NOW()
+			`, d.APIKeyID, d.Day.Format("2006-01-02"), d.Route, d.Requests, d.Units, d.Errors).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	tracing.End(span, err)
+	return err
+}
+
+func (r *APIKeyUsageRepositoryImpl) GetByKey(keyID uint, from, to time.Time) ([]models.APIKeyUsage, error) {
+	var usage []models.APIKeyUsage
+	err := r.db.Where("api_key_id = ? AND day BETWEEN ? AND ?", keyID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("day, route").
+		Find(&usage).Error
+	return usage, err
+}
+
+func (r *APIKeyUsageRepositoryImpl) GetAll(from, to time.Time) ([]models.APIKeyUsage, error) {
+	var usage []models.APIKeyUsage
+	err := r.db.Where("day BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("api_key_id, day, route").
+		Find(&usage).Error
+	return usage, err
+}
+
 // RepositoryManager bundles all repositories
 type RepositoryManager struct {
 	SearchQuery     models.SearchQueryRepository
@@ -262,6 +602,8 @@ type RepositoryManager struct {
 	UserFeedback    models.UserFeedbackRepository
 	PopularQuery    models.PopularQueryRepository
 	SystemHealth    models.SystemHealthRepository
+	APIKey          models.APIKeyRepository
+	APIKeyUsage     models.APIKeyUsageRepository
 }
 
 func NewRepositoryManager(db *gorm.DB) *RepositoryManager {
@@ -271,5 +613,7 @@ func NewRepositoryManager(db *gorm.DB) *RepositoryManager {
 		UserFeedback:    NewUserFeedbackRepository(db),
 		PopularQuery:    NewPopularQueryRepository(db),
 		SystemHealth:    NewSystemHealthRepository(db),
+		APIKey:          NewAPIKeyRepository(db),
+		APIKeyUsage:     NewAPIKeyUsageRepository(db),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,179 @@
+// backend/internal/safety/classifier.go
+package safety
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/seeder"
+)
+
+// warningWindow is how many non-empty lines away from a command a wiki
+// Warning box may be and still be linked to it
+const warningWindow = 3
+
+var (
+	chainSeparator = regexp.MustCompile(`\s*(\|\|?|&&|;)\s*`)
+	nullRedirect   = regexp.MustCompile(`\d?>>?\s*/dev/null`)
+	warningLine    = regexp.MustCompile(`(?i)^warning\s*:\s*`)
+)
+
+// Assessment is the classification of one command
+type Assessment struct {
+	Command string
+	Level   Level
+	Reasons []string
+}
+
+// Annotation is a command found in page content together with its
+// classification and the wiki Warning box next to it, if any
+type Annotation struct {
+	Assessment
+	WikiWarning string
+}
+
+// Classifier rates shell commands by risk using a rule set
+type Classifier struct {
+	rules     []Rule
+	processor *seeder.ContentProcessor
+}
+
+// NewClassifier returns a classifier for rules, or DefaultRules when rules
+// is empty
+func NewClassifier(rules []Rule) *Classifier {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	return &Classifier{
+		rules:     rules,
+		processor: seeder.NewContentProcessor(),
+	}
+}
+
+// NormalizeCommand collapses whitespace so the same command written twice
+// compares equal
+func NormalizeCommand(command string) string {
+	return strings.Join(strings.Fields(command), " ")
+}
+
+// IsWarning reports whether a line of page content is a wiki Warning box
+func IsWarning(line string) bool {
+	return warningLine.MatchString(strings.TrimSpace(line))
+}
+
+// Classify rates a command at the highest level of any rule matching one of
+// its parts, giving the reasons for that level. Commands no rule recognizes
+// are assumed to modify the system.
+func (c *Classifier) Classify(command string) Assessment {
+	command = NormalizeCommand(command)
+	assessment := Assessment{Command: command, Level: ReadOnly}
+
+	matched := false
+	seen := make(map[string]bool)
+	for _, part := range chainSeparator.Split(nullRedirect.ReplaceAllString(command, ""), -1) {
+		part = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "sudo "))
+		if part == "" {
+			continue
+		}
+		for _, r := range c.rules {
+			if !r.Pattern.MatchString(part) {
+				continue
+			}
+			matched = true
+			if r.Level > assessment.Level {
+				assessment.Level = r.Level
+				assessment.Reasons = nil
+				seen = make(map[string]bool)
+			}
+			if r.Level == assessment.Level && r.Level > ReadOnly && !seen[r.Reason] {
+				seen[r.Reason] = true
+				assessment.Reasons = append(assessment.Reasons, r.Reason)
+			}
+		}
+	}
+
+	if !matched {
+		assessment.Level = ModifiesSystem
+		assessment.Reasons = []string{"unrecognized command"}
+	}
+	return assessment
+}
+
+// Annotate classifies every command in a page section and links each wiki
+// Warning box to the block of consecutive command lines nearest to it, a
+// few lines at most. On a tie the block after the warning wins, since
+// the wiki usually warns before the command it is about.
+func (c *Classifier) Annotate(content string) []Annotation {
+	type commandLine struct {
+		position int
+		block    int
+		command  string
+	}
+	type warning struct {
+		position int
+		text     string
+	}
+
+	var commands []commandLine
+	var warnings []warning
+	position, block := 0, -1
+	lastCommand := -2
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if warningLine.MatchString(line) {
+			warnings = append(warnings, warning{position: position, text: warningLine.ReplaceAllString(line, "")})
+		} else if found := c.processor.ExtractCommandExamples(line); len(found) > 0 {
+			if lastCommand != position-1 {
+				block++
+			}
+			lastCommand = position
+			for _, command := range found {
+				commands = append(commands, commandLine{position: position, block: block, command: command})
+			}
+		}
+		position++
+	}
+
+	// Each warning goes to its nearest block; a block keeps the closest
+	// warning pointed at it
+	blockWarning := make(map[int]string)
+	blockDistance := make(map[int]int)
+	for _, w := range warnings {
+		nearest, best := -1, warningWindow+1
+		for _, cmd := range commands {
+			distance := cmd.position - w.position
+			var closer bool
+			if distance < 0 {
+				distance = -distance
+				closer = distance < best
+			} else {
+				closer = distance <= best && distance <= warningWindow
+			}
+			if closer {
+				nearest, best = cmd.block, distance
+			}
+		}
+		if nearest < 0 {
+			continue
+		}
+		if previous, ok := blockDistance[nearest]; !ok || best < previous {
+			blockWarning[nearest] = w.text
+			blockDistance[nearest] = best
+		}
+	}
+
+	annotations := make([]Annotation, 0, len(commands))
+	seen := make(map[string]bool)
+	for _, cmd := range commands {
+		annotation := Annotation{Assessment: c.Classify(cmd.command), WikiWarning: blockWarning[cmd.block]}
+		if seen[annotation.Command] {
+			continue
+		}
+		seen[annotation.Command] = true
+		annotations = append(annotations, annotation)
+	}
+	return annotations
+}
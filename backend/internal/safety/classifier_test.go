@@ -0,0 +1,235 @@
+// backend/internal/safety/classifier_test.go
+package safety
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		command string
+		level   Level
+		reasons []string
+	}{
+		// Commands the wiki warns about
+		{
+			command: "pacman -Rdd linux-firmware",
+			level:   Destructive,
+			reasons: []string{"removes packages while ignoring dependencies", "skips dependency checks"},
+		},
+		{
+			command: "rm -rf /var/lib/pacman/db.lck",
+			level:   Destructive,
+			reasons: []string{"deletes files"},
+		},
+		{
+			command: "dd if=archlinux.iso of=/dev/sdb bs=4M status=progress",
+			level:   DataLoss,
+			reasons: []string{"writes raw data over the output file or device"},
+		},
+		{
+			command: "mkfs.ext4 /dev/sda1",
+			level:   DataLoss,
+			reasons: []string{"creates a file system, erasing the device"},
+		},
+		{
+			command: "mkfs -t vfat /dev/sdb1",
+			level:   DataLoss,
+			reasons: []string{"creates a file system, erasing the device"},
+		},
+		{
+			command: "rm -rf /",
+			level:   DataLoss,
+			reasons: []string{"recursively deletes a top-level directory"},
+		},
+		{
+			command: "pacman -Sy firefox",
+			level:   Destructive,
+			reasons: []string{"partial upgrade, unsupported on Arch"},
+		},
+
+		// sudo is looked through
+		{
+			command: "sudo mkfs.btrfs /dev/nvme0n1p2",
+			level:   DataLoss,
+			reasons: []string{"creates a file system, erasing the device"},
+		},
+		{
+			command: "sudo   pacman  -Syu",
+			level:   ModifiesSystem,
+			reasons: []string{"installs, upgrades or removes packages"},
+		},
+
+		// Chains and pipelines take their riskiest part
+		{
+			command: "pacman -Qi linux && rm -rf /",
+			level:   DataLoss,
+			reasons: []string{"recursively deletes a top-level directory"},
+		},
+		{
+			command: "umount /mnt; sudo mkfs.ext4 /dev/sdb1 || echo failed",
+			level:   DataLoss,
+			reasons: []string{"creates a file system, erasing the device"},
+		},
+		{
+			command: "journalctl -b | grep -i error",
+			level:   ReadOnly,
+		},
+		{
+			command: "echo 'Server = https://mirror.example/$repo' | sudo tee -a /etc/pacman.d/mirrorlist",
+			level:   ModifiesSystem,
+			reasons: []string{"changes files"},
+		},
+
+		// Redirects
+		{
+			command: "ls /boot > /dev/null 2>&1",
+			level:   ReadOnly,
+		},
+		{
+			command: "echo options > /etc/modprobe.d/nouveau.conf",
+			level:   ModifiesSystem,
+			reasons: []string{"writes to a file"},
+		},
+		{
+			command: "cat image.img > /dev/sdb",
+			level:   DataLoss,
+			reasons: []string{"redirects output onto a block device"},
+		},
+
+		// Read-only and unknown commands
+		{
+			command: "pacman -Qi linux",
+			level:   ReadOnly,
+		},
+		{
+			command: "systemctl status sshd",
+			level:   ReadOnly,
+		},
+		{
+			command: "dd if=/dev/sda",
+			level:   ModifiesSystem,
+			reasons: []string{"unrecognized command"},
+		},
+		{
+			command: "frobnicate --all",
+			level:   ModifiesSystem,
+			reasons: []string{"unrecognized command"},
+		},
+	}
+
+	c := NewClassifier(nil)
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := c.Classify(tt.command)
+			if got.Command != NormalizeCommand(tt.command) {
+				t.Errorf("command %q, want %q", got.Command, NormalizeCommand(tt.command))
+			}
+			if got.Level != tt.level || !reflect.DeepEqual(got.Reasons, tt.reasons) {
+				t.Errorf("Classify(%q) = %s %q, want %s %q", tt.command, got.Level, got.Reasons, tt.level, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	type linked struct {
+		command string
+		warning string
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []linked
+	}{
+		{
+			name:    "no warning",
+			content: "Check the kernel.\n$ uname -r\n# pacman -Qi linux",
+			want:    []linked{{"uname -r", ""}, {"pacman -Qi linux", ""}},
+		},
+		{
+			name:    "warning before its command",
+			content: "Warning: This deletes the lock.\n# rm /var/lib/pacman/db.lck",
+			want:    []linked{{"rm /var/lib/pacman/db.lck", "This deletes the lock."}},
+		},
+		{
+			name:    "warning after the only command",
+			content: "# rm /var/lib/pacman/db.lck\nWarning: This deletes the lock.",
+			want:    []linked{{"rm /var/lib/pacman/db.lck", "This deletes the lock."}},
+		},
+		{
+			name:    "whole block of consecutive commands shares the warning",
+			content: "Warning: This erases the partition.\n# umount /dev/sdb1\n# mkfs.ext4 /dev/sdb1",
+			want: []linked{
+				{"umount /dev/sdb1", "This erases the partition."},
+				{"mkfs.ext4 /dev/sdb1", "This erases the partition."},
+			},
+		},
+		{
+			name:    "tie goes to the block after the warning",
+			content: "# pacman -Qi linux\nWarning: This erases the partition.\n# mkfs.ext4 /dev/sdb1",
+			want: []linked{
+				{"pacman -Qi linux", ""},
+				{"mkfs.ext4 /dev/sdb1", "This erases the partition."},
+			},
+		},
+		{
+			name:    "nearer block before the warning wins",
+			content: "# rm -rf ~/.cache/yay\nWarning: Cached builds are lost.\nRebuild later.\n# pacman -Syu",
+			want: []linked{
+				{"rm -rf ~/.cache/yay", "Cached builds are lost."},
+				{"pacman -Syu", ""},
+			},
+		},
+		{
+			name:    "nearer block after the warning wins",
+			content: "# pacman -Qi linux\nFirst check the version.\nWarning: Cached packages are lost.\n# pacman -Scc",
+			want: []linked{
+				{"pacman -Qi linux", ""},
+				{"pacman -Scc", "Cached packages are lost."},
+			},
+		},
+		{
+			name:    "edge of the window",
+			content: "Warning: This erases the partition.\nOne.\nTwo.\n# mkfs.ext4 /dev/sdb1",
+			want:    []linked{{"mkfs.ext4 /dev/sdb1", "This erases the partition."}},
+		},
+		{
+			name:    "beyond the window",
+			content: "Warning: This erases the partition.\nOne.\nTwo.\nThree.\n# mkfs.ext4 /dev/sdb1",
+			want:    []linked{{"mkfs.ext4 /dev/sdb1", ""}},
+		},
+		{
+			name:    "block keeps the closest of two warnings",
+			content: "Warning: Far away.\nSome prose.\nWarning: Close by.\n# rm /var/lib/pacman/db.lck",
+			want:    []linked{{"rm /var/lib/pacman/db.lck", "Close by."}},
+		},
+		{
+			name:    "blank lines do not count towards the distance",
+			content: "Warning: This deletes the lock.\n\n\n\n# rm /var/lib/pacman/db.lck",
+			want:    []linked{{"rm /var/lib/pacman/db.lck", "This deletes the lock."}},
+		},
+		{
+			name:    "repeated command annotated once",
+			content: "# pacman -Syu\nThen again.\n#   pacman -Syu",
+			want:    []linked{{"pacman -Syu", ""}},
+		},
+	}
+
+	c := NewClassifier(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []linked
+			for _, annotation := range c.Annotate(tt.content) {
+				if want := c.Classify(annotation.Command); !reflect.DeepEqual(annotation.Assessment, want) {
+					t.Errorf("%q assessed as %+v, want %+v", annotation.Command, annotation.Assessment, want)
+				}
+				got = append(got, linked{annotation.Command, annotation.WikiWarning})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Annotate\n got  %q\n want %q", got, tt.want)
+			}
+		})
+	}
+}
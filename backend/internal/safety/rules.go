@@ -0,0 +1,92 @@
+// backend/internal/safety/rules.go
+package safety
+
+import "regexp"
+
+// Level is how much harm running a command can do, from least to most
+type Level int
+
+const (
+	ReadOnly Level = iota
+	ModifiesSystem
+	Destructive
+	DataLoss
+)
+
+func (l Level) String() string {
+	switch l {
+	case ReadOnly:
+		return "read_only"
+	case ModifiesSystem:
+		return "modifies_system"
+	case Destructive:
+		return "destructive"
+	case DataLoss:
+		return "data_loss"
+	default:
+		return "unknown"
+	}
+}
+
+// Rule assigns a level to commands matching Pattern. Patterns are matched
+// against one command of a pipeline or chain at a time, with any leading
+// sudo removed, so ^ anchors at the program name.
+type Rule struct {
+	Level   Level
+	Pattern *regexp.Regexp
+	Reason  string
+}
+
+func rule(level Level, pattern, reason string) Rule {
+	return Rule{Level: level, Pattern: regexp.MustCompile(pattern), Reason: reason}
+}
+
+// DefaultRules covers the commands that show up in Arch Wiki troubleshooting
+// sections. Commands matching no rule are treated as modifying the system.
+var DefaultRules = []Rule{
+	// Data loss: the command overwrites or erases storage
+	rule(DataLoss, `^dd\b.*\bof=`, "writes raw data over the output file or device"),
+	rule(DataLoss, `^mkfs(\.\w+)?\b`, "creates a file system, erasing the device"),
+	rule(DataLoss, `^mkswap\b`, "formats the device as swap"),
+	rule(DataLoss, `^(wipefs|shred|blkdiscard)\b`, "erases data on the device"),
+	rule(DataLoss, `^cryptsetup\s+(luksFormat|erase|luksErase)\b`, "destroys the existing encrypted volume"),
+	rule(DataLoss, `^sgdisk\b.*(\s--zap(-all)?\b|\s-[oZz]\b)`, "wipes the partition table"),
+	rule(DataLoss, `^rm\s+(-\S+\s+)*(/|/\*|~|~/|\*|/home|/boot|/etc|/usr)(\s|$)`, "recursively deletes a top-level directory"),
+	rule(DataLoss, `>\s*/dev/(sd|nvme|mmcblk|vd|hd)`, "redirects output onto a block device"),
+
+	// Destructive: hard to undo or able to leave the system unbootable
+	rule(Destructive, `^rm\b`, "deletes files"),
+	rule(Destructive, `^(fdisk|gdisk|cfdisk|parted|sfdisk|sgdisk)\b`, "edits the partition table"),
+	rule(Destructive, `^pacman\s+-R\S*dd`, "removes packages while ignoring dependencies"),
+	rule(Destructive, `^pacman\s+-\S*d\S*d`, "skips dependency checks"),
+	rule(Destructive, `^pacman\s.*--overwrite\b`, "overwrites files owned by other packages"),
+	rule(Destructive, `^pacman\s+-Sy\s+[^-]`, "partial upgrade, unsupported on Arch"),
+	rule(Destructive, `^pacman\s+-Sc?cc?\b`, "deletes cached packages needed for downgrades"),
+	rule(Destructive, `^(chmod|chown|chgrp)\s+(-\S*\s+)*-\S*R`, "recursively changes ownership or permissions"),
+	rule(Destructive, `^git\s+(reset\s+--hard|clean\s+-\S*f)`, "discards uncommitted changes"),
+	rule(Destructive, `^(grub-install|bootctl\s+install|efibootmgr\s+.*-[bB])`, "rewrites the boot loader"),
+	rule(Destructive, `^(userdel|groupdel)\b`, "deletes accounts"),
+
+	// Modifies system: changes state but is routine and reversible
+	rule(ModifiesSystem, `^pacman\s+-[SURD]`, "installs, upgrades or removes packages"),
+	rule(ModifiesSystem, `^(pacman-key|paccache|pacman-db-upgrade)\b`, "changes package manager state"),
+	rule(ModifiesSystem, `^systemctl\s+(enable|disable|start|stop|restart|reload|mask|unmask|daemon-reload|edit|set-default|isolate|kill|reset-failed)\b`, "changes services"),
+	rule(ModifiesSystem, `^(reboot|poweroff|shutdown|halt)\b`, "restarts or stops the machine"),
+	rule(ModifiesSystem, `^(mkinitcpio|grub-mkconfig|update-grub|dracut)\b`, "regenerates boot files"),
+	rule(ModifiesSystem, `^(modprobe|rmmod|insmod|sysctl\s+-w|sysctl\s+--system)\b`, "changes the running kernel"),
+	rule(ModifiesSystem, `^(mount|umount|swapon|swapoff|fsck(\.\w+)?|e2fsck|tune2fs)\b`, "changes file systems"),
+	rule(ModifiesSystem, `^(useradd|usermod|passwd|groupadd|gpasswd|chsh)\b`, "changes accounts"),
+	rule(ModifiesSystem, `^(chmod|chown|chgrp|ln|cp|mv|mkdir|touch|install|tee)\b`, "changes files"),
+	rule(ModifiesSystem, `^sed\s+(-\S+\s+)*-i`, "edits files in place"),
+	rule(ModifiesSystem, `(^|[^2&])>>?\s*\S`, "writes to a file"),
+	rule(ModifiesSystem, `^(timedatectl|hostnamectl|localectl)\s+set-`, "changes system settings"),
+	rule(ModifiesSystem, `^(ip\s+(link|addr|route)\s+(set|add|del)|nmcli\s+.*(up|down|add|modify|delete))\b`, "changes network configuration"),
+
+	// Read-only: inspects state without changing it
+	rule(ReadOnly, `^(ls|cat|less|more|head|tail|grep|find|file|stat|wc|diff|which|whereis|type|echo|printf)\b`, "reads files"),
+	rule(ReadOnly, `^(journalctl|dmesg|lsblk|blkid|lsusb|lspci|lsmod|lscpu|free|df|du|uname|uptime|id|whoami|findmnt|ps|top|htop|env|locale)\b`, "reports system state"),
+	rule(ReadOnly, `^pacman\s+-(Q|F|Ss|Si|Sl|Sg)`, "queries packages"),
+	rule(ReadOnly, `^systemctl\s+(status|show|cat|list-\S+|is-\S+|--failed)`, "reports service state"),
+	rule(ReadOnly, `^(ip\s+(a|addr|link|route)(\s+show)?|ping|resolvectl\s+status|nmcli\s+(device|connection)\s+show|ss|iw\s+dev)\s*`, "reports network state"),
+	rule(ReadOnly, `^(sysctl|timedatectl|hostnamectl|localectl)(\s+(status|-a|show))?\s*$`, "reports settings"),
+}
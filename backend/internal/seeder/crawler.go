@@ -0,0 +1,439 @@
+// backend/internal/seeder/crawler.go
+package seeder
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/alchemyst"
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// userAgent identifies the crawler to the wiki
+const userAgent = "ArchSearch-Bot/1.0 (+https://github.com/yourusername/arch-search)"
+
+// ErrPageInactive is returned when crawling a page an operator deactivated
+var ErrPageInactive = errors.New("page is deactivated")
+
+var (
+	whitespacePattern = regexp.MustCompile(`\s+`)
+	blankLinesPattern = regexp.MustCompile(`\n\s*\n`)
+
+	// Common error patterns in Arch Linux
+	errorRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)error[:\s]+[a-zA-Z0-9\s\-\._/]+`),
+		regexp.MustCompile(`(?i)failed[:\s]+[a-zA-Z0-9\s\-\._/]+`),
+		regexp.MustCompile(`(?i)cannot[:\s]+[a-zA-Z0-9\s\-\._/]+`),
+		regexp.MustCompile(`(?i)unable to[:\s]+[a-zA-Z0-9\s\-\._/]+`),
+		regexp.MustCompile(`(?i)permission denied[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+		regexp.MustCompile(`(?i)no such file or directory[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+		regexp.MustCompile(`(?i)command not found[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+		regexp.MustCompile(`(?i)segmentation fault[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+		regexp.MustCompile(`(?i)kernel panic[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+		regexp.MustCompile(`(?i)dependency.*conflict[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+		regexp.MustCompile(`(?i)package.*not found[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+		regexp.MustCompile(`(?i)service.*failed[:\s]*[a-zA-Z0-9\s\-\._/]*`),
+	}
+)
+
+// Page is a wiki page to crawl
+type Page struct {
+	Title    string
+	URL      string
+	Priority int
+}
+
+// Section is a heading of a crawled page and the text under it
+type Section struct {
+	Title   string
+	Content string
+	Anchor  string
+	Level   int
+}
+
+// Document is the content extracted from one crawl of a page
+type Document struct {
+	Page          Page
+	Content       string
+	Sections      []Section
+	ErrorPatterns []string
+	Hash          string
+}
+
+// Change describes how a crawl changed a page's stored content
+type Change int
+
+const (
+	Unchanged Change = iota
+	Modified
+	Added
+)
+
+// CrawlerConfig tunes how the wiki is fetched
+type CrawlerConfig struct {
+	Delay   time.Duration // between requests to the wiki
+	Timeout time.Duration // per request
+}
+
+// Crawler fetches wiki pages, records their metadata and sections and
+// uploads them to Alchemyst. The seed command crawls every configured page
+// with it; the admin API re-crawls single pages.
+type Crawler struct {
+	alchemystService *alchemyst.Service
+	repoManager      *repository.RepositoryManager
+	cache            *database.Cache
+	cfg              CrawlerConfig
+	logger           *logrus.Logger
+}
+
+func NewCrawler(alchemystService *alchemyst.Service, repoManager *repository.RepositoryManager, cache *database.Cache, cfg CrawlerConfig, logger *logrus.Logger) *Crawler {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &Crawler{
+		alchemystService: alchemystService,
+		repoManager:      repoManager,
+		cache:            cache,
+		cfg:              cfg,
+		logger:           logger,
+	}
+}
+
+// Fetch downloads a page and extracts its content, sections and error
+// patterns without storing anything
+func (c *Crawler) Fetch(ctx context.Context, page Page) (*Document, error) {
+	log := utils.LoggerFromContext(ctx, c.logger)
+	doc := &Document{Page: page}
+	var processingError error
+
+	// Create a new collector for each page to avoid state issues
+	collector := colly.NewCollector(colly.UserAgent(userAgent))
+	collector.Limit(&colly.LimitRule{
+		DomainGlob:  "wiki.archlinux.org",
+		Parallelism: 1,
+		Delay:       c.cfg.Delay,
+	})
+	collector.SetRequestTimeout(c.cfg.Timeout)
+
+	collector.OnHTML("#mw-content-text", func(e *colly.HTMLElement) {
+		doc.Content = extractPageContent(e)
+		doc.Sections = extractSections(e)
+
+		log.WithFields(logrus.Fields{
+			"page":           page.Title,
+			"content_length": len(doc.Content),
+			"sections":       len(doc.Sections),
+		}).Debug("Content extracted")
+	})
+
+	collector.OnError(func(r *colly.Response, err error) {
+		processingError = err
+	})
+
+	if err := collector.Visit(page.URL); err != nil {
+		return nil, fmt.Errorf("failed to visit page: %w", err)
+	}
+	if processingError != nil {
+		return nil, fmt.Errorf("processing error: %w", processingError)
+	}
+	if doc.Content == "" {
+		return nil, fmt.Errorf("no content extracted from page")
+	}
+
+	doc.ErrorPatterns = ExtractErrorPatterns(doc.Content)
+	doc.Hash = contentHash(doc.Content)
+	return doc, nil
+}
+
+// Crawl fetches a page, records its metadata and sections, replaces its
+// documents in Alchemyst and drops cached searches that used it. Pages an
+// operator deactivated are skipped with ErrPageInactive unless reactivate
+// is set. New pages are reported as Added; invalidating the searches that
+// could now find them is left to the caller, which may crawl many.
+func (c *Crawler) Crawl(ctx context.Context, page Page, reactivate bool) (Change, error) {
+	log := utils.LoggerFromContext(ctx, c.logger)
+
+	existing, err := c.repoManager.ContentMetadata.GetByTitle(page.Title)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return Unchanged, fmt.Errorf("failed to load page metadata: %w", err)
+	}
+	if existing != nil {
+		if !existing.IsActive && !reactivate {
+			return Unchanged, ErrPageInactive
+		}
+		if err := c.repoManager.ContentMetadata.UpdateCrawlStatus(ctx, existing.ID, "crawling"); err != nil {
+			log.WithError(err).WithField("page", page.Title).Warn("Failed to mark page as crawling")
+		}
+	}
+
+	change, err := c.crawl(ctx, page, existing)
+	if err != nil && existing != nil {
+		if statusErr := c.repoManager.ContentMetadata.UpdateCrawlStatus(ctx, existing.ID, "failed"); statusErr != nil {
+			log.WithError(statusErr).WithField("page", page.Title).Warn("Failed to mark page as failed")
+		}
+	}
+	return change, err
+}
+
+func (c *Crawler) crawl(ctx context.Context, page Page, existing *models.ContentMetadata) (Change, error) {
+	log := utils.LoggerFromContext(ctx, c.logger)
+
+	doc, err := c.Fetch(ctx, page)
+	if err != nil {
+		return Unchanged, err
+	}
+
+	// The old documents are named after the old sections, so collect them
+	// before the metadata is replaced
+	if existing != nil {
+		if err := c.Remove(ctx, existing); err != nil {
+			return Unchanged, fmt.Errorf("failed to remove previous content: %w", err)
+		}
+	}
+
+	change, err := c.storeMetadata(ctx, doc, existing)
+	if err != nil {
+		return Unchanged, fmt.Errorf("failed to update content metadata: %w", err)
+	}
+
+	// Upload main content to Alchemyst
+	if err := c.upload(ctx, page.Title, doc.Content, page.URL); err != nil {
+		return change, fmt.Errorf("failed to upload main content: %w", err)
+	}
+
+	// Upload sections separately for better search granularity
+	for i, section := range doc.Sections {
+		sectionTitle := SectionDocument(page.Title, section.Title)
+		if err := c.upload(ctx, sectionTitle, section.Content, page.URL+"#"+section.Anchor); err != nil {
+			log.WithError(err).WithField("section", sectionTitle).Warn("Failed to upload section")
+			continue
+		}
+
+		// Log progress for long pages
+		if len(doc.Sections) > 10 && i%5 == 0 {
+			log.WithFields(logrus.Fields{
+				"page":     page.Title,
+				"progress": fmt.Sprintf("%d/%d", i+1, len(doc.Sections)),
+			}).Debug("Section upload progress")
+		}
+	}
+
+	if change == Modified && c.cache != nil {
+		removed, err := c.cache.InvalidatePage(ctx, page.Title)
+		if err != nil {
+			log.WithError(err).WithField("page", page.Title).Warn("Failed to invalidate cached searches")
+		} else {
+			log.WithFields(logrus.Fields{
+				"page":    page.Title,
+				"entries": removed,
+			}).Info("Invalidated cached searches for changed page")
+		}
+	}
+
+	return change, nil
+}
+
+// Remove deletes a page's documents, and those of its sections, from
+// Alchemyst. Its metadata and cached searches are left alone.
+func (c *Crawler) Remove(ctx context.Context, content *models.ContentMetadata) error {
+	if c.alchemystService == nil {
+		return fmt.Errorf("alchemyst service not initialized")
+	}
+	if err := c.alchemystService.DeleteWikiContent(ctx, content.WikiPageTitle); err != nil {
+		return err
+	}
+	for _, section := range content.Sections {
+		if err := c.alchemystService.DeleteWikiContent(ctx, SectionDocument(content.WikiPageTitle, section.SectionTitle)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SectionDocument names the Alchemyst document holding one section of a page
+func SectionDocument(pageTitle, sectionTitle string) string {
+	return fmt.Sprintf("%s/%s", pageTitle, sectionTitle)
+}
+
+// storeMetadata records a crawled page and its sections, reactivating it
+func (c *Crawler) storeMetadata(ctx context.Context, doc *Document, existing *models.ContentMetadata) (Change, error) {
+	now := time.Now()
+	var patterns models.StringArray = doc.ErrorPatterns
+	wordCount := len(strings.Fields(doc.Content))
+
+	if existing != nil {
+		change := Unchanged
+		if existing.ContentHash != doc.Hash {
+			change = Modified
+		}
+
+		existing.ContentHash = doc.Hash
+		existing.PageURL = doc.Page.URL
+		existing.ErrorPatterns = patterns
+		existing.WordCount = wordCount
+		existing.SectionCount = len(doc.Sections)
+		existing.LastCrawled = &now
+		existing.CrawlStatus = "completed"
+		existing.IsActive = true
+		existing.Sections = nil
+
+		if err := c.repoManager.ContentMetadata.Update(ctx, existing); err != nil {
+			return Unchanged, err
+		}
+		return change, c.storeSections(ctx, existing.ID, doc.Sections)
+	}
+
+	content := &models.ContentMetadata{
+		WikiPageTitle: doc.Page.Title,
+		ContentHash:   doc.Hash,
+		PageURL:       doc.Page.URL,
+		ErrorPatterns: patterns,
+		WordCount:     wordCount,
+		SectionCount:  len(doc.Sections),
+		LastCrawled:   &now,
+		CrawlStatus:   "completed",
+		IsActive:      true,
+	}
+	if err := c.repoManager.ContentMetadata.Create(ctx, content); err != nil {
+		return Unchanged, err
+	}
+	return Added, c.storeSections(ctx, content.ID, doc.Sections)
+}
+
+// storeSections records the page's section headings so they can be offered
+// as suggestions and listed alongside the page
+func (c *Crawler) storeSections(ctx context.Context, contentID uint, sections []Section) error {
+	records := make([]models.WikiSection, 0, len(sections))
+	for i, section := range sections {
+		records = append(records, models.WikiSection{
+			SectionTitle:   section.Title,
+			SectionContent: section.Content,
+			SectionOrder:   i,
+			ErrorPatterns:  ExtractErrorPatterns(section.Content),
+		})
+	}
+	return c.repoManager.ContentMetadata.ReplaceSections(ctx, contentID, records)
+}
+
+func (c *Crawler) upload(ctx context.Context, title, content, wikiURL string) error {
+	if c.alchemystService == nil {
+		return fmt.Errorf("alchemyst service not initialized")
+	}
+
+	utils.LoggerFromContext(ctx, c.logger).WithFields(logrus.Fields{
+		"title":          title,
+		"content_length": len(content),
+		"url":            wikiURL,
+	}).Debug("Uploading to Alchemyst")
+
+	return c.alchemystService.AddWikiContent(ctx, title, content, wikiURL)
+}
+
+func extractPageContent(e *colly.HTMLElement) string {
+	// Remove unwanted elements
+	e.DOM.Find(".navbox, .infobox, .ambox, .toc, .printfooter, .catlinks").Remove()
+	e.DOM.Find("#toc, .noprint, .editlink, .mw-editsection").Remove()
+
+	// Get text content
+	text := strings.TrimSpace(e.DOM.Text())
+
+	// Clean up whitespace
+	text = whitespacePattern.ReplaceAllString(text, " ")
+	text = blankLinesPattern.ReplaceAllString(text, "\n\n")
+
+	return text
+}
+
+func extractSections(e *colly.HTMLElement) []Section {
+	var sections []Section
+
+	e.DOM.Find("h2, h3, h4").Each(func(i int, selection *goquery.Selection) {
+		// Get section title
+		titleText := strings.TrimSpace(selection.Find(".mw-headline").Text())
+		if titleText == "" {
+			return
+		}
+
+		// Get anchor
+		anchor := ""
+		if id, exists := selection.Find(".mw-headline").Attr("id"); exists {
+			anchor = id
+		}
+
+		// Get section level based on tag name
+		level := 2
+		switch goquery.NodeName(selection) {
+		case "h3":
+			level = 3
+		case "h4":
+			level = 4
+		}
+
+		// Get section content (find content until next heading)
+		var content strings.Builder
+		selection.NextUntil("h2, h3, h4").Each(func(j int, sibling *goquery.Selection) {
+			// Skip certain elements
+			if sibling.Is("table") || sibling.HasClass("navbox") || sibling.HasClass("ambox") {
+				return
+			}
+
+			text := strings.TrimSpace(sibling.Text())
+			if text != "" {
+				content.WriteString(text + "\n")
+			}
+		})
+
+		sectionContent := strings.TrimSpace(content.String())
+
+		// Only include sections with substantial content
+		if len(sectionContent) > 50 {
+			sections = append(sections, Section{
+				Title:   titleText,
+				Content: sectionContent,
+				Anchor:  anchor,
+				Level:   level,
+			})
+		}
+	})
+
+	return sections
+}
+
+// ExtractErrorPatterns finds error messages quoted in wiki text
+func ExtractErrorPatterns(content string) []string {
+	patterns := make(map[string]bool)
+
+	for _, regex := range errorRegexes {
+		for _, match := range regex.FindAllString(content, -1) {
+			// Clean and normalize the pattern
+			pattern := whitespacePattern.ReplaceAllString(strings.TrimSpace(match), " ")
+			if len(pattern) > 5 && len(pattern) < 100 {
+				patterns[strings.ToLower(pattern)] = true
+			}
+		}
+	}
+
+	var result []string
+	for pattern := range patterns {
+		result = append(result, pattern)
+	}
+	return result
+}
+
+func contentHash(content string) string {
+	hash := md5.Sum([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
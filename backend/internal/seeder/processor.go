@@ -71,9 +71,9 @@ func (cp *ContentProcessor) ExtractCommandExamples(content string) []string {
 	commandPatterns := []*regexp.Regexp{
 		regexp.MustCompile(`(?m)^\s*\$\s+([^\n]+)`),      // $ command
 		regexp.MustCompile(`(?m)^\s*#\s+([^\n]+)`),       // # command
-		regexp.MustCompile(`(?m)^\s*sudo\s+([^\n]+)`),    // sudo command
-		regexp.MustCompile(`(?m)^\s*pacman\s+([^\n]+)`),  // pacman command
-		regexp.MustCompile(`(?m)^\s*systemctl\s+([^\n]+)`), // systemctl command
+		regexp.MustCompile(`(?m)^\s*(sudo\s+[^\n]+)`),    // sudo command
+		regexp.MustCompile(`(?m)^\s*(pacman\s+[^\n]+)`),  // pacman command
+		regexp.MustCompile(`(?m)^\s*(systemctl\s+[^\n]+)`), // systemctl command
 	}
 	
 	for _, pattern := range commandPatterns {
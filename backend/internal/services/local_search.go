@@ -0,0 +1,274 @@
+// backend/internal/services/local_search.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// localSnapshotTTL bounds how stale the in-memory copy of page metadata used
+// for local matching may get
+const localSnapshotTTL = 5 * time.Minute
+
+// localScoreWeight keeps keyword matches below comparable semantic matches
+// from the backend when the two are merged
+const localScoreWeight = 0.6
+
+// QueryAnalysis describes how a query will be searched
+type QueryAnalysis struct {
+	Query          string   `json:"query"`
+	ProcessedQuery string   `json:"processed_query"`
+	Keywords       []string `json:"keywords"`
+	Limit          int      `json:"limit"`
+}
+
+// localSnapshot is the subset of wiki metadata needed for keyword matching
+// and for keeping deactivated pages out of results
+type localSnapshot struct {
+	mu       sync.Mutex
+	pages    []localPage
+	titles   map[string]string // every page title, keyed by its lowercase form
+	inactive map[string]bool   // lowercased titles of deactivated pages
+	loaded   time.Time
+}
+
+type localPage struct {
+	name     string // wiki title, e.g. General_troubleshooting
+	title    string // lowercased display title
+	url      string
+	sections []string // lowercased section titles, in page order
+	display  []string // section titles as written
+	patterns []string // lowercased error patterns
+}
+
+// Analyze preprocesses a query the same way SearchForSolution does
+func (s *SearchService) Analyze(ctx context.Context, query string, limit int) QueryAnalysis {
+	processed := s.preprocessQuery(ctx, query)
+	return QueryAnalysis{
+		Query:          query,
+		ProcessedQuery: processed,
+		Keywords:       strings.Fields(strings.ToLower(processed)),
+		Limit:          ClampLimit(limit),
+	}
+}
+
+// LocalSearch matches keywords against indexed page titles, section titles
+// and error patterns without calling the backend. It answers in
+// milliseconds and is meant to be shown while the semantic search runs.
+func (s *SearchService) LocalSearch(ctx context.Context, keywords []string, limit int) (_ []models.SearchResult, err error) {
+	ctx, span := tracing.Start(ctx, "SearchService.LocalSearch",
+		attribute.Int("search.keywords", len(keywords)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+
+	pages, err := s.localPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []models.SearchResult
+	for _, page := range pages {
+		result, ok := matchLocalPage(page, keywords)
+		if !ok {
+			continue
+		}
+		result.Relevance = s.determineRelevance(result.Score)
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	limit = ClampLimit(limit)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	span.SetAttributes(attribute.Int("search.results", len(results)))
+	return results, nil
+}
+
+// matchLocalPage scores a page by the share of keywords found in its title,
+// section titles or error patterns, pointing the result at the best section
+func matchLocalPage(page localPage, keywords []string) (models.SearchResult, bool) {
+	var matched float64
+	bestSection, bestSectionHits := -1, 0
+	var snippet string
+
+	for _, keyword := range keywords {
+		if strings.Contains(page.title, keyword) {
+			matched += 1
+			continue
+		}
+		if i := containsAny(page.patterns, keyword); i >= 0 {
+			matched += 0.8
+			if snippet == "" {
+				snippet = page.patterns[i]
+			}
+			continue
+		}
+		if containsAny(page.sections, keyword) >= 0 {
+			matched += 0.6
+		}
+	}
+	if matched == 0 {
+		return models.SearchResult{}, false
+	}
+
+	for i, section := range page.sections {
+		hits := 0
+		for _, keyword := range keywords {
+			if strings.Contains(section, keyword) {
+				hits++
+			}
+		}
+		if hits > bestSectionHits {
+			bestSection, bestSectionHits = i, hits
+		}
+	}
+
+	result := models.SearchResult{
+		Title: fmt.Sprintf("Arch Wiki - %s", strings.ReplaceAll(page.name, "_", " ")),
+		URL:   page.url,
+		Page:  page.name,
+		Score: localScoreWeight * matched / float64(len(keywords)),
+	}
+	if bestSection >= 0 {
+		result.URL = page.url + "#" + strings.ReplaceAll(page.display[bestSection], " ", "_")
+		result.Content = page.display[bestSection]
+	}
+	if snippet != "" {
+		result.Content = snippet
+	}
+	return result, true
+}
+
+// containsAny returns the index of the first value containing keyword, or -1
+func containsAny(values []string, keyword string) int {
+	for i, value := range values {
+		if strings.Contains(value, keyword) {
+			return i
+		}
+	}
+	return -1
+}
+
+// localPages returns the active pages of the metadata snapshot
+func (s *SearchService) localPages(ctx context.Context) ([]localPage, error) {
+	s.local.mu.Lock()
+	defer s.local.mu.Unlock()
+
+	if err := s.loadLocalSnapshot(ctx); err != nil {
+		return nil, err
+	}
+	return s.local.pages, nil
+}
+
+// pageTitles returns the titles of every page in the snapshot, keyed by
+// their lowercase form, and the lowercased titles of deactivated pages
+func (s *SearchService) pageTitles(ctx context.Context) (map[string]string, map[string]bool, error) {
+	s.local.mu.Lock()
+	defer s.local.mu.Unlock()
+
+	if err := s.loadLocalSnapshot(ctx); err != nil {
+		return nil, nil, err
+	}
+	return s.local.titles, s.local.inactive, nil
+}
+
+// ReloadPages drops the metadata snapshot so the next search reads page
+// state afresh, e.g. right after a page was deactivated
+func (s *SearchService) ReloadPages() {
+	s.local.mu.Lock()
+	defer s.local.mu.Unlock()
+	s.local.pages = nil
+	s.local.titles = nil
+	s.local.inactive = nil
+}
+
+// loadLocalSnapshot reloads the snapshot when old. Callers hold s.local.mu.
+func (s *SearchService) loadLocalSnapshot(ctx context.Context) (err error) {
+	if s.local.pages != nil && time.Since(s.local.loaded) < localSnapshotTTL {
+		return nil
+	}
+
+	_, span := tracing.Start(ctx, "SearchService.loadLocalPages")
+	defer func() { tracing.End(span, err) }()
+
+	contents, err := s.repoManager.ContentMetadata.GetActive()
+	if err != nil {
+		return fmt.Errorf("failed to load page metadata: %w", err)
+	}
+	all, err := s.repoManager.ContentMetadata.List()
+	if err != nil {
+		return fmt.Errorf("failed to load page metadata: %w", err)
+	}
+
+	pages := make([]localPage, 0, len(contents))
+	for _, content := range contents {
+		page := localPage{
+			name:  content.WikiPageTitle,
+			title: strings.ToLower(strings.ReplaceAll(content.WikiPageTitle, "_", " ")),
+			url:   content.PageURL,
+		}
+		for _, section := range content.Sections {
+			page.sections = append(page.sections, strings.ToLower(section.SectionTitle))
+			page.display = append(page.display, section.SectionTitle)
+		}
+		for _, pattern := range content.ErrorPatterns {
+			page.patterns = append(page.patterns, strings.ToLower(pattern))
+		}
+		pages = append(pages, page)
+	}
+
+	titles := make(map[string]string, len(all))
+	inactive := make(map[string]bool)
+	for _, content := range all {
+		titles[strings.ToLower(content.WikiPageTitle)] = content.WikiPageTitle
+		if !content.IsActive {
+			inactive[strings.ToLower(content.WikiPageTitle)] = true
+		}
+	}
+
+	s.local.pages = pages
+	s.local.titles = titles
+	s.local.inactive = inactive
+	s.local.loaded = time.Now()
+	return nil
+}
+
+// MergeResults combines backend and local results into one ranking. Backend
+// results win when both point at the same page; local matches for other
+// pages are kept below them by their lower score weight.
+func (s *SearchService) MergeResults(backend, local []models.SearchResult, limit int) []models.SearchResult {
+	merged := make([]models.SearchResult, 0, len(backend)+len(local))
+	seen := make(map[string]bool, len(backend))
+
+	for _, result := range backend {
+		merged = append(merged, result)
+		seen[strings.ToLower(result.Page)] = true
+	}
+	for _, result := range local {
+		if seen[strings.ToLower(result.Page)] {
+			continue
+		}
+		merged = append(merged, result)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Score > merged[j].Score })
+	limit = ClampLimit(limit)
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
@@ -0,0 +1,33 @@
+// backend/internal/services/risk.go
+package services
+
+import (
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/safety"
+)
+
+// annotateRisks attaches warnings for the risky commands in each result
+func (s *SearchService) annotateRisks(results []models.SearchResult) {
+	for i := range results {
+		for _, annotation := range s.classifier.Annotate(results[i].Content) {
+			if needsWarning(annotation) {
+				results[i].Warnings = append(results[i].Warnings, commandWarning(annotation))
+			}
+		}
+	}
+}
+
+// needsWarning reports whether a command should be called out: it can do
+// real damage, or the wiki itself warns about it
+func needsWarning(annotation safety.Annotation) bool {
+	return annotation.Level >= safety.Destructive || annotation.WikiWarning != ""
+}
+
+func commandWarning(annotation safety.Annotation) models.CommandWarning {
+	return models.CommandWarning{
+		Command:     annotation.Command,
+		Risk:        annotation.Level.String(),
+		Reasons:     annotation.Reasons,
+		WikiWarning: annotation.WikiWarning,
+	}
+}
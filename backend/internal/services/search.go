@@ -11,13 +11,21 @@ import (
 	"github.com/Ayash-Bera/ophelia/backend/internal/alchemyst"
 	"github.com/Ayash-Bera/ophelia/backend/internal/models"
 	"github.com/Ayash-Bera/ophelia/backend/internal/repository"
+	"github.com/Ayash-Bera/ophelia/backend/internal/safety"
+	"github.com/Ayash-Bera/ophelia/backend/internal/seeder"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"github.com/Ayash-Bera/ophelia/backend/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type SearchService struct {
 	alchemystService *alchemyst.Service
 	repoManager      *repository.RepositoryManager
 	logger           *logrus.Logger
+	local            localSnapshot
+	processor        *seeder.ContentProcessor
+	classifier       *safety.Classifier
 }
 
 var (
@@ -36,46 +44,78 @@ func NewSearchService(
 		alchemystService: alchemystService,
 		repoManager:      repoManager,
 		logger:           logger,
+		processor:        seeder.NewContentProcessor(),
+		classifier:       safety.NewClassifier(nil),
 	}
 }
 
-// SearchForSolution searches for solutions to the given error query
-func (s *SearchService) SearchForSolution(ctx context.Context, errorQuery string) ([]models.SearchResult, error) {
-	s.logger.WithField("query", errorQuery).Debug("Starting search for solution")
+// MaxSearchResults caps the results returned for one query
+const MaxSearchResults = 10
+
+// ClampLimit turns a requested result limit into one the service honours
+func ClampLimit(limit int) int {
+	if limit <= 0 || limit > MaxSearchResults {
+		return MaxSearchResults
+	}
+	return limit
+}
+
+// SearchForSolution searches for solutions to the given error query,
+// returning at most limit results
+func (s *SearchService) SearchForSolution(ctx context.Context, errorQuery string, limit int) (_ []models.SearchResult, err error) {
+	limit = ClampLimit(limit)
+	ctx, span := tracing.Start(ctx, "SearchService.SearchForSolution",
+		attribute.Int("search.query_length", len(errorQuery)),
+		attribute.Int("search.limit", limit),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	log := utils.LoggerFromContext(ctx, s.logger)
+
+	log.WithField("query", utils.RedactQuery(errorQuery)).Debug("Starting search for solution")
 
 	// Preprocess the query
-	processedQuery := s.preprocessQuery(errorQuery)
+	processedQuery := s.preprocessQuery(ctx, errorQuery)
 
 	// Search using Alchemyst Context API
 	alchemystResults, err := s.alchemystService.SearchForSolution(ctx, processedQuery)
 	if err != nil {
-		s.logger.WithError(err).Error("Alchemyst search failed")
+		log.WithError(err).Error("Alchemyst search failed")
 		return nil, fmt.Errorf("search service unavailable: %w", err)
 	}
 
-	s.logger.WithField("raw_results", len(alchemystResults)).Debug("Received results from Alchemyst")
+	log.WithField("raw_results", len(alchemystResults)).Debug("Received results from Alchemyst")
 
 	// Convert and enhance results
 	searchResults := s.convertAlchemystResults(alchemystResults)
+	searchResults = s.resolvePages(ctx, searchResults)
 
-	s.logger.WithField("original_query", errorQuery).Info("Original query")
-	s.logger.WithField("processed_query", processedQuery).Info("Processed query")
-	s.logger.WithField("alchemyst_raw_count", len(alchemystResults)).Info("Raw Alchemyst results")
-	s.logger.WithField("converted_count", len(searchResults)).Info("After conversion")
+	log.WithField("original_query", utils.RedactQuery(errorQuery)).Info("Original query")
+	log.WithField("processed_query", utils.RedactQuery(processedQuery)).Info("Processed query")
+	log.WithField("alchemyst_raw_count", len(alchemystResults)).Info("Raw Alchemyst results")
+	log.WithField("converted_count", len(searchResults)).Info("After conversion")
 
 	// TODO: Add result filtering and ranking in future iterations
-	// Limit results to top 10
-	if len(searchResults) > 10 {
-		searchResults = searchResults[:10]
+	if len(searchResults) > limit {
+		searchResults = searchResults[:limit]
 	}
+	s.annotateRisks(searchResults)
+
+	span.SetAttributes(
+		attribute.Int("search.raw_results", len(alchemystResults)),
+		attribute.Int("search.results", len(searchResults)),
+	)
 
-	s.logger.WithField("final_results", len(searchResults)).Debug("Search completed")
+	log.WithField("final_results", len(searchResults)).Debug("Search completed")
 
 	return searchResults, nil
 }
 
 // preprocessQuery cleans and enhances the search query
-func (s *SearchService) preprocessQuery(query string) string {
+func (s *SearchService) preprocessQuery(ctx context.Context, query string) string {
+	_, span := tracing.Start(ctx, "SearchService.preprocessQuery")
+	defer span.End()
+
 	// Remove common noise words that don't help with error searching
 	noiseWords := []string{
 		"please", "help", "how", "do", "i", "can", "you", "me", "my", "the", "a", "an",
@@ -98,9 +138,11 @@ func (s *SearchService) preprocessQuery(query string) string {
 
 	// If filtering removed too much, use original
 	if len(processed) < len(query)/3 {
+		span.SetAttributes(attribute.Bool("search.query_fallback", true))
 		return query
 	}
 
+	span.SetAttributes(attribute.Int("search.processed_length", len(processed)))
 	return processed
 }
 
@@ -129,6 +171,7 @@ func (s *SearchService) convertAlchemystResults(alchemystResults []alchemyst.Sea
 			Title:     title,
 			Content:   result.Text,
 			URL:       wikiURL,
+			Page:      pageName,
 			Score:     result.Score,
 			Relevance: s.determineRelevance(result.Score),
 		}
@@ -137,6 +180,42 @@ func (s *SearchService) convertAlchemystResults(alchemystResults []alchemyst.Sea
 	return results
 }
 
+// resolvePages sets each result's Page to the wiki page its document
+// belongs to and removes results from pages an operator deactivated. Their
+// documents are deleted from Alchemyst on deactivation, so this only catches
+// ones that outlived a failed or lagging delete.
+func (s *SearchService) resolvePages(ctx context.Context, results []models.SearchResult) []models.SearchResult {
+	titles, inactive, err := s.pageTitles(ctx)
+	if err != nil {
+		utils.LoggerFromContext(ctx, s.logger).WithError(err).Warn("Failed to load page titles, not filtering results")
+		return results
+	}
+
+	kept := results[:0]
+	for _, result := range results {
+		result.Page = documentPage(result.Page, titles)
+		if !inactive[strings.ToLower(result.Page)] {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// documentPage returns the title of the page a document belongs to, given
+// the known titles keyed by their lowercase form. Section documents are
+// named Page/Section and titles such as Pacman/Troubleshooting contain
+// slashes themselves, so the longest known title the name starts with
+// wins. Unknown names are returned as they are.
+func documentPage(name string, titles map[string]string) string {
+	lower := strings.ToLower(name)
+	for end := len(lower); end > 0; end = strings.LastIndexByte(lower[:end], '/') {
+		if title, ok := titles[lower[:end]]; ok {
+			return title
+		}
+	}
+	return name
+}
+
 // extractPageNameFromFilename extracts the page name from Alchemyst filename
 // Format: "PageName-timestamp-random.txt" -> "PageName"
 func (s *SearchService) extractPageNameFromFilename(filename string) string {
@@ -0,0 +1,321 @@
+// backend/internal/services/summary.go
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/safety"
+	"github.com/Ayash-Bera/ophelia/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Limits on what a solution summary shows
+const (
+	summarySources   = 3 // top results the summary is built from
+	summarySteps     = 8
+	summaryCommands  = 5 // per step
+	summaryFiles     = 10
+	summarySentences = 3
+)
+
+// Sentences shorter or longer than this are rarely useful on their own
+const (
+	minSentenceLength = 20
+	maxSentenceLength = 300
+)
+
+// Summarize builds an extractive solution summary from the top results:
+// ordered steps with the commands found in them, referenced files and the
+// sentences that best match the query. Nothing is generated, so the same
+// results always give the same summary. It returns nil when the results
+// contain nothing worth summarizing.
+func (s *SearchService) Summarize(ctx context.Context, query string, results []models.SearchResult) *models.SolutionSummary {
+	ctx, span := tracing.Start(ctx, "SearchService.Summarize",
+		attribute.Int("search.results", len(results)),
+	)
+	defer span.End()
+
+	var keywords []string
+	for _, keyword := range strings.Fields(strings.ToLower(s.preprocessQuery(ctx, query))) {
+		if len(keyword) >= 3 {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	summary := &models.SolutionSummary{
+		Steps:     []models.SolutionStep{},
+		Files:     []models.SolutionFile{},
+		Sentences: []models.SolutionSentence{},
+		Sources:   []models.SolutionSource{},
+	}
+	seenCommands := make(map[string]bool)
+	seenFiles := make(map[string]bool)
+	var candidates []sentenceCandidate
+
+	for _, result := range results {
+		if len(summary.Sources) == summarySources {
+			break
+		}
+		if strings.TrimSpace(result.Content) == "" {
+			continue
+		}
+
+		source := len(summary.Sources)
+		used := false
+
+		annotations := make(map[string]safety.Annotation)
+		for _, annotation := range s.classifier.Annotate(result.Content) {
+			annotations[annotation.Command] = annotation
+		}
+
+		for _, step := range s.extractSteps(result.Content, seenCommands, annotations) {
+			if len(summary.Steps) == summarySteps {
+				break
+			}
+			step.Source = source
+			summary.Steps = append(summary.Steps, step)
+			used = true
+		}
+
+		for _, path := range s.processor.ExtractFilePaths(result.Content) {
+			path = strings.TrimRight(path, ".,")
+			if seenFiles[path] || len(summary.Files) == summaryFiles {
+				continue
+			}
+			seenFiles[path] = true
+			summary.Files = append(summary.Files, models.SolutionFile{Path: path, Source: source})
+			used = true
+		}
+
+		for position, sentence := range proseSentences(result.Content, s.isCommandLine) {
+			if score := keywordHits(sentence, keywords); score > 0 {
+				candidates = append(candidates, sentenceCandidate{
+					text: sentence, source: source, position: position, score: score,
+				})
+				used = true
+			}
+		}
+
+		if used {
+			summary.Sources = append(summary.Sources, models.SolutionSource{
+				Title: result.Title,
+				URL:   result.URL,
+				Page:  result.Page,
+			})
+		}
+	}
+
+	// Best matches first; earlier sources and earlier sentences break ties
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		if candidates[i].source != candidates[j].source {
+			return candidates[i].source < candidates[j].source
+		}
+		return candidates[i].position < candidates[j].position
+	})
+	seenSentences := make(map[string]bool)
+	for _, candidate := range candidates {
+		if len(summary.Sentences) == summarySentences {
+			break
+		}
+		if seenSentences[candidate.text] {
+			continue
+		}
+		seenSentences[candidate.text] = true
+		summary.Sentences = append(summary.Sentences, models.SolutionSentence{
+			Text:   candidate.text,
+			Source: candidate.source,
+		})
+	}
+
+	// Sources are numbered as they are met, so drop any whose only
+	// contribution was a sentence that did not make the cut
+	summary = compactSources(summary)
+
+	span.SetAttributes(
+		attribute.Int("summary.steps", len(summary.Steps)),
+		attribute.Int("summary.files", len(summary.Files)),
+		attribute.Int("summary.sentences", len(summary.Sentences)),
+	)
+
+	if len(summary.Steps) == 0 && len(summary.Files) == 0 && len(summary.Sentences) == 0 {
+		return nil
+	}
+	return summary
+}
+
+type sentenceCandidate struct {
+	text     string
+	source   int
+	position int
+	score    int
+}
+
+// extractSteps walks a section line by line, grouping consecutive command
+// lines into one step introduced by the last sentence before them. Each
+// step is rated by its riskiest command. Commands already in seen are
+// skipped.
+func (s *SearchService) extractSteps(content string, seen map[string]bool, annotations map[string]safety.Annotation) []models.SolutionStep {
+	var steps []models.SolutionStep
+	var lead string
+	var current *models.SolutionStep
+	var currentLevel safety.Level
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || safety.IsWarning(line) {
+			// Warnings are attached to commands, not read as step text
+			continue
+		}
+
+		commands := s.processor.ExtractCommandExamples(line)
+		if len(commands) == 0 {
+			// Prose ends the current step and may introduce the next one
+			current = nil
+			if sentences := splitSentences(line); len(sentences) > 0 {
+				lead = sentences[len(sentences)-1]
+			}
+			continue
+		}
+
+		for _, command := range commands {
+			key := safety.NormalizeCommand(command)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if current == nil {
+				steps = append(steps, models.SolutionStep{Text: lead, Risk: safety.ReadOnly.String()})
+				current = &steps[len(steps)-1]
+				currentLevel = safety.ReadOnly
+				lead = ""
+			}
+			if len(current.Commands) == summaryCommands {
+				continue
+			}
+			current.Commands = append(current.Commands, key)
+
+			annotation, ok := annotations[key]
+			if !ok {
+				annotation = safety.Annotation{Assessment: s.classifier.Classify(key)}
+			}
+			if annotation.Level > currentLevel {
+				currentLevel = annotation.Level
+				current.Risk = currentLevel.String()
+			}
+			if needsWarning(annotation) {
+				current.Warnings = append(current.Warnings, commandWarning(annotation))
+			}
+		}
+	}
+	return steps
+}
+
+// isCommandLine reports whether a line is a shell command rather than prose
+func (s *SearchService) isCommandLine(line string) bool {
+	return len(s.processor.ExtractCommandExamples(line)) > 0
+}
+
+// proseSentences returns the sentences of content outside command lines, in
+// order
+func proseSentences(content string, isCommand func(string) bool) []string {
+	var sentences []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isCommand(line) {
+			continue
+		}
+		for _, sentence := range splitSentences(line) {
+			if len(sentence) >= minSentenceLength && len(sentence) <= maxSentenceLength {
+				sentences = append(sentences, sentence)
+			}
+		}
+	}
+	return sentences
+}
+
+// splitSentences breaks text after '.', '!', '?' or ':' followed by a space
+// and an upper-case letter or digit. File names and versions such as
+// pacman.conf or 6.1 stay whole because no space follows their dot.
+func splitSentences(text string) []string {
+	var sentences []string
+	runes := []rune(text)
+	start := 0
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.', '!', '?', ':':
+		default:
+			continue
+		}
+		if i+2 >= len(runes) || runes[i+1] != ' ' {
+			continue
+		}
+		if next := runes[i+2]; !unicode.IsUpper(next) && !unicode.IsDigit(next) {
+			continue
+		}
+		if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
+	}
+
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// keywordHits counts the distinct keywords that appear in sentence
+func keywordHits(sentence string, keywords []string) int {
+	lower := strings.ToLower(sentence)
+	hits := 0
+	for _, keyword := range keywords {
+		if strings.Contains(lower, keyword) {
+			hits++
+		}
+	}
+	return hits
+}
+
+// compactSources removes sources nothing cites and renumbers the citations
+func compactSources(summary *models.SolutionSummary) *models.SolutionSummary {
+	cited := make([]bool, len(summary.Sources))
+	for _, step := range summary.Steps {
+		cited[step.Source] = true
+	}
+	for _, file := range summary.Files {
+		cited[file.Source] = true
+	}
+	for _, sentence := range summary.Sentences {
+		cited[sentence.Source] = true
+	}
+
+	renumber := make([]int, len(summary.Sources))
+	sources := []models.SolutionSource{}
+	for i, source := range summary.Sources {
+		if cited[i] {
+			renumber[i] = len(sources)
+			sources = append(sources, source)
+		}
+	}
+	summary.Sources = sources
+
+	for i := range summary.Steps {
+		summary.Steps[i].Source = renumber[summary.Steps[i].Source]
+	}
+	for i := range summary.Files {
+		summary.Files[i].Source = renumber[summary.Files[i].Source]
+	}
+	for i := range summary.Sentences {
+		summary.Sentences[i].Source = renumber[summary.Sentences[i].Source]
+	}
+	return summary
+}
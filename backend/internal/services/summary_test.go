@@ -0,0 +1,322 @@
+// backend/internal/services/summary_test.go
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/Ayash-Bera/ophelia/backend/internal/safety"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestSearchService() *SearchService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewSearchService(nil, nil, logger)
+}
+
+func TestExtractSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		seen    []string
+		want    []models.SolutionStep
+	}{
+		{
+			name:    "no commands",
+			content: "Pacman keeps a lock file while it runs.",
+			want:    nil,
+		},
+		{
+			name:    "steps in page order with their lead sentence",
+			content: "Refresh the keyring first.\n# pacman -Sy archlinux-keyring\n# pacman -Su\nThen check the service.\n$ systemctl status foo",
+			want: []models.SolutionStep{
+				{
+					Text:     "Refresh the keyring first.",
+					Commands: []string{"pacman -Sy archlinux-keyring", "pacman -Su"},
+					Risk:     "destructive",
+					Warnings: []models.CommandWarning{{
+						Command: "pacman -Sy archlinux-keyring",
+						Risk:    "destructive",
+						Reasons: []string{"partial upgrade, unsupported on Arch"},
+					}},
+				},
+				{
+					Text:     "Then check the service.",
+					Commands: []string{"systemctl status foo"},
+					Risk:     "read_only",
+				},
+			},
+		},
+		{
+			name:    "lead is the last sentence before the commands",
+			content: "The upgrade failed. Clear the cache and retry.\n# pacman -Scc",
+			want: []models.SolutionStep{{
+				Text:     "Clear the cache and retry.",
+				Commands: []string{"pacman -Scc"},
+				Risk:     "destructive",
+				Warnings: []models.CommandWarning{{
+					Command: "pacman -Scc",
+					Risk:    "destructive",
+					Reasons: []string{"deletes cached packages needed for downgrades"},
+				}},
+			}},
+		},
+		{
+			name:    "prose ends a step",
+			content: "# pacman -Qi linux\nCompare the versions.\n# uname -r",
+			want: []models.SolutionStep{
+				{Commands: []string{"pacman -Qi linux"}, Risk: "read_only"},
+				{Text: "Compare the versions.", Commands: []string{"uname -r"}, Risk: "read_only"},
+			},
+		},
+		{
+			name:    "duplicate and already seen commands skipped",
+			content: "Upgrade everything.\n# pacman -Syu\n#   pacman   -Syu\nReboot now.\n# reboot\n# reboot",
+			seen:    []string{"pacman -Syu"},
+			want: []models.SolutionStep{{
+				Text:     "Reboot now.",
+				Commands: []string{"reboot"},
+				Risk:     "modifies_system",
+			}},
+		},
+		{
+			name:    "wiki warning attached, not read as text",
+			content: "Warning: Only remove the lock when pacman is not running.\n# rm /var/lib/pacman/db.lck",
+			want: []models.SolutionStep{{
+				Commands: []string{"rm /var/lib/pacman/db.lck"},
+				Risk:     "destructive",
+				Warnings: []models.CommandWarning{{
+					Command:     "rm /var/lib/pacman/db.lck",
+					Risk:        "destructive",
+					Reasons:     []string{"deletes files"},
+					WikiWarning: "Only remove the lock when pacman is not running.",
+				}},
+			}},
+		},
+		{
+			name:    "commands per step capped",
+			content: "Inspect the packages.\n$ pacman -Qi a\n$ pacman -Qi b\n$ pacman -Qi c\n$ pacman -Qi d\n$ pacman -Qi e\n$ pacman -Qi f",
+			want: []models.SolutionStep{{
+				Text:     "Inspect the packages.",
+				Commands: []string{"pacman -Qi a", "pacman -Qi b", "pacman -Qi c", "pacman -Qi d", "pacman -Qi e"},
+				Risk:     "read_only",
+			}},
+		},
+	}
+
+	s := newTestSearchService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[string]bool)
+			for _, command := range tt.seen {
+				seen[command] = true
+			}
+			annotations := make(map[string]safety.Annotation)
+			for _, annotation := range s.classifier.Annotate(tt.content) {
+				annotations[annotation.Command] = annotation
+			}
+
+			got := s.extractSteps(tt.content, seen, annotations)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractSteps\n got  %#v\n want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactSources(t *testing.T) {
+	sources := []models.SolutionSource{{Title: "A"}, {Title: "B"}, {Title: "C"}}
+
+	tests := []struct {
+		name    string
+		summary *models.SolutionSummary
+		want    *models.SolutionSummary
+	}{
+		{
+			name: "every source cited",
+			summary: &models.SolutionSummary{
+				Steps:   []models.SolutionStep{{Source: 0}, {Source: 2}},
+				Files:   []models.SolutionFile{{Path: "/etc/x", Source: 1}},
+				Sources: sources,
+			},
+			want: &models.SolutionSummary{
+				Steps:   []models.SolutionStep{{Source: 0}, {Source: 2}},
+				Files:   []models.SolutionFile{{Path: "/etc/x", Source: 1}},
+				Sources: sources,
+			},
+		},
+		{
+			name: "uncited source dropped and later ones renumbered",
+			summary: &models.SolutionSummary{
+				Steps:     []models.SolutionStep{{Source: 2}},
+				Files:     []models.SolutionFile{{Path: "/etc/x", Source: 0}},
+				Sentences: []models.SolutionSentence{{Text: "s", Source: 2}},
+				Sources:   sources,
+			},
+			want: &models.SolutionSummary{
+				Steps:     []models.SolutionStep{{Source: 1}},
+				Files:     []models.SolutionFile{{Path: "/etc/x", Source: 0}},
+				Sentences: []models.SolutionSentence{{Text: "s", Source: 1}},
+				Sources:   []models.SolutionSource{{Title: "A"}, {Title: "C"}},
+			},
+		},
+		{
+			name: "only a sentence cites the last source",
+			summary: &models.SolutionSummary{
+				Sentences: []models.SolutionSentence{{Text: "s", Source: 2}},
+				Sources:   sources,
+			},
+			want: &models.SolutionSummary{
+				Sentences: []models.SolutionSentence{{Text: "s", Source: 0}},
+				Sources:   []models.SolutionSource{{Title: "C"}},
+			},
+		},
+		{
+			name:    "nothing cited",
+			summary: &models.SolutionSummary{Sources: sources},
+			want:    &models.SolutionSummary{Sources: []models.SolutionSource{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compactSources(tt.summary); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compactSources\n got  %#v\n want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// summaryResults are search results for "pacman database lock error". The
+// first has no content and the last only offers a sentence that loses to
+// earlier ones, so neither ends up a source.
+var summaryResults = []models.SearchResult{
+	{Title: "Empty", URL: "https://wiki.archlinux.org/title/Empty", Page: "Empty", Content: "  "},
+	{
+		Title: "Pacman#Failed to init transaction",
+		URL:   "https://wiki.archlinux.org/title/Pacman#Failed_to_init_transaction",
+		Page:  "Pacman",
+		Content: "If pacman says the database lock file exists, make sure no other pacman is running.\n" +
+			"Warning: Only remove the lock when no package manager is running.\n" +
+			"# rm /var/lib/pacman/db.lck\n" +
+			"Then retry the upgrade.\n" +
+			"# pacman -Syu",
+	},
+	{
+		Title: "Pacman/Tips and tricks",
+		URL:   "https://wiki.archlinux.org/title/Pacman/Tips_and_tricks",
+		Page:  "Pacman/Tips and tricks",
+		Content: "A stale lock is left behind when pacman is interrupted mid transaction.\n" +
+			"# pacman -Syu\n" +
+			"Rebuild the database with pacman-db-upgrade when the lock error persists.\n" +
+			"$ pacman-db-upgrade",
+	},
+	{
+		Title:   "Mirrors",
+		URL:     "https://wiki.archlinux.org/title/Mirrors",
+		Page:    "Mirrors",
+		Content: "Mirrors can also cause errors while syncing the database.",
+	},
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		results []models.SearchResult
+		want    *models.SolutionSummary
+	}{
+		{
+			name:    "no results",
+			query:   "pacman database lock error",
+			results: nil,
+			want:    nil,
+		},
+		{
+			name:    "nothing worth summarizing",
+			query:   "pacman database lock error",
+			results: []models.SearchResult{{Title: "Short", Content: "Too short."}},
+			want:    nil,
+		},
+		{
+			name:    "steps, files and sentences from the top results",
+			query:   "pacman database lock error",
+			results: summaryResults,
+			want: &models.SolutionSummary{
+				Steps: []models.SolutionStep{
+					{
+						Text:     "If pacman says the database lock file exists, make sure no other pacman is running.",
+						Commands: []string{"rm /var/lib/pacman/db.lck"},
+						Risk:     "destructive",
+						Warnings: []models.CommandWarning{{
+							Command:     "rm /var/lib/pacman/db.lck",
+							Risk:        "destructive",
+							Reasons:     []string{"deletes files"},
+							WikiWarning: "Only remove the lock when no package manager is running.",
+						}},
+						Source: 0,
+					},
+					{
+						Text:     "Then retry the upgrade.",
+						Commands: []string{"pacman -Syu"},
+						Risk:     "modifies_system",
+						Source:   0,
+					},
+					{
+						// pacman -Syu was already a step, so only the new
+						// command is taken from the second source
+						Text:     "Rebuild the database with pacman-db-upgrade when the lock error persists.",
+						Commands: []string{"pacman-db-upgrade"},
+						Risk:     "modifies_system",
+						Source:   1,
+					},
+				},
+				Files: []models.SolutionFile{{Path: "/var/lib/pacman/db.lck", Source: 0}},
+				Sentences: []models.SolutionSentence{
+					{Text: "Rebuild the database with pacman-db-upgrade when the lock error persists.", Source: 1},
+					{Text: "If pacman says the database lock file exists, make sure no other pacman is running.", Source: 0},
+					{Text: "A stale lock is left behind when pacman is interrupted mid transaction.", Source: 1},
+				},
+				Sources: []models.SolutionSource{
+					{Title: summaryResults[1].Title, URL: summaryResults[1].URL, Page: summaryResults[1].Page},
+					{Title: summaryResults[2].Title, URL: summaryResults[2].URL, Page: summaryResults[2].Page},
+				},
+			},
+		},
+	}
+
+	s := newTestSearchService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Summarize(context.Background(), tt.query, tt.results)
+			if !reflect.DeepEqual(got, tt.want) {
+				gotJSON, _ := json.MarshalIndent(got, "", "  ")
+				wantJSON, _ := json.MarshalIndent(tt.want, "", "  ")
+				t.Errorf("Summarize\n got  %s\n want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestSummarizeIsDeterministic(t *testing.T) {
+	s := newTestSearchService()
+	first, err := json.Marshal(s.Summarize(context.Background(), "pacman database lock error", summaryResults))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		// A fresh service each time, so nothing carries over between runs
+		next, err := json.Marshal(newTestSearchService().Summarize(context.Background(), "pacman database lock error", summaryResults))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(next) != string(first) {
+			t.Fatalf("run %d differs\n got  %s\n want %s", i+1, next, first)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// backend/internal/services/usage.go
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// usageKey identifies one api_key_usage row
+type usageKey struct {
+	keyID uint
+	day   string
+	route string
+}
+
+// UsageRecorder collects API key usage in memory and adds it to the
+// api_key_usage table on an interval, so requests never wait on the write
+type UsageRecorder struct {
+	keys     models.APIKeyRepository
+	usage    models.APIKeyUsageRepository
+	interval time.Duration
+	logger   *logrus.Logger
+
+	mu      sync.Mutex
+	pending map[usageKey]*models.APIKeyUsageDelta
+	used    map[uint]time.Time // last request per key since the last flush
+}
+
+func NewUsageRecorder(keys models.APIKeyRepository, usage models.APIKeyUsageRepository, interval time.Duration, logger *logrus.Logger) *UsageRecorder {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &UsageRecorder{
+		keys:     keys,
+		usage:    usage,
+		interval: interval,
+		logger:   logger,
+		pending:  make(map[usageKey]*models.APIKeyUsageDelta),
+		used:     make(map[uint]time.Time),
+	}
+}
+
+// Record counts one request; it satisfies middleware.UsageRecorder
+func (r *UsageRecorder) Record(keyID uint, route string, units int, failed bool) {
+	now := time.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	k := usageKey{keyID: keyID, day: day.Format("2006-01-02"), route: route}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delta, ok := r.pending[k]
+	if !ok {
+		delta = &models.APIKeyUsageDelta{APIKeyID: keyID, Day: day, Route: route}
+		r.pending[k] = delta
+	}
+	delta.Requests++
+	delta.Units += units
+	if failed {
+		delta.Errors++
+	}
+	r.used[keyID] = now
+}
+
+// Start flushes on every interval until ctx is done. Call Flush once
+// more after the server stops taking requests.
+func (r *UsageRecorder) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Flush(ctx)
+		}
+	}
+}
+
+// Flush writes the usage collected since the last flush. Usage that fails
+// to write is kept and retried on the next flush.
+func (r *UsageRecorder) Flush(ctx context.Context) {
+	r.mu.Lock()
+	pending, used := r.pending, r.used
+	r.pending = make(map[usageKey]*models.APIKeyUsageDelta)
+	r.used = make(map[uint]time.Time)
+	r.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	deltas := make([]models.APIKeyUsageDelta, 0, len(pending))
+	for _, delta := range pending {
+		deltas = append(deltas, *delta)
+	}
+	if err := r.usage.Add(ctx, deltas); err != nil {
+		r.logger.WithError(err).WithField("rows", len(deltas)).Error("Failed to record API key usage")
+		r.restore(pending, used)
+		return
+	}
+
+	// Keys used since the last flush share its latest time, which is
+	// accurate to within the flush interval
+	var latest time.Time
+	ids := make([]uint, 0, len(used))
+	for id, at := range used {
+		ids = append(ids, id)
+		if at.After(latest) {
+			latest = at
+		}
+	}
+	if err := r.keys.TouchLastUsed(ctx, ids, latest); err != nil {
+		r.logger.WithError(err).Warn("Failed to update API key last use")
+	}
+}
+
+// restore merges usage from a failed flush back into the pending counts
+func (r *UsageRecorder) restore(pending map[usageKey]*models.APIKeyUsageDelta, used map[uint]time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, delta := range pending {
+		if current, ok := r.pending[k]; ok {
+			current.Requests += delta.Requests
+			current.Units += delta.Units
+			current.Errors += delta.Errors
+			continue
+		}
+		r.pending[k] = delta
+	}
+	for id, at := range used {
+		if at.After(r.used[id]) {
+			r.used[id] = at
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// backend/internal/services/warmer.go
+package services
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/database"
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// warmerMetrics tracks warming runs and is published at /api/v1/admin/debug/vars
+var warmerMetrics = expvar.NewMap("cache_warmer")
+
+// WarmerConfig controls which queries are warmed and how fast
+type WarmerConfig struct {
+	TopN         int           // popular queries replayed per run
+	Rate         float64       // upstream searches per second
+	PollInterval time.Duration // how often to check whether content changed
+	OnStart      bool          // warm once when the server starts
+}
+
+// CacheWarmer replays the most popular queries into the search cache after
+// content changes, so the first users after a re-seed do not pay the full
+// upstream latency
+type CacheWarmer struct {
+	searchService *SearchService
+	searchCache   *database.SearchCache
+	cache         *database.Cache
+	popular       models.PopularQueryRepository
+	cfg           WarmerConfig
+	logger        *logrus.Logger
+}
+
+// WarmResult summarizes one warming run
+type WarmResult struct {
+	Queries  int           // popular queries considered
+	Warmed   int           // searches run to fill or refresh an entry
+	Fresh    int           // entries that were already fresh
+	Failed   int           // searches that failed
+	Duration time.Duration // wall time of the run
+}
+
+func NewCacheWarmer(
+	searchService *SearchService,
+	searchCache *database.SearchCache,
+	cache *database.Cache,
+	popular models.PopularQueryRepository,
+	cfg WarmerConfig,
+	logger *logrus.Logger,
+) *CacheWarmer {
+	if cfg.TopN <= 0 {
+		cfg.TopN = 50
+	}
+	if cfg.Rate <= 0 {
+		cfg.Rate = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+
+	return &CacheWarmer{
+		searchService: searchService,
+		searchCache:   searchCache,
+		cache:         cache,
+		popular:       popular,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// Start warms on startup if configured, then re-warms whenever the content
+// version recorded by the seeder changes, until ctx is done
+func (w *CacheWarmer) Start(ctx context.Context) {
+	lastVersion, err := w.cache.ContentVersion(ctx)
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to read content version")
+	}
+
+	if w.cfg.OnStart {
+		w.Run(ctx, "startup")
+	}
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			version, err := w.cache.ContentVersion(ctx)
+			if err != nil {
+				w.logger.WithError(err).Debug("Failed to read content version")
+				continue
+			}
+			if version != lastVersion {
+				lastVersion = version
+				w.Run(ctx, "content_changed")
+			}
+		}
+	}
+}
+
+// Run replays the top queries into the cache, pacing upstream searches to
+// the configured rate. Queries whose entries are still fresh cost nothing.
+func (w *CacheWarmer) Run(ctx context.Context, reason string) WarmResult {
+	start := time.Now()
+	log := w.logger.WithField("reason", reason)
+	warmerMetrics.Add("runs", 1)
+
+	queries, err := w.popular.GetTop(w.cfg.TopN)
+	if err != nil {
+		log.WithError(err).Error("Failed to load popular queries for cache warming")
+		warmerMetrics.Add("run_failures", 1)
+		return WarmResult{}
+	}
+
+	log.WithField("queries", len(queries)).Info("Cache warming started")
+
+	result := WarmResult{Queries: len(queries)}
+	interval := time.Duration(float64(time.Second) / w.cfg.Rate)
+	var lastSearch time.Time
+
+	for i, q := range queries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		limit := ClampLimit(0)
+		key := database.SearchCacheKey(q.QueryText, limit)
+
+		// Wait for our turn only when the previous query actually searched
+		if !lastSearch.IsZero() {
+			if wait := interval - time.Since(lastSearch); wait > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		query := q.QueryText
+		searched, err := w.searchCache.Warm(ctx, key, func(ctx context.Context) ([]models.SearchResult, error) {
+			return w.searchService.SearchForSolution(ctx, query, limit)
+		})
+		switch {
+		case err != nil:
+			result.Failed++
+			lastSearch = time.Now()
+			log.WithError(err).WithField("position", i+1).Warn("Failed to warm query")
+		case searched:
+			result.Warmed++
+			lastSearch = time.Now()
+		default:
+			result.Fresh++
+		}
+
+		if (i+1)%10 == 0 {
+			log.WithFields(logrus.Fields{
+				"progress": i + 1,
+				"total":    len(queries),
+				"warmed":   result.Warmed,
+			}).Debug("Cache warming progress")
+		}
+	}
+
+	result.Duration = time.Since(start)
+
+	warmerMetrics.Add("queries_warmed", int64(result.Warmed))
+	warmerMetrics.Add("queries_fresh", int64(result.Fresh))
+	warmerMetrics.Add("queries_failed", int64(result.Failed))
+	lastRun := new(expvar.Int)
+	lastRun.Set(result.Duration.Milliseconds())
+	warmerMetrics.Set("last_run_ms", lastRun)
+
+	// The share of entries that were already fresh is the hit rate the
+	// first users would have seen without this run
+	hitRateBefore, hitRateAfter := 0.0, 0.0
+	if result.Queries > 0 {
+		hitRateBefore = float64(result.Fresh) / float64(result.Queries)
+		hitRateAfter = float64(result.Fresh+result.Warmed) / float64(result.Queries)
+	}
+
+	log.WithFields(logrus.Fields{
+		"queries":         result.Queries,
+		"warmed":          result.Warmed,
+		"already_fresh":   result.Fresh,
+		"failed":          result.Failed,
+		"hit_rate_before": hitRateBefore,
+		"hit_rate_after":  hitRateAfter,
+		"duration_ms":     result.Duration.Milliseconds(),
+	}).Info("Cache warming completed")
+
+	return result
+}
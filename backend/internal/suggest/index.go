@@ -0,0 +1,421 @@
+// backend/internal/suggest/index.go
+package suggest
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Suggestion sources
+const (
+	TypeQuery   = "query"   // a previously searched query
+	TypePage    = "page"    // an indexed wiki page title
+	TypeSection = "section" // a section heading within a wiki page
+	TypeError   = "error"   // an error pattern extracted from wiki content
+)
+
+// Suggestion is a single autocomplete candidate
+type Suggestion struct {
+	Text         string    `json:"query_text"`
+	Type         string    `json:"type"`
+	URL          string    `json:"url,omitempty"`
+	Page         string    `json:"page,omitempty"`
+	Count        int       `json:"search_count"`
+	LastSearched time.Time `json:"last_searched"`
+	Score        float64   `json:"score"`
+	Fuzzy        bool      `json:"fuzzy,omitempty"`
+}
+
+// Entry is a suggestion source known to the index, e.g. a row from
+// popular_queries or a wiki page title
+type Entry struct {
+	Text     string
+	Type     string // one of the Type* constants, defaults to TypeQuery
+	URL      string // target for page and section entries
+	Page     string // owning page title for section entries
+	Count    int
+	LastSeen time.Time
+}
+
+// Options tunes the index size and ranking
+type Options struct {
+	MaxKeyLength  int           // runes indexed per key
+	MaxWordStarts int           // word positions indexed per entry, so "fail" also matches "unit failed"
+	TopK          int           // candidates kept per trie node
+	HalfLife      time.Duration // recency half-life used in ranking
+	MaxQueries    int           // query entries kept; Record skips new queries beyond it, 0 is unlimited
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MaxKeyLength:  48,
+		MaxWordStarts: 6,
+		TopK:          16,
+		HalfLife:      7 * 24 * time.Hour,
+	}
+}
+
+// Index is an in-memory prefix trie over known queries and wiki content. Every node keeps the
+// best-ranked entries below it, so a lookup is a walk down the trie plus a
+// small sort and never scans the whole subtree.
+type Index struct {
+	mu      sync.RWMutex
+	opts    Options
+	nodes   []node
+	entries []entry
+	byKey   map[string]int32
+	queries int // entries of TypeQuery
+}
+
+type node struct {
+	children []edge // sorted by rune
+	top      []int32
+}
+
+type edge struct {
+	r     rune
+	child int32
+}
+
+type entry struct {
+	text     string
+	typ      string
+	url      string
+	page     string
+	count    int
+	lastSeen time.Time
+	rank     float64 // score when the entry last changed, used to order node candidates
+}
+
+func NewIndex(opts Options) *Index {
+	defaults := DefaultOptions()
+	if opts.MaxKeyLength <= 0 {
+		opts.MaxKeyLength = defaults.MaxKeyLength
+	}
+	if opts.MaxWordStarts <= 0 {
+		opts.MaxWordStarts = defaults.MaxWordStarts
+	}
+	if opts.TopK <= 0 {
+		opts.TopK = defaults.TopK
+	}
+	if opts.HalfLife <= 0 {
+		opts.HalfLife = defaults.HalfLife
+	}
+
+	return &Index{
+		opts:  opts,
+		nodes: []node{{}},
+		byKey: make(map[string]int32),
+	}
+}
+
+// Len returns the number of distinct entries in the index
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Load replaces the index contents with the given entries
+func (idx *Index) Load(entries []Entry) {
+	fresh := NewIndex(idx.opts)
+	now := time.Now()
+	for _, e := range entries {
+		fresh.add(e, now)
+	}
+
+	idx.mu.Lock()
+	idx.nodes = fresh.nodes
+	idx.entries = fresh.entries
+	idx.byKey = fresh.byKey
+	idx.queries = fresh.queries
+	idx.mu.Unlock()
+}
+
+// Record notes that a query was just searched. Once MaxQueries queries are
+// known, only those are counted until the next Load.
+func (idx *Index) Record(text string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.opts.MaxQueries > 0 && idx.queries >= idx.opts.MaxQueries {
+		if _, known := idx.byKey[TypeQuery+"\x00"+Normalize(text)]; !known {
+			return
+		}
+	}
+	now := time.Now()
+	idx.add(Entry{Text: text, Type: TypeQuery, Count: 1, LastSeen: now}, now)
+}
+
+// add inserts or bumps an entry; callers hold the write lock. The same text
+// may appear once per type, so a page title and an identical query are
+// offered separately.
+func (idx *Index) add(e Entry, now time.Time) {
+	norm := Normalize(e.Text)
+	if norm == "" {
+		return
+	}
+	if e.Type == "" {
+		e.Type = TypeQuery
+	}
+
+	key := e.Type + "\x00" + norm
+	id, exists := idx.byKey[key]
+	if exists {
+		existing := &idx.entries[id]
+		existing.count += e.Count
+		if e.LastSeen.After(existing.lastSeen) {
+			existing.lastSeen = e.LastSeen
+		}
+	} else {
+		id = int32(len(idx.entries))
+		idx.entries = append(idx.entries, entry{
+			text:     strings.TrimSpace(e.Text),
+			typ:      e.Type,
+			url:      e.URL,
+			page:     e.Page,
+			count:    e.Count,
+			lastSeen: e.LastSeen,
+		})
+		idx.byKey[key] = id
+		if e.Type == TypeQuery {
+			idx.queries++
+		}
+	}
+	idx.entries[id].rank = idx.score(id, now)
+
+	for _, key := range idx.keys(norm) {
+		current := int32(0)
+		for _, r := range key {
+			current = idx.child(current, r, true)
+			idx.promote(current, id)
+		}
+	}
+}
+
+// keys returns the strings indexed for an entry: the text itself plus the
+// text starting at each following word, truncated to MaxKeyLength runes
+func (idx *Index) keys(norm string) [][]rune {
+	runes := []rune(norm)
+	var keys [][]rune
+	for i := 0; i < len(runes) && len(keys) < idx.opts.MaxWordStarts; i++ {
+		if i > 0 && runes[i-1] != ' ' {
+			continue
+		}
+		end := i + idx.opts.MaxKeyLength
+		if end > len(runes) {
+			end = len(runes)
+		}
+		keys = append(keys, runes[i:end])
+	}
+	return keys
+}
+
+// child returns the child of n for r, creating it when asked to
+func (idx *Index) child(n int32, r rune, create bool) int32 {
+	children := idx.nodes[n].children
+	i := sort.Search(len(children), func(i int) bool { return children[i].r >= r })
+	if i < len(children) && children[i].r == r {
+		return children[i].child
+	}
+	if !create {
+		return -1
+	}
+
+	childID := int32(len(idx.nodes))
+	idx.nodes = append(idx.nodes, node{})
+
+	children = append(children, edge{})
+	copy(children[i+1:], children[i:])
+	children[i] = edge{r: r, child: childID}
+	idx.nodes[n].children = children
+	return childID
+}
+
+// promote keeps id among the TopK best entries of node n, ordered by rank
+func (idx *Index) promote(n int32, id int32) {
+	top := idx.nodes[n].top
+	pos := -1
+	for i, existing := range top {
+		if existing == id {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		if len(top) < idx.opts.TopK {
+			top = append(top, id)
+			pos = len(top) - 1
+		} else if idx.entries[id].rank > idx.entries[top[len(top)-1]].rank {
+			pos = len(top) - 1
+			top[pos] = id
+		} else {
+			return
+		}
+	}
+
+	// The entry's rank only grows, so bubble it towards the front
+	rank := idx.entries[id].rank
+	for pos > 0 && idx.entries[top[pos-1]].rank < rank {
+		top[pos], top[pos-1] = top[pos-1], top[pos]
+		pos--
+	}
+	idx.nodes[n].top = top
+}
+
+// score ranks an entry by frequency, decayed by how long ago it was searched
+func (idx *Index) score(id int32, now time.Time) float64 {
+	e := idx.entries[id]
+	age := now.Sub(e.lastSeen)
+	if age < 0 {
+		age = 0
+	}
+	recency := math.Pow(0.5, float64(age)/float64(idx.opts.HalfLife))
+	return math.Log1p(float64(e.count)) * (0.3 + 0.7*recency)
+}
+
+// Suggest returns up to limit completions for prefix. Exact prefix matches
+// rank first; when they are not enough, completions within a small edit
+// distance of the prefix fill the rest.
+func (idx *Index) Suggest(prefix string, limit int) []Suggestion {
+	query := []rune(Normalize(prefix))
+	if len(query) == 0 || limit <= 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	now := time.Now()
+	candidates := make(map[int32]int) // entry -> edit distance
+
+	current := int32(0)
+	for _, r := range query {
+		current = idx.child(current, r, false)
+		if current < 0 {
+			break
+		}
+	}
+	if current > 0 {
+		for _, id := range idx.nodes[current].top {
+			candidates[id] = 0
+		}
+	}
+
+	if len(candidates) < limit {
+		if maxEdits := maxEditsFor(len(query)); maxEdits > 0 {
+			idx.fuzzy(query, maxEdits, candidates)
+		}
+	}
+
+	suggestions := make([]Suggestion, 0, len(candidates))
+	for id, distance := range candidates {
+		e := idx.entries[id]
+		suggestions = append(suggestions, Suggestion{
+			Text:         e.text,
+			Type:         e.typ,
+			URL:          e.url,
+			Page:         e.page,
+			Count:        e.count,
+			LastSearched: e.lastSeen,
+			Score:        idx.score(id, now) * math.Pow(0.5, float64(distance)),
+			Fuzzy:        distance > 0,
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// maxEditsFor allows more typos as the typed prefix grows
+func maxEditsFor(length int) int {
+	switch {
+	case length < 3:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fuzzy walks the trie with a Levenshtein row per node and collects the top
+// entries of every node whose path is within maxEdits of the query, each at
+// the smallest distance found
+func (idx *Index) fuzzy(query []rune, maxEdits int, candidates map[int32]int) {
+	row := make([]int, len(query)+1)
+	for i := range row {
+		row[i] = i
+	}
+
+	var walk func(n int32, r rune, prev []int)
+	walk = func(n int32, r rune, prev []int) {
+		cur := make([]int, len(query)+1)
+		cur[0] = prev[0] + 1
+		best := cur[0]
+		for i := 1; i <= len(query); i++ {
+			cost := 1
+			if query[i-1] == r {
+				cost = 0
+			}
+			cur[i] = min(cur[i-1]+1, prev[i]+1, prev[i-1]+cost)
+			if cur[i] < best {
+				best = cur[i]
+			}
+		}
+
+		distance := cur[len(query)]
+		if distance <= maxEdits {
+			for _, id := range idx.nodes[n].top {
+				if existing, ok := candidates[id]; !ok || distance < existing {
+					candidates[id] = distance
+				}
+			}
+		}
+
+		// Go deeper only while a longer path could still come closer, so
+		// "pacman keyt" reaches "pacman key" rather than stopping at
+		// "pacman ke"
+		if best > maxEdits || best >= distance {
+			return
+		}
+		for _, e := range idx.nodes[n].children {
+			walk(e.child, e.r, cur)
+		}
+	}
+
+	for _, e := range idx.nodes[0].children {
+		walk(e.child, e.r, row)
+	}
+}
+
+// Normalize lowercases text and collapses whitespace so that keys and
+// lookups agree
+func Normalize(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.TrimSpace(text) {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
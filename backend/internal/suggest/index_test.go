@@ -0,0 +1,414 @@
+// backend/internal/suggest/index_test.go
+package suggest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// indexFixture mixes queries and pages of different popularity and age
+func indexFixture(now time.Time) []Entry {
+	return []Entry{
+		{Text: "pacman failed to commit transaction", Count: 50, LastSeen: now},
+		{Text: "pacman keyring is corrupted", Count: 20, LastSeen: now},
+		{Text: "Pacman", Type: TypePage, URL: "https://wiki.archlinux.org/title/Pacman", Count: 8, LastSeen: now},
+		{Text: "Pacman/Tips and tricks", Type: TypePage, Count: 8, LastSeen: now.Add(-30 * 24 * time.Hour)},
+		{Text: "systemd unit failed to start", Count: 5, LastSeen: now},
+		{Text: "nvidia driver black screen", Count: 30, LastSeen: now.Add(-60 * 24 * time.Hour)},
+		{Text: "nvidia suspend", Count: 4, LastSeen: now},
+	}
+}
+
+// texts lists suggestions in order, marking fuzzy ones with a trailing ~
+func texts(suggestions []Suggestion) []string {
+	var out []string
+	for _, s := range suggestions {
+		text := s.Text
+		if s.Fuzzy {
+			text += "~"
+		}
+		out = append(out, text)
+	}
+	return out
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{
+			name:   "empty prefix",
+			prefix: "  ",
+			limit:  10,
+			want:   nil,
+		},
+		{
+			name:   "zero limit",
+			prefix: "pac",
+			limit:  0,
+			want:   nil,
+		},
+		{
+			name:   "ranked by count",
+			prefix: "pac",
+			limit:  10,
+			want: []string{
+				"pacman failed to commit transaction",
+				"pacman keyring is corrupted",
+				"Pacman",
+				"Pacman/Tips and tricks",
+			},
+		},
+		{
+			name:   "limit keeps the best",
+			prefix: "pac",
+			limit:  2,
+			want:   []string{"pacman failed to commit transaction", "pacman keyring is corrupted"},
+		},
+		{
+			name:   "case and spacing ignored",
+			prefix: "PACMAN   Failed",
+			limit:  10,
+			want:   []string{"pacman failed to commit transaction"},
+		},
+		{
+			name:   "matches at word starts",
+			prefix: "fail",
+			limit:  10,
+			want:   []string{"pacman failed to commit transaction", "systemd unit failed to start"},
+		},
+		{
+			name:   "recent entry outranks an old popular one",
+			prefix: "nvidia",
+			limit:  10,
+			want:   []string{"nvidia suspend", "nvidia driver black screen"},
+		},
+		{
+			name:   "no typos allowed in short prefixes",
+			prefix: "pz",
+			limit:  10,
+			want:   nil,
+		},
+		{
+			name:   "one typo allowed from three runes",
+			prefix: "pacmen",
+			limit:  10,
+			want: []string{
+				"pacman failed to commit transaction~",
+				"pacman keyring is corrupted~",
+				"Pacman~",
+				"Pacman/Tips and tricks~",
+			},
+		},
+		{
+			name:   "two typos allowed from eight runes",
+			prefix: "systemf unot",
+			limit:  10,
+			want:   []string{"systemd unit failed to start~"},
+		},
+		{
+			name:   "too many typos",
+			prefix: "pxcmxn",
+			limit:  10,
+			want:   nil,
+		},
+		{
+			name:   "exact matches before fuzzy ones",
+			prefix: "nvidia s",
+			limit:  10,
+			want:   []string{"nvidia suspend", "nvidia driver black screen~"},
+		},
+		{
+			name:   "fuzzy not needed when exact fill the limit",
+			prefix: "nvidia s",
+			limit:  1,
+			want:   []string{"nvidia suspend"},
+		},
+		{
+			name:   "unknown prefix",
+			prefix: "xyzzy",
+			limit:  10,
+			want:   nil,
+		},
+	}
+
+	idx := NewIndex(DefaultOptions())
+	idx.Load(indexFixture(time.Now()))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := texts(idx.Suggest(tt.prefix, tt.limit))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q, %d)\n got  %q\n want %q", tt.prefix, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestFuzzyScoreHalvedPerEdit(t *testing.T) {
+	idx := NewIndex(DefaultOptions())
+	idx.Load([]Entry{{Text: "pacman keyring is corrupted", Count: 20, LastSeen: time.Now()}})
+
+	exact := idx.Suggest("pacman keyr", 1)
+	fuzzy := idx.Suggest("pacman keyt", 1)
+	twice := idx.Suggest("pacman kayt", 1)
+	if len(exact) != 1 || len(fuzzy) != 1 || exact[0].Fuzzy || !fuzzy[0].Fuzzy {
+		t.Fatalf("got %q and %q, want the entry exact then fuzzy", texts(exact), texts(fuzzy))
+	}
+	if ratio := fuzzy[0].Score / exact[0].Score; ratio < 0.49 || ratio > 0.51 {
+		t.Errorf("score with one edit is %.2f of the exact score, want 0.5", ratio)
+	}
+	if len(twice) != 1 {
+		t.Fatalf("got %q for two edits, want the entry", texts(twice))
+	}
+	if ratio := twice[0].Score / exact[0].Score; ratio < 0.24 || ratio > 0.26 {
+		t.Errorf("score with two edits is %.2f of the exact score, want 0.25", ratio)
+	}
+}
+
+func TestSuggestTypesKeptApart(t *testing.T) {
+	idx := NewIndex(DefaultOptions())
+	now := time.Now()
+	idx.Load([]Entry{
+		{Text: "Pacman", Count: 3, LastSeen: now},
+		{Text: "pacman", Count: 2, LastSeen: now},
+		{Text: "Pacman", Type: TypePage, URL: "https://wiki.archlinux.org/title/Pacman", Count: 8, LastSeen: now},
+	})
+
+	got := idx.Suggest("pacman", 10)
+	if len(got) != 2 {
+		t.Fatalf("got %d suggestions, want the query and the page: %+v", len(got), got)
+	}
+	if got[0].Type != TypePage || got[0].URL == "" {
+		t.Errorf("first suggestion %+v, want the page", got[0])
+	}
+	if got[1].Type != TypeQuery || got[1].Count != 5 {
+		t.Errorf("second suggestion %+v, want the query with both counts", got[1])
+	}
+}
+
+func TestTopKPerNode(t *testing.T) {
+	opts := DefaultOptions()
+	opts.TopK = 2
+	idx := NewIndex(opts)
+	now := time.Now()
+	idx.Load([]Entry{
+		{Text: "alpha one", Count: 1, LastSeen: now},
+		{Text: "alpha two", Count: 2, LastSeen: now},
+		{Text: "alpha three", Count: 3, LastSeen: now},
+		{Text: "alpha four", Count: 4, LastSeen: now},
+	})
+
+	if got, want := texts(idx.Suggest("al", 10)), []string{"alpha four", "alpha three"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want the %d best %q", got, opts.TopK, want)
+	}
+
+	// Searching an entry raises its rank until it is among the best again
+	for i := 0; i < 20; i++ {
+		idx.Record("alpha one")
+	}
+	if got, want := texts(idx.Suggest("al", 10)), []string{"alpha one", "alpha four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after recording got %q, want %q", got, want)
+	}
+}
+
+func TestMaxEditsFor(t *testing.T) {
+	tests := []struct {
+		length int
+		want   int
+	}{
+		{0, 0},
+		{2, 0},
+		{3, 1},
+		{7, 1},
+		{8, 2},
+		{40, 2},
+	}
+	for _, tt := range tests {
+		if got := maxEditsFor(tt.length); got != tt.want {
+			t.Errorf("maxEditsFor(%d) = %d, want %d", tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestRecordMaxQueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxQueries int
+		load       []Entry
+		record     []string
+		want       map[string]int // count per query; absent queries must not be suggested
+	}{
+		{
+			name:       "unlimited",
+			maxQueries: 0,
+			load:       []Entry{{Text: "alpha", Count: 1}},
+			record:     []string{"bravo", "charlie"},
+			want:       map[string]int{"alpha": 1, "bravo": 1, "charlie": 1},
+		},
+		{
+			name:       "room for one more",
+			maxQueries: 2,
+			load:       []Entry{{Text: "alpha", Count: 1}},
+			record:     []string{"bravo", "charlie"},
+			want:       map[string]int{"alpha": 1, "bravo": 1, "charlie": 0},
+		},
+		{
+			name:       "known queries still counted at the cap",
+			maxQueries: 2,
+			load:       []Entry{{Text: "alpha", Count: 1}, {Text: "bravo", Count: 1}},
+			record:     []string{"charlie", "Alpha", "alpha"},
+			want:       map[string]int{"alpha": 3, "bravo": 1, "charlie": 0},
+		},
+		{
+			name:       "pages do not count towards the cap",
+			maxQueries: 1,
+			load:       []Entry{{Text: "Pacman", Type: TypePage, Count: 8}},
+			record:     []string{"bravo", "charlie"},
+			want:       map[string]int{"bravo": 1, "charlie": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.MaxQueries = tt.maxQueries
+			idx := NewIndex(opts)
+			now := time.Now()
+			for i := range tt.load {
+				tt.load[i].LastSeen = now
+			}
+			idx.Load(tt.load)
+			for _, text := range tt.record {
+				idx.Record(text)
+			}
+
+			for text, want := range tt.want {
+				got := 0
+				for _, s := range idx.Suggest(text, 10) {
+					if s.Type == TypeQuery && strings.EqualFold(s.Text, text) && !s.Fuzzy {
+						got = s.Count
+					}
+				}
+				if got != want {
+					t.Errorf("count of %q = %d, want %d", text, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadResetsQueryCap(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MaxQueries = 1
+	idx := NewIndex(opts)
+	idx.Load([]Entry{{Text: "alpha", Count: 1, LastSeen: time.Now()}})
+	idx.Record("bravo")
+	if idx.Len() != 1 {
+		t.Fatalf("Len = %d at the cap, want 1", idx.Len())
+	}
+
+	idx.Load(nil)
+	idx.Record("bravo")
+	if idx.Len() != 1 || len(idx.Suggest("bravo", 1)) != 1 {
+		t.Errorf("Record after Load did not add the query")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"  Pacman  ", "pacman"},
+		{"Failed\tto   START\n unit", "failed to start unit"},
+		{"ÜBER Ärger", "über ärger"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// benchmarkIndex holds as many entries as a busy deployment keeps: the
+// configured query cap plus the wiki's pages, sections and error patterns
+func benchmarkIndex(b *testing.B) *Index {
+	b.Helper()
+	words := strings.Fields(`pacman systemd nvidia kernel grub boot failed error
+		unable to start unit service mount network wifi bluetooth audio xorg wayland
+		keyring signature corrupted package database lock timeout module driver
+		firmware suspend resume display manager login shell permission denied`)
+
+	rng := rand.New(rand.NewSource(1))
+	now := time.Now()
+	entries := make([]Entry, 0, 50000)
+	for i := 0; i < cap(entries); i++ {
+		n := 2 + rng.Intn(5)
+		parts := make([]string, n)
+		for j := range parts {
+			parts[j] = words[rng.Intn(len(words))]
+		}
+		typ := TypeQuery
+		switch i % 10 {
+		case 0:
+			typ = TypePage
+		case 1, 2:
+			typ = TypeSection
+		case 3:
+			typ = TypeError
+		}
+		entries = append(entries, Entry{
+			Text:     fmt.Sprintf("%s %d", strings.Join(parts, " "), i),
+			Type:     typ,
+			Count:    1 + rng.Intn(100),
+			LastSeen: now.Add(-time.Duration(rng.Intn(90*24)) * time.Hour),
+		})
+	}
+
+	idx := NewIndex(DefaultOptions())
+	idx.Load(entries)
+	return idx
+}
+
+// BenchmarkSuggest covers the lookups behind every keystroke, which have to
+// stay within single-digit milliseconds; typo tolerance is the costly path
+func BenchmarkSuggest(b *testing.B) {
+	idx := benchmarkIndex(b)
+	prefixes := []struct {
+		name   string
+		prefix string
+	}{
+		{"short", "pa"},
+		{"prefix", "pacman key"},
+		{"word start", "failed"},
+		{"one typo", "pacmna"},
+		{"two typos", "systemf unot"},
+		{"miss", "zzzzzzzzzz"},
+	}
+
+	for _, p := range prefixes {
+		b.Run(p.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				idx.Suggest(p.prefix, 10)
+			}
+		})
+	}
+}
+
+func BenchmarkRecord(b *testing.B) {
+	idx := benchmarkIndex(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Record("pacman failed to commit transaction")
+	}
+}
@@ -0,0 +1,156 @@
+// backend/internal/suggest/loader.go
+package suggest
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Ayash-Bera/ophelia/backend/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Loader returns the entries an index should be rebuilt from
+type Loader func(ctx context.Context) ([]Entry, error)
+
+// PopularQueryLoader loads the most searched queries from popular_queries
+func PopularQueryLoader(repo models.PopularQueryRepository, limit int) Loader {
+	return func(ctx context.Context) ([]Entry, error) {
+		queries, err := repo.GetTop(limit)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]Entry, 0, len(queries))
+		for _, q := range queries {
+			entries = append(entries, Entry{
+				Text:     q.QueryText,
+				Count:    q.SearchCount,
+				LastSeen: q.LastSearched,
+			})
+		}
+		return entries, nil
+	}
+}
+
+// Weights given to wiki content entries, which have no search counts of
+// their own. Pages rank above the error patterns and headings they contain.
+const (
+	pageWeight    = 8
+	errorWeight   = 3
+	sectionWeight = 2
+)
+
+// ContentLoader loads page titles, section headings and error patterns from
+// the active wiki pages, so suggestions work before anyone has searched
+func ContentLoader(repo models.ContentMetadataRepository) Loader {
+	return func(ctx context.Context) ([]Entry, error) {
+		pages, err := repo.GetActiveOutlines()
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []Entry
+		for _, page := range pages {
+			seen := page.UpdatedAt
+			if page.LastCrawled != nil {
+				seen = *page.LastCrawled
+			}
+			title := displayTitle(page.WikiPageTitle)
+
+			entries = append(entries, Entry{
+				Text:     title,
+				Type:     TypePage,
+				URL:      page.PageURL,
+				Count:    pageWeight,
+				LastSeen: seen,
+			})
+
+			for _, section := range page.Sections {
+				entries = append(entries, Entry{
+					Text:     section.SectionTitle,
+					Type:     TypeSection,
+					URL:      sectionURL(page.PageURL, section.SectionTitle),
+					Page:     title,
+					Count:    sectionWeight,
+					LastSeen: seen,
+				})
+			}
+
+			for _, pattern := range page.ErrorPatterns {
+				entries = append(entries, Entry{
+					Text:     pattern,
+					Type:     TypeError,
+					Count:    errorWeight,
+					LastSeen: seen,
+				})
+			}
+		}
+		return entries, nil
+	}
+}
+
+// Combine merges the entries of several loaders into one rebuild
+func Combine(loaders ...Loader) Loader {
+	return func(ctx context.Context) ([]Entry, error) {
+		var entries []Entry
+		for _, load := range loaders {
+			loaded, err := load(ctx)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, loaded...)
+		}
+		return entries, nil
+	}
+}
+
+// displayTitle turns a wiki title such as "General_troubleshooting" into
+// the form readers see on the page
+func displayTitle(title string) string {
+	return strings.ReplaceAll(title, "_", " ")
+}
+
+// sectionURL links to a section the way MediaWiki builds heading anchors
+func sectionURL(pageURL, sectionTitle string) string {
+	if pageURL == "" {
+		return ""
+	}
+	return pageURL + "#" + strings.ReplaceAll(strings.TrimSpace(sectionTitle), " ", "_")
+}
+
+// Refresh rebuilds the index from load immediately and then on every
+// interval until ctx is done. Searches recorded between rebuilds are kept
+// current by Record.
+func (idx *Index) Refresh(ctx context.Context, load Loader, interval time.Duration, logger *logrus.Logger) {
+	rebuild := func() {
+		start := time.Now()
+		entries, err := load(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Failed to load suggestion index")
+			return
+		}
+		idx.Load(entries)
+		logger.WithFields(logrus.Fields{
+			"entries":  len(entries),
+			"build_ms": time.Since(start).Milliseconds(),
+		}).Info("Suggestion index rebuilt")
+	}
+
+	rebuild()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rebuild()
+		}
+	}
+}
@@ -0,0 +1,10 @@
+-- Reverts 001_initial_schema.sql, dropping every table it created
+-- Migration: 001_initial_schema.down.sql
+
+DROP TABLE IF EXISTS system_health;
+DROP TABLE IF EXISTS wiki_sections;
+DROP TABLE IF EXISTS popular_queries;
+DROP TABLE IF EXISTS search_analytics;
+DROP TABLE IF EXISTS content_metadata;
+DROP TABLE IF EXISTS user_feedback;
+DROP TABLE IF EXISTS search_queries;
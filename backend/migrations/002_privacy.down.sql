@@ -0,0 +1,8 @@
+-- Reverts 002_privacy.sql
+-- Migration: 002_privacy.down.sql
+
+DROP INDEX IF EXISTS idx_user_feedback_session;
+DROP INDEX IF EXISTS idx_user_feedback_created;
+
+-- Hashed addresses are not valid INET values and are dropped
+ALTER TABLE search_queries ALTER COLUMN ip_address TYPE INET USING CASE WHEN ip_address ~ '^[0-9a-fA-F.:/]+$' AND ip_address ~ '[.:]' THEN ip_address::INET END;
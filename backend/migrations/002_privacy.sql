@@ -0,0 +1,10 @@
+-- Privacy controls for search analytics
+-- Migration: 002_privacy.sql
+
+-- IP addresses may be stored truncated or as a salted hash, which does not fit INET.
+-- host() drops the /32 that a plain cast would add to existing addresses.
+ALTER TABLE search_queries ALTER COLUMN ip_address TYPE TEXT USING host(ip_address);
+
+-- Retention runs delete by age and by session
+CREATE INDEX IF NOT EXISTS idx_user_feedback_created ON user_feedback(created_at);
+CREATE INDEX IF NOT EXISTS idx_user_feedback_session ON user_feedback(user_session);
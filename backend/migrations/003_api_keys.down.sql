@@ -0,0 +1,5 @@
+-- Reverts 003_api_keys.sql
+-- Migration: 003_api_keys.down.sql
+
+DROP TABLE IF EXISTS api_key_usage;
+DROP TABLE IF EXISTS api_keys;
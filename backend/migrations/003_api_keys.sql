@@ -0,0 +1,34 @@
+-- API keys with scopes and quotas, and per-key usage for internal billing
+-- Migration: 003_api_keys.sql
+
+-- Keys are stored as a SHA-256 hash; the prefix identifies a key in listings
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    owner VARCHAR(255),
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) UNIQUE NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    rate_per_minute INTEGER DEFAULT 0,
+    daily_quota INTEGER DEFAULT 0,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Requests and quota units per key, day and route
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    id SERIAL PRIMARY KEY,
+    api_key_id INTEGER NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    requests INTEGER DEFAULT 0,
+    units INTEGER DEFAULT 0,
+    errors INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_usage_key_day_route ON api_key_usage(api_key_id, day, route);
+CREATE INDEX IF NOT EXISTS idx_api_key_usage_day ON api_key_usage(day);
@@ -0,0 +1,9 @@
+-- Reverts 004_align_models.sql. The foreign keys keep ON DELETE CASCADE,
+-- as 001 defines them.
+-- Migration: 004_align_models.down.sql
+
+ALTER TABLE wiki_sections ALTER COLUMN content_metadata_id DROP NOT NULL;
+ALTER TABLE user_feedback ALTER COLUMN query_id DROP NOT NULL;
+ALTER TABLE content_metadata DROP COLUMN IF EXISTS updated_at;
+
+INSERT INTO system_health (service_name, status, response_time_ms) VALUES ('nats', 'healthy', 0);
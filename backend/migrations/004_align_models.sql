@@ -0,0 +1,27 @@
+-- Brings databases created by either path in line with the models, now that
+-- the SQL migrations are the only schema source and GORM no longer
+-- auto-migrates at startup
+-- Migration: 004_align_models.sql
+
+-- The service has no NATS dependency and never reports on it
+DELETE FROM system_health WHERE service_name = 'nats';
+
+-- GORM sets updated_at on every save; only AutoMigrate used to add it
+ALTER TABLE content_metadata ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT NOW();
+
+-- Feedback and sections always belong to a row; any without one are unreachable
+DELETE FROM user_feedback WHERE query_id IS NULL;
+ALTER TABLE user_feedback ALTER COLUMN query_id SET NOT NULL;
+DELETE FROM wiki_sections WHERE content_metadata_id IS NULL;
+ALTER TABLE wiki_sections ALTER COLUMN content_metadata_id SET NOT NULL;
+
+-- Where AutoMigrate created the tables first, its foreign keys lack the
+-- ON DELETE CASCADE of 001; recreate them under the names 001 gives them
+ALTER TABLE user_feedback DROP CONSTRAINT IF EXISTS fk_search_queries_feedback;
+ALTER TABLE user_feedback DROP CONSTRAINT IF EXISTS user_feedback_query_id_fkey;
+ALTER TABLE user_feedback ADD CONSTRAINT user_feedback_query_id_fkey
+    FOREIGN KEY (query_id) REFERENCES search_queries(id) ON DELETE CASCADE;
+ALTER TABLE wiki_sections DROP CONSTRAINT IF EXISTS fk_content_metadata_sections;
+ALTER TABLE wiki_sections DROP CONSTRAINT IF EXISTS wiki_sections_content_metadata_id_fkey;
+ALTER TABLE wiki_sections ADD CONSTRAINT wiki_sections_content_metadata_id_fkey
+    FOREIGN KEY (content_metadata_id) REFERENCES content_metadata(id) ON DELETE CASCADE;
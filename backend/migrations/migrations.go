@@ -0,0 +1,24 @@
+// backend/migrations/migrations.go
+
+// Package migrations holds the versioned SQL schema. The files are embedded
+// into every binary that migrates, so the schema ships with the code that
+// expects it.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+//go:embed *.sql
+var embedded embed.FS
+
+// Source returns the migrations to run: those in dir when it is set, for
+// trying out a migration without a rebuild, and the embedded ones otherwise
+func Source(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
+	}
+	return embedded
+}
@@ -0,0 +1,35 @@
+// backend/pkg/utils/apikey.go
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// apiKeyPrefix marks Ophelia API keys so they are easy to spot in leaks
+const apiKeyPrefix = "oph_"
+
+// GenerateAPIKey returns a new random API key secret
+func GenerateAPIKey() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(bytes), nil
+}
+
+// HashAPIKey returns the hash stored in place of an API key secret. Keys
+// are long random strings, so a plain SHA-256 is enough.
+func HashAPIKey(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// APIKeyDisplayPrefix returns the start of a secret, shown in key listings
+func APIKeyDisplayPrefix(secret string) string {
+	if len(secret) <= 12 {
+		return secret
+	}
+	return secret[:12]
+}
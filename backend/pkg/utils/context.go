@@ -0,0 +1,25 @@
+// backend/pkg/utils/context.go
+package utils
+
+import "context"
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID stores the request ID in the context so that code below the
+// HTTP layer (services, clients, repositories) can tag its work with it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in the context, if any
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
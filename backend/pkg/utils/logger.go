@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -8,6 +9,8 @@ import (
 
 var Logger *logrus.Logger
 
+type loggerKey struct{}
+
 func InitLogger() {
 	Logger = logrus.New()
 	
@@ -39,4 +42,29 @@ func GetLogger() *logrus.Logger {
 		InitLogger()
 	}
 	return Logger
-}
\ No newline at end of file
+}
+
+// WithLogger stores a request-scoped log entry in the context
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, entry)
+}
+
+// LoggerFromContext returns the request-scoped entry stored in ctx. Outside a
+// request it falls back to the given logger, still tagged with the request ID
+// when the context carries one.
+func LoggerFromContext(ctx context.Context, fallback *logrus.Logger) *logrus.Entry {
+	if ctx != nil {
+		if entry, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok && entry != nil {
+			return entry
+		}
+	}
+
+	if fallback == nil {
+		fallback = GetLogger()
+	}
+	entry := logrus.NewEntry(fallback)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		entry = entry.WithField("request_id", requestID)
+	}
+	return entry
+}
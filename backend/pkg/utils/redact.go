@@ -0,0 +1,99 @@
+// backend/pkg/utils/redact.go
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Redaction levels for query text and IP addresses written to logs
+const (
+	RedactNone    = "none"    // log values as-is
+	RedactPartial = "partial" // keep enough to correlate (query prefix, IP network)
+	RedactFull    = "full"    // keep only a short fingerprint
+)
+
+var (
+	redactMu         sync.RWMutex
+	queryRedactLevel = RedactNone
+	ipRedactLevel    = RedactPartial
+)
+
+// SetRedaction configures how query text and IPs are redacted in logs.
+// Unknown levels fall back to full redaction.
+func SetRedaction(queryLevel, ipLevel string) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	queryRedactLevel = normalizeRedactLevel(queryLevel)
+	ipRedactLevel = normalizeRedactLevel(ipLevel)
+}
+
+// RedactsQueries reports whether query text is redacted at all
+func RedactsQueries() bool {
+	redactMu.RLock()
+	defer redactMu.RUnlock()
+	return queryRedactLevel != RedactNone
+}
+
+// RedactQuery returns query text suitable for logging at the configured level
+func RedactQuery(query string) string {
+	redactMu.RLock()
+	level := queryRedactLevel
+	redactMu.RUnlock()
+
+	switch level {
+	case RedactNone:
+		return query
+	case RedactPartial:
+		const keep = 16
+		runes := []rune(query)
+		if len(runes) <= keep {
+			return fmt.Sprintf("%s [%s]", string(runes), fingerprint(query))
+		}
+		return fmt.Sprintf("%s… (%d chars) [%s]", string(runes[:keep]), len(runes), fingerprint(query))
+	default:
+		return fmt.Sprintf("[%d chars %s]", len([]rune(query)), fingerprint(query))
+	}
+}
+
+// RedactIP returns an IP address suitable for logging at the configured level
+func RedactIP(ip string) string {
+	redactMu.RLock()
+	level := ipRedactLevel
+	redactMu.RUnlock()
+
+	switch level {
+	case RedactNone:
+		return ip
+	case RedactPartial:
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return fingerprint(ip)
+		}
+		if v4 := parsed.To4(); v4 != nil {
+			return v4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+		}
+		return parsed.Mask(net.CIDRMask(48, 128)).String() + "/48"
+	default:
+		return fingerprint(ip)
+	}
+}
+
+func normalizeRedactLevel(level string) string {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case RedactNone:
+		return RedactNone
+	case RedactPartial:
+		return RedactPartial
+	default:
+		return RedactFull
+	}
+}
+
+// fingerprint is a short stable hash that lets log lines be correlated
+// without revealing the value
+func fingerprint(value string) string {
+	return MD5Hash(value)[:8]
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 // GenerateSessionID generates a session ID based on input string
@@ -16,6 +18,26 @@ func GenerateSessionID(input string) string {
 	return hex.EncodeToString(hash[:])[:16] // Return first 16 characters
 }
 
+// MinClientSessionIDLength is the shortest X-Session-ID accepted as proof of
+// ownership. Derived session IDs are 16 characters, so they never qualify.
+const MinClientSessionIDLength = 32
+
+// IsClientSessionID reports whether id is long enough to be a random ID
+// issued by the client rather than one derived from IP and User-Agent,
+// which anyone who knows those two values can recompute
+func IsClientSessionID(id string) bool {
+	return len(id) >= MinClientSessionIDLength
+}
+
+// SessionFromRequest returns the client's session ID from the X-Session-ID
+// header, or a fingerprint of IP and User-Agent when the header is absent
+func SessionFromRequest(c *gin.Context) string {
+	if session := c.GetHeader("X-Session-ID"); session != "" {
+		return session
+	}
+	return GenerateSessionID(c.ClientIP() + c.GetHeader("User-Agent"))
+}
+
 // MD5Hash generates MD5 hash of input string
 func MD5Hash(input string) string {
 	hash := md5.Sum([]byte(input))
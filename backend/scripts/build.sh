@@ -31,4 +31,6 @@ echo "Usage:"
 echo "  ./dist/arch-search-server                    # Start the API server"
 echo "  ./dist/arch-search-seeder --help             # See seeder options"
 echo "  ./dist/arch-search-seeder --dry-run          # Test without uploading"
-echo "  ./dist/arch-search-seeder --limit 3          # Process only 3 pages"
\ No newline at end of file
+echo "  ./dist/arch-search-seeder --limit 3          # Process only 3 pages"
+echo "  ./dist/arch-search-server migrate status     # Show applied and pending migrations"
+echo "  ./dist/arch-search-server seed --limit 3     # The server binary also runs the seeder"
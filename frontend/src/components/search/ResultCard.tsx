@@ -2,7 +2,7 @@
 'use client';
 
 import { useState } from 'react';
-import { ExternalLink, ThumbsUp, ThumbsDown, Copy, Check } from 'lucide-react';
+import { ExternalLink, ThumbsUp, ThumbsDown, Copy, Check, AlertTriangle } from 'lucide-react';
 import { SearchResult } from '@/lib/types';
 
 interface ResultCardProps {
@@ -95,6 +95,34 @@ export default function ResultCard({ result, onFeedback }: ResultCardProps) {
                     </div>
                 </div>
 
+                {/* Risky commands flagged by the server */}
+                {result.warnings && result.warnings.length > 0 && (
+                    <div className="mb-4 p-3 bg-red-50 dark:bg-red-900/10 border border-red-200 dark:border-red-900/40 rounded-md">
+                        <h4 className="flex items-center gap-1.5 text-sm font-medium text-red-800 dark:text-red-400 mb-2">
+                            <AlertTriangle className="h-4 w-4" />
+                            Check before running
+                        </h4>
+                        <div className="space-y-2">
+                            {result.warnings.map((warning, idx) => (
+                                <div key={idx} className="text-xs">
+                                    <code className="block font-mono text-gray-800 dark:text-gray-200 bg-white dark:bg-gray-900 px-2 py-1 rounded">
+                                        {warning.command}
+                                    </code>
+                                    <p className="mt-1 text-red-700 dark:text-red-400">
+                                        {warning.risk.replace(/_/g, ' ')}
+                                        {warning.reasons?.length ? `: ${warning.reasons.join(', ')}` : ''}
+                                    </p>
+                                    {warning.wiki_warning && (
+                                        <p className="mt-1 text-gray-700 dark:text-gray-300">
+                                            Wiki warning: {warning.wiki_warning}
+                                        </p>
+                                    )}
+                                </div>
+                            ))}
+                        </div>
+                    </div>
+                )}
+
                 {/* Command extraction (if content contains commands) */}
                 {result.content.match(/`[^`]+`|sudo \w+|\$ \w+/g) && (
                     <div className="mb-4 p-3 bg-gray-50 dark:bg-gray-800 rounded-md">
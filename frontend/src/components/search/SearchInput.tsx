@@ -2,7 +2,16 @@
 'use client';
 
 import { useState, useEffect } from 'react';
-import { Search, Loader2, Terminal } from 'lucide-react';
+import { Search, Loader2, Terminal, FileText, Hash, AlertTriangle, History } from 'lucide-react';
+import { apiClient } from '@/lib/api-client';
+import { SearchSuggestion, SuggestionType } from '@/lib/types';
+
+const suggestionIcons: Record<SuggestionType, typeof Search> = {
+    query: History,
+    page: FileText,
+    section: Hash,
+    error: AlertTriangle,
+};
 
 interface SearchInputProps {
     onSearch: (query: string) => void;
@@ -13,6 +22,46 @@ interface SearchInputProps {
 export default function SearchInput({ onSearch, loading, placeholder }: SearchInputProps) {
     const [query, setQuery] = useState('');
     const [focused, setFocused] = useState(false);
+    const [suggestions, setSuggestions] = useState<SearchSuggestion[]>([]);
+
+    // Fetch suggestions while typing, debounced to one request per pause
+    useEffect(() => {
+        const prefix = query.trim();
+        if (prefix.length < 2 || prefix.includes('\n')) {
+            setSuggestions([]);
+            return;
+        }
+
+        let cancelled = false;
+        const timer = setTimeout(async () => {
+            try {
+                const results = await apiClient.getSuggestions(prefix, 6);
+                if (!cancelled) setSuggestions(results);
+            } catch {
+                if (!cancelled) setSuggestions([]);
+            }
+        }, 150);
+
+        return () => {
+            cancelled = true;
+            clearTimeout(timer);
+        };
+    }, [query]);
+
+    const selectSuggestion = (suggestion: SearchSuggestion) => {
+        setSuggestions([]);
+
+        // Pages and sections go straight to the wiki instead of running a search
+        if ((suggestion.type === 'page' || suggestion.type === 'section') && suggestion.url) {
+            window.open(suggestion.url, '_blank', 'noopener,noreferrer');
+            return;
+        }
+
+        setQuery(suggestion.query_text);
+        if (!loading) {
+            onSearch(suggestion.query_text);
+        }
+    };
 
     const handleSubmit = (e: React.FormEvent) => {
         e.preventDefault();
@@ -79,6 +128,38 @@ export default function SearchInput({ onSearch, loading, placeholder }: SearchIn
                             disabled={loading}
                         />
 
+                        {focused && suggestions.length > 0 && (
+                            <ul className="border-t border-gray-100 dark:border-gray-800">
+                                {suggestions.map((suggestion) => {
+                                    const Icon = suggestionIcons[suggestion.type] || Search;
+                                    return (
+                                        <li key={`${suggestion.type}:${suggestion.query_text}`}>
+                                            <button
+                                                type="button"
+                                                // Keep focus on the textarea so the list stays open until the click lands
+                                                onMouseDown={(e) => e.preventDefault()}
+                                                onClick={() => selectSuggestion(suggestion)}
+                                                className="w-full flex items-center gap-3 px-4 py-2 text-left hover:bg-gray-50 dark:hover:bg-gray-900 transition-colors"
+                                            >
+                                                <Icon className="h-4 w-4 flex-shrink-0 text-gray-500" />
+                                                <span className="flex-1 truncate font-mono text-sm text-gray-800 dark:text-gray-200">
+                                                    {suggestion.query_text}
+                                                </span>
+                                                {suggestion.page && (
+                                                    <span className="truncate text-xs text-gray-500">
+                                                        {suggestion.page}
+                                                    </span>
+                                                )}
+                                                <span className="text-xs uppercase tracking-wide text-gray-400">
+                                                    {suggestion.type}
+                                                </span>
+                                            </button>
+                                        </li>
+                                    );
+                                })}
+                            </ul>
+                        )}
+
                         <div className="flex items-center justify-between p-4 border-t border-gray-100 dark:border-gray-800 bg-gray-50 dark:bg-gray-900/50">
                             <div className="flex items-center gap-2 text-xs text-gray-500">
                                 <kbd className="px-2 py-1 bg-gray-200 dark:bg-gray-700 rounded text-xs font-mono">
@@ -1,5 +1,5 @@
 // frontend/src/lib/api-client.ts
-import { SearchRequest, SearchResponse, FeedbackRequest, APIResponse, SearchSuggestion } from './types';
+import { SearchRequest, SearchResponse, FeedbackRequest, APIResponse, SearchSuggestion, SearchStreamHandlers } from './types';
 
 const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080';
 
@@ -39,14 +39,10 @@ class APIClient {
         }
     }
 
-    async search(query: string): Promise<SearchResponse> {
-        const requestBody: SearchRequest = { query };
+    async search(query: string, summarize: boolean = false): Promise<SearchResponse> {
+        const requestBody: SearchRequest = { query, summarize };
 
-        const response = await this.request<APIResponse<{
-            results: SearchResult[];
-            total: number;
-            response_time: number;
-        }>>('/api/v1/search', {
+        const response = await this.request<APIResponse<SearchResponse['data']>>('/api/v1/search', {
             method: 'POST',
             body: JSON.stringify(requestBody),
         });
@@ -59,10 +55,50 @@ class APIClient {
                 results: response.data?.results || [],
                 total: response.data?.total || 0,
                 response_time: response.data?.response_time || 0,
+                computed_at: response.data?.computed_at || '',
+                freshness: response.data?.freshness || 'live',
+                summary: response.data?.summary,
             }
         };
     }
 
+    // Streams search progress over server-sent events. Call the returned
+    // function to cancel; the server stops sending once the connection closes.
+    searchStream(query: string, handlers: SearchStreamHandlers, limit?: number, summarize: boolean = false): () => void {
+        const params = new URLSearchParams({ q: query });
+        if (summarize) {
+            params.set('summarize', 'true');
+        }
+        if (limit) {
+            params.set('limit', limit.toString());
+        }
+
+        const source = new EventSource(`${this.baseURL}/api/v1/search/stream?${params}`);
+        const parse = (event: Event) => JSON.parse((event as MessageEvent).data);
+
+        source.addEventListener('analysis', (e) => handlers.onAnalysis?.(parse(e)));
+        source.addEventListener('cached', (e) => handlers.onCached?.(parse(e)));
+        source.addEventListener('local', (e) => handlers.onLocal?.(parse(e)));
+        source.addEventListener('results', (e) => handlers.onResults?.(parse(e)));
+        source.addEventListener('final', (e) => handlers.onFinal?.(parse(e)));
+        source.addEventListener('done', (e) => {
+            handlers.onDone?.(parse(e).response_time_ms);
+            source.close();
+        });
+        source.addEventListener('error', (e) => {
+            // Server-sent error events carry data; connection failures do not
+            const data = (e as MessageEvent).data;
+            if (data) {
+                handlers.onError?.(JSON.parse(data).message);
+                return;
+            }
+            handlers.onError?.('Connection lost');
+            source.close();
+        });
+
+        return () => source.close();
+    }
+
     async submitFeedback(feedback: FeedbackRequest): Promise<APIResponse> {
         return this.request<APIResponse>('/api/v1/feedback', {
             method: 'POST',
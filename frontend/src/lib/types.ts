@@ -1,6 +1,8 @@
 // frontend/src/lib/types.ts
 export interface SearchRequest {
     query: string;
+    limit?: number;
+    summarize?: boolean;
 }
 
 export interface SearchResult {
@@ -10,6 +12,16 @@ export interface SearchResult {
     url: string;
     score: number;
     relevance: 'high' | 'medium' | 'low';
+    warnings?: CommandWarning[];
+}
+
+export type CommandRisk = 'read_only' | 'modifies_system' | 'destructive' | 'data_loss';
+
+export interface CommandWarning {
+    command: string;
+    risk: CommandRisk;
+    reasons?: string[];
+    wiki_warning?: string;
 }
 
 export interface SearchResponse {
@@ -19,9 +31,20 @@ export interface SearchResponse {
         results: SearchResult[];
         total: number;
         response_time: number;
+        computed_at: string;
+        freshness: 'live' | 'fresh' | 'stale';
+        summary?: SolutionSummary;
     };
 }
 
+// Extractive digest of the top results; source fields index into sources
+export interface SolutionSummary {
+    steps: { text: string; commands: string[]; risk: CommandRisk; warnings?: CommandWarning[]; source: number }[];
+    files: { path: string; source: number }[];
+    sentences: { text: string; source: number }[];
+    sources: { title: string; url: string; page?: string }[];
+}
+
 export interface FeedbackRequest {
     query_id: number;
     feedback_type: 'helpful' | 'not_helpful' | 'partially_helpful';
@@ -35,7 +58,39 @@ export interface APIResponse<T = any> {
     error?: string;
 }
 
+export type SuggestionType = 'query' | 'page' | 'section' | 'error';
+
 export interface SearchSuggestion {
     query_text: string;
+    type: SuggestionType;
+    url?: string;
+    page?: string;
     search_count: number;
-}
\ No newline at end of file
+    last_searched: string;
+    score: number;
+    fuzzy?: boolean;
+}
+export interface QueryAnalysis {
+    query: string;
+    processed_query: string;
+    keywords: string[];
+    limit: number;
+}
+
+export interface StreamResults {
+    results: SearchResult[];
+    total: number;
+    computed_at?: string;
+    freshness?: 'live' | 'fresh' | 'stale';
+    summary?: SolutionSummary;
+}
+
+export interface SearchStreamHandlers {
+    onAnalysis?: (analysis: QueryAnalysis) => void;
+    onCached?: (results: StreamResults) => void;
+    onLocal?: (results: StreamResults) => void;
+    onResults?: (results: StreamResults) => void;
+    onFinal?: (results: StreamResults) => void;
+    onError?: (message: string) => void;
+    onDone?: (responseTimeMs: number) => void;
+}